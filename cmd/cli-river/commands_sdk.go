@@ -2,6 +2,7 @@ package main
 
 import (
     "fmt"
+    "strconv"
     "time"
 
     "github.com/abiosoft/ishell/v2"
@@ -155,6 +156,35 @@ var SdkCancelFileRequest = &ishell.Cmd{
     },
 }
 
+var SdkPauseFileRequest = &ishell.Cmd{
+    Name: "PauseFileRequest",
+    Func: func(c *ishell.Context) {
+        if err := _SDK.PauseFileRequest(fnGetString(c, "FileRequestID")); err != nil {
+            c.Println(err)
+        }
+    },
+}
+
+var SdkResumeFileRequest = &ishell.Cmd{
+    Name: "ResumeFileRequest",
+    Func: func(c *ishell.Context) {
+        if err := _SDK.ResumeFileRequest(fnGetString(c, "FileRequestID")); err != nil {
+            c.Println(err)
+        }
+    },
+}
+
+var SdkSetFileRequestPriority = &ishell.Cmd{
+    Name: "SetFileRequestPriority",
+    Func: func(c *ishell.Context) {
+        reqID := fnGetString(c, "FileRequestID")
+        priority, _ := strconv.ParseInt(fnGetString(c, "Priority (0-255)"), 10, 32)
+        if err := _SDK.SetFileRequestPriority(reqID, int32(priority)); err != nil {
+            c.Println(err)
+        }
+    },
+}
+
 var SdkDeleteAllPendingMessages = &ishell.Cmd{
     Name: "DeleteAllPendingMessages",
     Func: func(c *ishell.Context) {
@@ -172,6 +202,9 @@ var SdkRemoteLog = &ishell.Cmd{
 func init() {
     SDK.AddCmd(SdkDeleteAllPendingMessages)
     SDK.AddCmd(SdkCancelFileRequest)
+    SDK.AddCmd(SdkPauseFileRequest)
+    SDK.AddCmd(SdkResumeFileRequest)
+    SDK.AddCmd(SdkSetFileRequestPriority)
     SDK.AddCmd(SdkDeletePending)
     SDK.AddCmd(SdkConnInfo)
     SDK.AddCmd(SdkSetLogLevel)
@@ -30,6 +30,7 @@ require (
 	go.uber.org/zap v1.26.0
 	golang.org/x/mobile v0.0.0-20210527171505-7e972142eb43
 	golang.org/x/sync v0.6.0
+	golang.org/x/text v0.14.0
 	google.golang.org/protobuf v1.32.0
 )
 
@@ -138,7 +139,6 @@ require (
 	golang.org/x/mod v0.14.0 // indirect
 	golang.org/x/net v0.19.0 // indirect
 	golang.org/x/sys v0.15.0 // indirect
-	golang.org/x/text v0.14.0 // indirect
 	golang.org/x/tools v0.16.0 // indirect
 	gopkg.in/inf.v0 v0.9.1 // indirect
 	gopkg.in/ini.v1 v1.67.0 // indirect
@@ -0,0 +1,16 @@
+#!/usr/bin/env bash
+
+## The messages in this package are handled by the SDK locally and are never sent to the server, they import the
+## types of river-msg, hence the proto files of river-msg must be in the include path.
+currentWorkingDir=$(pwd)
+riverMsgDir=$(go list -m -f '{{.Dir}}' github.com/ronaksoft/river-msg)
+rm ./*.pb.go
+rm ./*.rony.go
+
+cd ./proto || exit
+goPkg='Mcore.types.proto=github.com/ronaksoft/river-msg/go/msg,Mupdates.proto=github.com/ronaksoft/river-msg/go/msg'
+protoc -I="${riverMsgDir}"/proto -I=. --go_out=paths=source_relative,"${goPkg}":.. ./*.proto
+protoc -I="${riverMsgDir}"/proto -I=. --gorony_out=paths=source_relative,"${goPkg}":.. ./*.proto
+
+cd "$currentWorkingDir" || exit
+go fmt
@@ -0,0 +1,80 @@
+// Code generated by Rony's protoc plugin; DO NOT EDIT.
+// ProtoC ver. v4.25.2
+// Rony ver. v0.12.22
+// Source: client.groups.proto
+
+package clientmsg
+
+import (
+	edge "github.com/ronaksoft/rony/edge"
+	pools "github.com/ronaksoft/rony/pools"
+	registry "github.com/ronaksoft/rony/registry"
+	protojson "google.golang.org/protobuf/encoding/protojson"
+	proto "google.golang.org/protobuf/proto"
+	sync "sync"
+)
+
+var _ = pools.Imported
+
+const C_ClientGetReadBy int64 = 1668492792
+
+type poolClientGetReadBy struct {
+	pool sync.Pool
+}
+
+func (p *poolClientGetReadBy) Get() *ClientGetReadBy {
+	x, ok := p.pool.Get().(*ClientGetReadBy)
+	if !ok {
+		x = &ClientGetReadBy{}
+	}
+
+	return x
+}
+
+func (p *poolClientGetReadBy) Put(x *ClientGetReadBy) {
+	if x == nil {
+		return
+	}
+
+	x.GroupID = 0
+	x.MessageID = 0
+
+	p.pool.Put(x)
+}
+
+var PoolClientGetReadBy = poolClientGetReadBy{}
+
+func (x *ClientGetReadBy) DeepCopy(z *ClientGetReadBy) {
+	z.GroupID = x.GroupID
+	z.MessageID = x.MessageID
+}
+
+func (x *ClientGetReadBy) Clone() *ClientGetReadBy {
+	z := &ClientGetReadBy{}
+	x.DeepCopy(z)
+	return z
+}
+
+func (x *ClientGetReadBy) Unmarshal(b []byte) error {
+	return proto.UnmarshalOptions{Merge: true}.Unmarshal(b, x)
+}
+
+func (x *ClientGetReadBy) Marshal() ([]byte, error) {
+	return proto.Marshal(x)
+}
+
+func (x *ClientGetReadBy) UnmarshalJSON(b []byte) error {
+	return protojson.Unmarshal(b, x)
+}
+
+func (x *ClientGetReadBy) MarshalJSON() ([]byte, error) {
+	return protojson.Marshal(x)
+}
+
+func (x *ClientGetReadBy) PushToContext(ctx *edge.RequestCtx) {
+	ctx.PushMessage(C_ClientGetReadBy, x)
+}
+
+func init() {
+	registry.RegisterConstructor(1668492792, "ClientGetReadBy")
+}
@@ -0,0 +1,219 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.32.0
+// 	protoc        v4.25.2
+// source: client.typing.proto
+
+package clientmsg
+
+import (
+	msg "github.com/ronaksoft/river-msg/go/msg"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ClientGetTypingUsers
+// @Function
+// @Return: ClientTypingUsers
+type ClientGetTypingUsers struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Peer *msg.InputPeer `protobuf:"bytes,1,opt,name=Peer,proto3" json:"Peer,omitempty"`
+}
+
+func (x *ClientGetTypingUsers) Reset() {
+	*x = ClientGetTypingUsers{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_client_typing_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClientGetTypingUsers) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientGetTypingUsers) ProtoMessage() {}
+
+func (x *ClientGetTypingUsers) ProtoReflect() protoreflect.Message {
+	mi := &file_client_typing_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientGetTypingUsers.ProtoReflect.Descriptor instead.
+func (*ClientGetTypingUsers) Descriptor() ([]byte, []int) {
+	return file_client_typing_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ClientGetTypingUsers) GetPeer() *msg.InputPeer {
+	if x != nil {
+		return x.Peer
+	}
+	return nil
+}
+
+// ClientTypingUsers
+type ClientTypingUsers struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Users []*msg.UpdateUserTyping `protobuf:"bytes,1,rep,name=Users,proto3" json:"Users,omitempty"`
+}
+
+func (x *ClientTypingUsers) Reset() {
+	*x = ClientTypingUsers{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_client_typing_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *ClientTypingUsers) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClientTypingUsers) ProtoMessage() {}
+
+func (x *ClientTypingUsers) ProtoReflect() protoreflect.Message {
+	mi := &file_client_typing_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClientTypingUsers.ProtoReflect.Descriptor instead.
+func (*ClientTypingUsers) Descriptor() ([]byte, []int) {
+	return file_client_typing_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *ClientTypingUsers) GetUsers() []*msg.UpdateUserTyping {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+var File_client_typing_proto protoreflect.FileDescriptor
+
+var file_client_typing_proto_rawDesc = []byte{
+	0x0a, 0x13, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x2e, 0x74, 0x79, 0x70, 0x69, 0x6e, 0x67, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x09, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x6d, 0x73, 0x67,
+	0x1a, 0x10, 0x63, 0x6f, 0x72, 0x65, 0x2e, 0x74, 0x79, 0x70, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x1a, 0x0d, 0x75, 0x70, 0x64, 0x61, 0x74, 0x65, 0x73, 0x2e, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x22, 0x3a, 0x0a, 0x14, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x47, 0x65, 0x74, 0x54, 0x79,
+	0x70, 0x69, 0x6e, 0x67, 0x55, 0x73, 0x65, 0x72, 0x73, 0x12, 0x22, 0x0a, 0x04, 0x50, 0x65, 0x65,
+	0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6d, 0x73, 0x67, 0x2e, 0x49, 0x6e,
+	0x70, 0x75, 0x74, 0x50, 0x65, 0x65, 0x72, 0x52, 0x04, 0x50, 0x65, 0x65, 0x72, 0x22, 0x40, 0x0a,
+	0x11, 0x43, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x54, 0x79, 0x70, 0x69, 0x6e, 0x67, 0x55, 0x73, 0x65,
+	0x72, 0x73, 0x12, 0x2b, 0x0a, 0x05, 0x55, 0x73, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x15, 0x2e, 0x6d, 0x73, 0x67, 0x2e, 0x55, 0x70, 0x64, 0x61, 0x74, 0x65, 0x55, 0x73,
+	0x65, 0x72, 0x54, 0x79, 0x70, 0x69, 0x6e, 0x67, 0x52, 0x05, 0x55, 0x73, 0x65, 0x72, 0x73, 0x42,
+	0x0e, 0x5a, 0x0c, 0x2e, 0x2f, 0x3b, 0x63, 0x6c, 0x69, 0x65, 0x6e, 0x74, 0x6d, 0x73, 0x67, 0x62,
+	0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_client_typing_proto_rawDescOnce sync.Once
+	file_client_typing_proto_rawDescData = file_client_typing_proto_rawDesc
+)
+
+func file_client_typing_proto_rawDescGZIP() []byte {
+	file_client_typing_proto_rawDescOnce.Do(func() {
+		file_client_typing_proto_rawDescData = protoimpl.X.CompressGZIP(file_client_typing_proto_rawDescData)
+	})
+	return file_client_typing_proto_rawDescData
+}
+
+var file_client_typing_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_client_typing_proto_goTypes = []interface{}{
+	(*ClientGetTypingUsers)(nil), // 0: clientmsg.ClientGetTypingUsers
+	(*ClientTypingUsers)(nil),    // 1: clientmsg.ClientTypingUsers
+	(*msg.InputPeer)(nil),        // 2: msg.InputPeer
+	(*msg.UpdateUserTyping)(nil), // 3: msg.UpdateUserTyping
+}
+var file_client_typing_proto_depIdxs = []int32{
+	2, // 0: clientmsg.ClientGetTypingUsers.Peer:type_name -> msg.InputPeer
+	3, // 1: clientmsg.ClientTypingUsers.Users:type_name -> msg.UpdateUserTyping
+	2, // [2:2] is the sub-list for method output_type
+	2, // [2:2] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_client_typing_proto_init() }
+func file_client_typing_proto_init() {
+	if File_client_typing_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_client_typing_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ClientGetTypingUsers); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_client_typing_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*ClientTypingUsers); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_client_typing_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_client_typing_proto_goTypes,
+		DependencyIndexes: file_client_typing_proto_depIdxs,
+		MessageInfos:      file_client_typing_proto_msgTypes,
+	}.Build()
+	File_client_typing_proto = out.File
+	file_client_typing_proto_rawDesc = nil
+	file_client_typing_proto_goTypes = nil
+	file_client_typing_proto_depIdxs = nil
+}
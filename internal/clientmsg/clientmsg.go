@@ -0,0 +1,13 @@
+// Package clientmsg keeps the messages which are only used between the UI and the SDK. They are handled by the
+// local handlers and are never sent to the server, hence they are not part of river-msg. The code is generated
+// from the proto files by build.sh.
+package clientmsg
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
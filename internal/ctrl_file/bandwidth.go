@@ -0,0 +1,85 @@
+package fileCtrl
+
+import (
+    "context"
+    "sync"
+    "time"
+
+    "github.com/juju/ratelimit"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+// bandwidthLimiter is a token bucket which is shared between all the transfers of one direction
+// (upload or download). Rate is in bytes per second and zero rate means unlimited.
+type bandwidthLimiter struct {
+    mtx    sync.RWMutex
+    rate   int64
+    bucket *ratelimit.Bucket
+}
+
+func newBandwidthLimiter(rate int64) *bandwidthLimiter {
+    l := &bandwidthLimiter{}
+    l.SetRate(rate)
+    return l
+}
+
+// SetRate changes the rate of the limiter, it could be called while transfers are running.
+func (l *bandwidthLimiter) SetRate(rate int64) {
+    l.mtx.Lock()
+    defer l.mtx.Unlock()
+    if rate < 0 {
+        rate = 0
+    }
+    if rate == l.rate && (rate == 0 || l.bucket != nil) {
+        return
+    }
+    l.rate = rate
+    if rate == 0 {
+        l.bucket = nil
+        return
+    }
+
+    // We let the bucket hold one second of traffic, so short bursts are not penalized
+    l.bucket = ratelimit.NewBucketWithRate(float64(rate), rate)
+}
+
+func (l *bandwidthLimiter) Rate() int64 {
+    l.mtx.RLock()
+    defer l.mtx.RUnlock()
+    return l.rate
+}
+
+// Wait blocks until n bytes could be transferred or ctx is done. It returns false if ctx is done before.
+func (l *bandwidthLimiter) Wait(ctx context.Context, n int64) bool {
+    l.mtx.RLock()
+    bucket := l.bucket
+    l.mtx.RUnlock()
+    if bucket == nil || n <= 0 {
+        return true
+    }
+
+    d := bucket.Take(n)
+    if d <= 0 {
+        return true
+    }
+    if ctx == nil {
+        time.Sleep(d)
+        return true
+    }
+    t := time.NewTimer(d)
+    defer t.Stop()
+    select {
+    case <-t.C:
+        return true
+    case <-ctx.Done():
+        return false
+    }
+}
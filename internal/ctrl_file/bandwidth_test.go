@@ -0,0 +1,50 @@
+package fileCtrl
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func TestBandwidthLimiter(t *testing.T) {
+	Convey("BandwidthLimiter", t, func(c C) {
+		Convey("Unlimited", func(c C) {
+			l := newBandwidthLimiter(0)
+			st := time.Now()
+			for i := 0; i < 100; i++ {
+				c.So(l.Wait(context.Background(), 1<<20), ShouldBeTrue)
+			}
+			c.So(time.Since(st), ShouldBeLessThan, 100*time.Millisecond)
+		})
+		Convey("Limited", func(c C) {
+			l := newBandwidthLimiter(100 << 10)
+			st := time.Now()
+			// The first 100KB is the burst capacity
+			for i := 0; i < 15; i++ {
+				c.So(l.Wait(context.Background(), 10<<10), ShouldBeTrue)
+			}
+			c.So(time.Since(st), ShouldBeGreaterThanOrEqualTo, 400*time.Millisecond)
+		})
+		Convey("Change Rate At Runtime", func(c C) {
+			l := newBandwidthLimiter(1 << 10)
+			c.So(l.Wait(context.Background(), 1<<10), ShouldBeTrue)
+			ctx, cf := context.WithTimeout(context.Background(), 50*time.Millisecond)
+			c.So(l.Wait(ctx, 1<<10), ShouldBeFalse)
+			cf()
+			l.SetRate(0)
+			c.So(l.Rate(), ShouldEqual, 0)
+			c.So(l.Wait(context.Background(), 1<<20), ShouldBeTrue)
+		})
+	})
+}
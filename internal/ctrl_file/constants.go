@@ -20,6 +20,9 @@ const (
 	MaxParts               = 3000
 	RetryMaxAttempts       = 25
 	RetryWaitTime          = 100 * time.Millisecond
+	VerifyMaxAttempts      = 2
+	PartRetryMaxAttempts   = 10
+	DefaultUploadWindow    = 5
 )
 
-var chunkSizesKB = []int32{256, 512}
+var chunkSizesKB = []int32{64, 128, 256, 512}
@@ -1,7 +1,9 @@
 package fileCtrl
 
 import (
+    "bytes"
     "context"
+    "hash/crc32"
     "os"
     "sync"
 
@@ -29,9 +31,13 @@ type DownloadRequest struct {
     mtx      sync.Mutex
     file     *os.File
     parts    chan int32
+    urgent   chan int32
     done     chan struct{}
+    signal   chan struct{}
     progress int64
     finished bool
+    attempts int32
+    auto     bool
 }
 
 func (d *DownloadRequest) isDownloaded(partIndex int32) bool {
@@ -45,8 +51,19 @@ func (d *DownloadRequest) isDownloaded(partIndex int32) bool {
     return false
 }
 
-func (d *DownloadRequest) addToDownloaded(partIndex int32) {
+// addToDownloaded marks the part as downloaded. The checksum of the part as it has been written to the
+// disk is saved, hence the part could be verified before the file is completed. Since the checksum is
+// calculated from the received bytes, it could only find the parts which have been damaged on the disk
+// afterwards, e.g. by an interrupted write before the app restarted.
+func (d *DownloadRequest) addToDownloaded(partIndex int32, sum uint32) {
+    _ = repo.Files.SavePartSum(d.GetID(), partIndex, sum)
     d.mtx.Lock()
+    for _, index := range d.FinishedParts {
+        if partIndex == index {
+            d.mtx.Unlock()
+            return
+        }
+    }
     d.FinishedParts = append(d.FinishedParts, partIndex)
     progress := int64(float64(len(d.FinishedParts)) / float64(d.TotalParts) * 100)
     skipOnProgress := false
@@ -55,6 +72,7 @@ func (d *DownloadRequest) addToDownloaded(partIndex int32) {
     } else {
         d.progress = progress
     }
+    d.notify()
     d.mtx.Unlock()
     saved, _ := repo.Files.SaveFileRequest(d.GetID(), &d.ClientFileRequest, true)
     if saved && !d.SkipDelegateCall && !skipOnProgress {
@@ -63,7 +81,147 @@ func (d *DownloadRequest) addToDownloaded(partIndex int32) {
 
 }
 
+// notify wakes up all the streams which are waiting for a part. It must be called while d.mtx is locked.
+func (d *DownloadRequest) notify() {
+    if d.signal != nil {
+        close(d.signal)
+    }
+    d.signal = make(chan struct{})
+}
+
+// waitSignal returns a channel which will be closed when the next part is downloaded or the request is done
+func (d *DownloadRequest) waitSignal() <-chan struct{} {
+    d.mtx.Lock()
+    defer d.mtx.Unlock()
+    if d.signal == nil {
+        d.signal = make(chan struct{})
+    }
+    return d.signal
+}
+
+// prioritize makes the part to be downloaded before the other waiting parts
+func (d *DownloadRequest) prioritize(partIndex int32) {
+    if d.urgent == nil || partIndex < 0 || partIndex >= d.TotalParts || d.isDownloaded(partIndex) {
+        return
+    }
+    select {
+    case d.urgent <- partIndex:
+    default:
+    }
+}
+
+// finish unregisters the request from the active downloads and wakes up the waiting streams
+func (d *DownloadRequest) finish() {
+    d.ctrl.activeDownloads.Delete(d.GetID())
+    d.mtx.Lock()
+    d.notify()
+    d.mtx.Unlock()
+}
+
+// partSize returns the expected number of bytes of the part, it returns 0 if the file size is not known
+func (d *DownloadRequest) partSize(partIndex int32) int32 {
+    if d.FileSize <= 0 || d.ChunkSize <= 0 {
+        return 0
+    }
+    remaining := d.FileSize - int64(partIndex)*int64(d.ChunkSize)
+    if remaining < int64(d.ChunkSize) {
+        return int32(remaining)
+    }
+    return d.ChunkSize
+}
+
+// partRange returns the offset and the size of the part in the file
+func (d *DownloadRequest) partRange(partIndex int32, fileSize int64) (int64, int64) {
+    if d.ChunkSize <= 0 {
+        return 0, fileSize
+    }
+    offset := int64(partIndex) * int64(d.ChunkSize)
+    size := int64(d.ChunkSize)
+    if offset+size > fileSize {
+        size = fileSize - offset
+    }
+    return offset, size
+}
+
+// verify checks every part of the downloaded file against the checksum which has been saved when the part was
+// written, then it checks the whole file against its known sha256 (if we know it) and records the result in
+// the repo. If the file is corrupted, it returns the parts which must be downloaded again.
+// We do not have a checksum of the parts from the server, hence the parts which have been corrupted on the
+// network are only detected by the size check of each part on receive and by the sha256 of the whole file.
+// In that case the corrupted part cannot be found, hence all the parts are returned.
+func (d *DownloadRequest) verify() ([]int32, error) {
+    err := d.file.Sync()
+    if err != nil {
+        return nil, err
+    }
+    fileInfo, err := d.file.Stat()
+    if err != nil {
+        return nil, err
+    }
+
+    var corrupted []int32
+    sums := repo.Files.GetPartSums(d.GetID())
+    for partIndex := int32(0); partIndex < d.TotalParts; partIndex++ {
+        sum, ok := sums[partIndex]
+        if !ok {
+            // The part has been downloaded by an older version which did not save the checksums
+            continue
+        }
+        offset, size := d.partRange(partIndex, fileInfo.Size())
+        b := make([]byte, size)
+        _, err = d.file.ReadAt(b, offset)
+        if err != nil || crc32.ChecksumIEEE(b) != sum {
+            corrupted = append(corrupted, partIndex)
+        }
+    }
+
+    if integrity := repo.Files.GetIntegrity(d.ClusterID, d.FileID, d.AccessHash); len(corrupted) == 0 && len(integrity.Sha256) > 0 {
+        h, err := domain.CalculateSha256(d.TempPath)
+        if err != nil {
+            return nil, err
+        }
+        if !bytes.Equal(h, integrity.Sha256) {
+            for partIndex := int32(0); partIndex < d.TotalParts; partIndex++ {
+                corrupted = append(corrupted, partIndex)
+            }
+        }
+    }
+
+    if len(corrupted) > 0 {
+        _ = repo.Files.SaveIntegrityResult(d.ClusterID, d.FileID, d.AccessHash, fileInfo.Size(), repo.FileIntegrityCorrupted)
+        return corrupted, domain.ErrCorruptedFile
+    }
+    _ = repo.Files.SaveIntegrityResult(d.ClusterID, d.FileID, d.AccessHash, fileInfo.Size(), repo.FileIntegrityVerified)
+    return nil, nil
+}
+
+// retry removes the parts from the downloaded parts and push them back to be downloaded again
+func (d *DownloadRequest) retry(parts []int32) {
+    d.mtx.Lock()
+    finishedParts := d.FinishedParts[:0]
+    for _, index := range d.FinishedParts {
+        retried := false
+        for _, partIndex := range parts {
+            if index == partIndex {
+                retried = true
+                break
+            }
+        }
+        if !retried {
+            finishedParts = append(finishedParts, index)
+        }
+    }
+    d.FinishedParts = finishedParts
+    d.progress = int64(float64(len(d.FinishedParts)) / float64(d.TotalParts) * 100)
+    d.mtx.Unlock()
+    _, _ = repo.Files.SaveFileRequest(d.GetID(), &d.ClientFileRequest, true)
+    for _, partIndex := range parts {
+        d.parts <- partIndex
+    }
+}
+
 func (d *DownloadRequest) cancel(err error) {
+    d.finish()
     if !d.SkipDelegateCall {
         d.ctrl.onCancel(d.GetID(), d.ClusterID, d.FileID, int64(d.AccessHash), err != nil, d.PeerID)
     }
@@ -71,6 +229,7 @@ func (d *DownloadRequest) cancel(err error) {
 }
 
 func (d *DownloadRequest) complete() {
+    d.finish()
     if !d.SkipDelegateCall {
         d.ctrl.onCompleted(d.GetID(), d.ClusterID, d.FileID, int64(d.AccessHash), d.FilePath, d.PeerID)
     }
@@ -83,6 +242,15 @@ func (d *DownloadRequest) GetID() string {
 
 func (d *DownloadRequest) Prepare() error {
     logger.Info("prepare DownloadRequest", zap.String("ReqID", d.GetID()))
+    // Auto downloads could be waiting in the queue or restored after restart, meanwhile the device could
+    // have been connected to another network type, hence we check the rules again before starting them.
+    if repo.Files.IsAutoFileRequest(d.GetID()) {
+        clientFile, err := repo.Files.Get(d.ClusterID, d.FileID, d.AccessHash)
+        if err != nil || !d.ctrl.CanAutoDownload(clientFile) {
+            d.cancel(domain.ErrAutoDownloadDisabled)
+            return domain.ErrAutoDownloadDisabled
+        }
+    }
     // Check temp file stat and if it does not exists, we create it
     _, err := os.Stat(d.TempPath)
     if err != nil {
@@ -127,8 +295,10 @@ func (d *DownloadRequest) Prepare() error {
         d.FinishedParts = d.FinishedParts[:0]
     }
 
-    // Prepare Channels to active the system dynamics
-    d.parts = make(chan int32, d.TotalParts)
+    // Prepare Channels to active the system dynamics. Parts could be pushed twice if they are prioritized
+    // by a stream, so we make room for them.
+    d.parts = make(chan int32, 2*d.TotalParts)
+    d.urgent = make(chan int32, d.TotalParts)
     d.done = make(chan struct{}, 1)
     for partIndex := int32(0); partIndex < d.TotalParts; partIndex++ {
         if d.isDownloaded(partIndex) {
@@ -137,6 +307,8 @@ func (d *DownloadRequest) Prepare() error {
         d.parts <- partIndex
     }
 
+    d.ctrl.activeDownloads.Store(d.GetID(), d)
+
     logger.Debug("Download Prepared",
         zap.String("ID", d.GetID()),
         zap.Int32("TotalParts", d.TotalParts),
@@ -152,15 +324,35 @@ func (d *DownloadRequest) NextAction() executor.Action {
         return nil
     }
 
-    // Wait for next part, or return nil if we finished
+    // Parts requested by streams are served first
     select {
-    case partID := <-d.parts:
+    case partID := <-d.urgent:
+        if !d.isDownloaded(partID) {
+            return &DownloadAction{
+                id:  partID,
+                req: d,
+            }
+        }
+    default:
+    }
+
+    // Wait for next part, or return nil if we finished
+    for {
+        var partID int32
+        select {
+        case partID = <-d.urgent:
+        case partID = <-d.parts:
+        case <-d.done:
+            return nil
+        }
+        // The part might have been already downloaded by being prioritized
+        if d.isDownloaded(partID) {
+            continue
+        }
         return &DownloadAction{
             id:  partID,
             req: d,
         }
-    case <-d.done:
-        return nil
     }
 }
 
@@ -176,10 +368,34 @@ func (d *DownloadRequest) ActionDone(id int32) {
         if d.finished {
             return
         }
+
+        // Verify the file before moving it to its final path, if it is corrupted we try to download it again
+        // and if it is still corrupted we give up.
+        corrupted, err := d.verify()
+        if err != nil {
+            d.attempts++
+            logger.Warn("got error on verifying DownloadRequest",
+                zap.String("ReqID", d.GetID()),
+                zap.Int32("Attempts", d.attempts),
+                zap.Int32s("Corrupted", corrupted),
+                zap.Error(err),
+            )
+            if d.attempts < VerifyMaxAttempts && len(corrupted) > 0 {
+                d.retry(corrupted)
+                return
+            }
+            d.finished = true
+            d.done <- struct{}{}
+            _ = d.file.Close()
+            _ = os.Remove(d.TempPath)
+            d.cancel(err)
+            return
+        }
+
         d.finished = true
         d.done <- struct{}{}
         _ = d.file.Close()
-        err := os.Rename(d.TempPath, d.FilePath)
+        err = os.Rename(d.TempPath, d.FilePath)
         if err != nil {
             _ = os.Remove(d.TempPath)
             d.cancel(err)
@@ -214,6 +430,17 @@ func (a *DownloadAction) ID() int32 {
 
 func (a *DownloadAction) Do(ctx context.Context) {
     offset := a.id * a.req.ChunkSize
+
+    // Wait for our share of the download bandwidth
+    limit := int64(a.req.partSize(a.id))
+    if limit == 0 {
+        limit = DefaultChunkSize
+    }
+    if !a.req.ctrl.downloadLimiter.Wait(ctx, limit) {
+        a.req.parts <- a.id
+        return
+    }
+
     ctx, cf := context.WithTimeout(ctx, domain.HttpRequestTimeout)
     defer cf()
     req := &msg.FileGet{
@@ -246,6 +473,20 @@ func (a *DownloadAction) Do(ctx context.Context) {
                     a.req.parts <- a.id
                     return
                 }
+                // If we know the file size, we make sure the part is complete, otherwise we download
+                // only this part again
+                if partSize := a.req.partSize(a.id); partSize > 0 {
+                    if int32(len(file.Bytes)) < partSize {
+                        logger.Warn("received incomplete part, will retry ...",
+                            zap.Int32("Offset", offset),
+                            zap.Int("Byte", len(file.Bytes)),
+                            zap.Int32("Expected", partSize),
+                        )
+                        a.req.parts <- a.id
+                        return
+                    }
+                    file.Bytes = file.Bytes[:partSize]
+                }
                 _, err = a.req.file.WriteAt(file.Bytes, int64(offset))
                 if err != nil {
                     logger.Error("couldn't write to file, will retry...",
@@ -256,7 +497,7 @@ func (a *DownloadAction) Do(ctx context.Context) {
                     a.req.parts <- a.id
                     return
                 }
-                a.req.addToDownloaded(a.id)
+                a.req.addToDownloaded(a.id, crc32.ChecksumIEEE(file.Bytes))
             default:
                 a.req.parts <- a.id
                 return
@@ -0,0 +1,139 @@
+package fileCtrl
+
+import (
+    "bytes"
+    "crypto/sha256"
+    "fmt"
+    "hash/crc32"
+    "os"
+    "path/filepath"
+    "testing"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+// newTestDownload prepares a download request whose parts are all written to the temp file, as if they have
+// been downloaded. The repo is initialized by file_test.go.
+func newTestDownload(content []byte, chunkSize int32) *DownloadRequest {
+    fileID := domain.RandomInt63()
+    _ = os.MkdirAll("./_hdd", os.ModePerm)
+    filePath := filepath.Join("./_hdd", fmt.Sprintf("download-%d", fileID))
+    d := &DownloadRequest{
+        ClientFileRequest: msg.ClientFileRequest{
+            ClusterID:        1,
+            FileID:           fileID,
+            AccessHash:       1,
+            FileSize:         int64(len(content)),
+            ChunkSize:        chunkSize,
+            FilePath:         filePath,
+            TempPath:         filePath + ".tmp",
+            SkipDelegateCall: true,
+        },
+        ctrl: &Controller{},
+    }
+    _, _ = repo.Files.SaveFileRequest(d.GetID(), &d.ClientFileRequest, false)
+    So(d.Prepare(), ShouldBeNil)
+
+    // drain the parts which have been queued by Prepare
+    for len(d.parts) > 0 {
+        <-d.parts
+    }
+    for partIndex := int32(0); partIndex < d.TotalParts; partIndex++ {
+        offset, size := d.partRange(partIndex, d.FileSize)
+        part := content[offset : offset+size]
+        _, err := d.file.WriteAt(part, offset)
+        So(err, ShouldBeNil)
+        d.addToDownloaded(partIndex, crc32.ChecksumIEEE(part))
+    }
+    return d
+}
+
+func queuedParts(d *DownloadRequest) []int32 {
+    var parts []int32
+    for len(d.parts) > 0 {
+        parts = append(parts, <-d.parts)
+    }
+    return parts
+}
+
+func TestDownloadVerify(t *testing.T) {
+    content := bytes.Repeat([]byte("0123456789abcdef"), 640)
+    Convey("Download Verify", t, func(c C) {
+        Convey("Intact File", func(c C) {
+            d := newTestDownload(content, 1024)
+            h := sha256.Sum256(content)
+            c.So(repo.Files.SaveSha256(d.ClusterID, d.FileID, d.AccessHash, h[:]), ShouldBeNil)
+
+            corrupted, err := d.verify()
+            c.So(err, ShouldBeNil)
+            c.So(corrupted, ShouldBeEmpty)
+            integrity := repo.Files.GetIntegrity(d.ClusterID, d.FileID, d.AccessHash)
+            c.So(integrity.Status, ShouldEqual, repo.FileIntegrityVerified)
+            c.So(integrity.Size, ShouldEqual, len(content))
+        })
+        Convey("Retry Only Parts Damaged On Disk", func(c C) {
+            d := newTestDownload(content, 1024)
+            c.So(d.TotalParts, ShouldEqual, 10)
+            _, err := d.file.WriteAt([]byte("corrupted"), 2*1024+10)
+            c.So(err, ShouldBeNil)
+            _, err = d.file.WriteAt([]byte("corrupted"), 9*1024)
+            c.So(err, ShouldBeNil)
+
+            corrupted, err := d.verify()
+            c.So(err, ShouldEqual, domain.ErrCorruptedFile)
+            c.So(corrupted, ShouldResemble, []int32{2, 9})
+            integrity := repo.Files.GetIntegrity(d.ClusterID, d.FileID, d.AccessHash)
+            c.So(integrity.Status, ShouldEqual, repo.FileIntegrityCorrupted)
+
+            d.retry(corrupted)
+            c.So(queuedParts(d), ShouldResemble, []int32{2, 9})
+            c.So(d.FinishedParts, ShouldHaveLength, 8)
+            c.So(d.isDownloaded(2), ShouldBeFalse)
+            c.So(d.isDownloaded(9), ShouldBeFalse)
+            c.So(d.isDownloaded(3), ShouldBeTrue)
+
+            // download the parts again
+            for _, partIndex := range []int32{2, 9} {
+                offset, size := d.partRange(partIndex, d.FileSize)
+                part := content[offset : offset+size]
+                _, err = d.file.WriteAt(part, offset)
+                c.So(err, ShouldBeNil)
+                d.addToDownloaded(partIndex, crc32.ChecksumIEEE(part))
+            }
+            corrupted, err = d.verify()
+            c.So(err, ShouldBeNil)
+            c.So(corrupted, ShouldBeEmpty)
+        })
+        Convey("Retry All Parts On Sha256 Mismatch", func(c C) {
+            d := newTestDownload(content, 1024)
+            h := sha256.Sum256([]byte("another content"))
+            c.So(repo.Files.SaveSha256(d.ClusterID, d.FileID, d.AccessHash, h[:]), ShouldBeNil)
+
+            corrupted, err := d.verify()
+            c.So(err, ShouldEqual, domain.ErrCorruptedFile)
+            c.So(corrupted, ShouldHaveLength, 10)
+
+            d.retry(corrupted)
+            c.So(queuedParts(d), ShouldHaveLength, 10)
+            c.So(d.FinishedParts, ShouldBeEmpty)
+        })
+        Convey("Part Sums Are Deleted With Request", func(c C) {
+            d := newTestDownload(content, 1024)
+            c.So(repo.Files.GetPartSums(d.GetID()), ShouldHaveLength, 10)
+            c.So(repo.Files.DeleteFileRequest(d.GetID()), ShouldBeNil)
+            c.So(repo.Files.GetPartSums(d.GetID()), ShouldBeEmpty)
+        })
+    })
+}
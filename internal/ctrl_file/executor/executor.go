@@ -2,8 +2,10 @@ package executor
 
 import (
     "context"
+    "io/ioutil"
     "path/filepath"
     "sync"
+    "sync/atomic"
 
     "github.com/beeker1121/goque"
     "github.com/ronaksoft/river-sdk/internal/logs"
@@ -22,37 +24,66 @@ import (
 const (
     defaultConcurrentRequests = 5
     defaultConcurrentAction   = 5
+
+    // goquePriorityQueue is the type which goque writes in the GOQUE file of priority queues
+    goquePriorityQueue = 2
+)
+
+// Priorities of the requests. Requests with higher priority are picked first, requests with the same
+// priority are picked in the order they have been pushed.
+const (
+    PriorityLow    uint8 = 0
+    PriorityNormal uint8 = 128
+    PriorityHigh   uint8 = 255
 )
 
 type RequestFactoryFunc func(data []byte) Request
 
+// requestState keeps the scheduling state of a request which has been pushed to the executor and has not
+// been finished yet. If the request is queued, itemID and itemPriority point to its only valid item in
+// the queue.
+type requestState struct {
+    priority     uint8
+    paused       bool
+    running      bool
+    data         []byte
+    queued       bool
+    itemID       uint64
+    itemPriority uint8
+}
+
 type Executor struct {
     name    string
-    stack   *goque.Stack
+    queue   *goque.PriorityQueue
     factory RequestFactoryFunc
     logger  *logs.Logger
 
     // internals
     waitGroupsLock sync.Mutex
     waitGroups     map[string]*sync.WaitGroup
+    statesLock     sync.Mutex
+    states         map[string]*requestState
     ctx            context.Context
     cf             context.CancelFunc
     rt             chan struct{}
+    ac             int32
     mtx            sync.Mutex
     running        bool
 }
 
 func NewExecutor(dbPath string, name string, factory RequestFactoryFunc, opts ...Option) (*Executor, error) {
-    s, err := goque.OpenStack(filepath.Join(dbPath, name))
+    q, err := openQueue(filepath.Join(dbPath, name))
     if err != nil {
         return nil, err
     }
     e := &Executor{
-        stack:      s,
+        queue:      q,
         name:       name,
         factory:    factory,
         rt:         make(chan struct{}, defaultConcurrentRequests),
+        ac:         defaultConcurrentAction,
         waitGroups: make(map[string]*sync.WaitGroup),
+        states:     make(map[string]*requestState),
         logger:     logs.With("FileExecutor"),
     }
     e.ctx, e.cf = context.WithCancel(context.Background())
@@ -62,104 +93,289 @@ func NewExecutor(dbPath string, name string, factory RequestFactoryFunc, opts ..
         opt(e)
     }
 
+    e.restore()
     return e, nil
 }
 
+// openQueue opens the priority queue in dir. Older versions were using a stack in the same path, since a
+// priority queue cannot open it, we move its requests into a new priority queue with normal priority.
+func openQueue(dir string) (*goque.PriorityQueue, error) {
+    goqueType, err := ioutil.ReadFile(filepath.Join(dir, "GOQUE"))
+    if err != nil || len(goqueType) == 0 || goqueType[0] == goquePriorityQueue {
+        return goque.OpenPriorityQueue(dir, goque.DESC)
+    }
+
+    s, err := goque.OpenStack(dir)
+    if err != nil {
+        return nil, err
+    }
+    var values [][]byte
+    for s.Length() > 0 {
+        item, err := s.Pop()
+        if err != nil {
+            _ = s.Close()
+            return nil, err
+        }
+        values = append(values, item.Value)
+    }
+    err = s.Drop()
+    if err != nil {
+        return nil, err
+    }
+
+    q, err := goque.OpenPriorityQueue(dir, goque.DESC)
+    if err != nil {
+        return nil, err
+    }
+    // Items are popped from the stack in the reverse order of their pushes
+    for i := len(values) - 1; i >= 0; i-- {
+        _, err = q.Enqueue(PriorityNormal, values[i])
+        if err != nil {
+            _ = q.Close()
+            return nil, err
+        }
+    }
+    return q, nil
+}
+
+// restore rebuilds the states of the requests which are left in the queue from the last run. The queue
+// is rewritten without the emptied items, and since queues of older versions could have several items
+// for a request, only the first one which would be dequeued is kept.
+func (e *Executor) restore() {
+    var items []*goque.PriorityItem
+    for e.queue.Length() > 0 {
+        item, err := e.queue.Dequeue()
+        if err != nil {
+            e.logger.Warn("got error on restoring requests", zap.Error(err))
+            break
+        }
+        items = append(items, item)
+    }
+    for _, item := range items {
+        if len(item.Value) == 0 {
+            continue
+        }
+        reqID := e.factory(item.Value).GetID()
+        if _, ok := e.states[reqID]; ok {
+            continue
+        }
+        st := &requestState{
+            priority: item.Priority,
+            data:     item.Value,
+        }
+        err := e.put(st)
+        if err != nil {
+            e.logger.Warn("got error on restoring request", zap.String("ReqID", reqID), zap.Error(err))
+            continue
+        }
+        e.states[reqID] = st
+    }
+}
+
+// Start runs the requests which have been restored from the last run. Requests which must not continue
+// must be paused or discarded before calling Start.
+func (e *Executor) Start() {
+    e.mtx.Lock()
+    if !e.running && e.queue.Length() > 0 {
+        e.running = true
+        go e.execute()
+    }
+    e.mtx.Unlock()
+}
+
 func (e *Executor) execute() {
     for {
+        // We wait for a free slot before picking the next request, hence requests which are pushed or
+        // reprioritized in the meantime are considered too.
+        e.rt <- struct{}{}
+
         e.mtx.Lock()
-        if e.stack.Length() == 0 {
+        if e.queue.Length() == 0 {
             e.running = false
             e.mtx.Unlock()
+            <-e.rt
             return
         }
         e.mtx.Unlock()
 
-        // Pop the next request from the stack
-        stackItem, err := e.stack.Pop()
+        // Dequeue the next request from the queue
+        item, err := e.queue.Dequeue()
         if err != nil {
             e.logger.Fatal("got Serious Error", zap.Error(err))
             return
         }
 
-        // Check rate limit and if it is ok then run the job in background
-        e.rt <- struct{}{}
+        if len(item.Value) == 0 {
+            <-e.rt
+            continue
+        }
+        req := e.factory(item.Value)
+        if !e.pick(req.GetID(), item) {
+            <-e.rt
+            continue
+        }
+
+        // Run the job in background
         go func() {
             defer func() {
                 <-e.rt
             }()
+            e.run(req)
+        }()
+    }
+}
 
-            req := e.factory(stackItem.Value)
-            e.waitGroupsLock.Lock()
-            waitGroup := e.waitGroups[req.GetID()]
-            if waitGroup != nil {
-                delete(e.waitGroups, req.GetID())
-            }
-            e.waitGroupsLock.Unlock()
-
-            oWaitGroup := &sync.WaitGroup{}
-            // External loop over requests
-            for req != nil {
-                err := req.Prepare()
-                if err != nil {
-                    e.logger.Warn("got error on Prepare",
-                        zap.String("ReqID", req.GetID()),
-                        zap.Error(err),
-                    )
-                    req = req.Next()
-                    continue
-                }
+// pick checks if the dequeued item is still valid to run. Since the priority queue does not support
+// removing or moving items, superseded items are emptied, however an item could have been dequeued
+// right before it has been superseded, and items of older versions are never emptied.
+func (e *Executor) pick(reqID string, item *goque.PriorityItem) bool {
+    e.statesLock.Lock()
+    defer e.statesLock.Unlock()
+    st := e.states[reqID]
+    if st == nil || !st.queued || st.itemID != item.ID || st.itemPriority != item.Priority {
+        e.logger.Debug("skips stale request", zap.String("ReqID", reqID))
+        return false
+    }
+    st.queued = false
+    if st.paused || st.running {
+        e.logger.Debug("skips stale request", zap.String("ReqID", reqID))
+        return false
+    }
+    st.running = true
+    return true
+}
 
-                oWaitGroup.Add(1)
-                go func(req Request) {
-                    defer oWaitGroup.Done()
-                    iMutex := sync.Mutex{}
-                    iRateLimit := make(chan struct{}, defaultConcurrentAction)
-                    iWaitGroup := sync.WaitGroup{}
-                    // Run actions in a loop
-                    for {
-                        act := req.NextAction()
-                        if act == nil {
-                            // Wait for all actions to be done before going to next request
-                            iWaitGroup.Wait()
-                            break
-                        }
-                        iRateLimit <- struct{}{}
-                        iWaitGroup.Add(1)
-                        go func() {
-                            act.Do(e.ctx)
-                            iMutex.Lock()
-                            req.ActionDone(act.ID())
-                            iMutex.Unlock()
-                            iWaitGroup.Done()
-                            <-iRateLimit
-                        }()
-                    }
-                }(req)
-
-                // Goto next chained request
-                req = req.Next()
+func (e *Executor) run(req Request) {
+    reqID := req.GetID()
+    e.waitGroupsLock.Lock()
+    waitGroup := e.waitGroups[reqID]
+    if waitGroup != nil {
+        delete(e.waitGroups, reqID)
+    }
+    e.waitGroupsLock.Unlock()
+
+    head := req
+    oWaitGroup := &sync.WaitGroup{}
+    // interrupted is set if the request has been stopped by a pause before it is completed
+    interrupted := int32(0)
+    // External loop over requests
+    for req != nil {
+        if e.IsPaused(reqID) {
+            atomic.StoreInt32(&interrupted, 1)
+            break
+        }
+        err := req.Prepare()
+        if err != nil {
+            e.logger.Warn("got error on Prepare",
+                zap.String("ReqID", req.GetID()),
+                zap.Error(err),
+            )
+            req = req.Next()
+            continue
+        }
+
+        oWaitGroup.Add(1)
+        go func(req Request) {
+            defer oWaitGroup.Done()
+            iMutex := sync.Mutex{}
+            iRateLimit := make(chan struct{}, e.ac)
+            iWaitGroup := sync.WaitGroup{}
+            // Run actions in a loop
+            for {
+                var act Action
+                if e.IsPaused(reqID) {
+                    atomic.StoreInt32(&interrupted, 1)
+                } else {
+                    act = req.NextAction()
+                }
+                if act == nil {
+                    // Wait for all actions to be done before going to next request
+                    iWaitGroup.Wait()
+                    break
+                }
+                iRateLimit <- struct{}{}
+                iWaitGroup.Add(1)
+                go func() {
+                    act.Do(e.ctx)
+                    iMutex.Lock()
+                    req.ActionDone(act.ID())
+                    iMutex.Unlock()
+                    iWaitGroup.Done()
+                    <-iRateLimit
+                }()
             }
+        }(req)
 
-            // Wait for all chained requests to finish
-            oWaitGroup.Wait()
+        // Goto next chained request
+        req = req.Next()
+    }
 
-            // If there is a waiter for this request, then mark it done.
-            if waitGroup != nil {
-                waitGroup.Done()
+    // Wait for all chained requests to finish
+    oWaitGroup.Wait()
+
+    // If the request has been paused, we keep its latest state to be used on resume. If it has been
+    // resumed before its running actions are done, we push it back to continue, otherwise we are done
+    // with it. The waiter of a pending request keeps waiting until the request is finished or discarded,
+    // it must be kept before the request could be run again.
+    done := true
+    e.statesLock.Lock()
+    if st := e.states[reqID]; st != nil {
+        st.running = false
+        switch {
+        case st.paused:
+            done = false
+            e.keepWaitGroup(reqID, waitGroup)
+            st.data = head.Serialize()
+        case atomic.LoadInt32(&interrupted) == 1:
+            done = false
+            e.keepWaitGroup(reqID, waitGroup)
+            st.data = head.Serialize()
+            err := e.enqueue(st)
+            if err != nil {
+                e.logger.Warn("got error on pushing back resumed request", zap.String("ReqID", reqID), zap.Error(err))
             }
+        default:
+            delete(e.states, reqID)
+        }
+    }
+    e.statesLock.Unlock()
 
-        }()
+    if done && waitGroup != nil {
+        waitGroup.Done()
     }
 }
 
-// Execute pushes the request into the stack. This function is non-blocking. If you need a blocking call
-// you should use ExecuteAndWait function
-func (e *Executor) Execute(req Request) error {
-    _, err := e.stack.Push(req.Serialize())
+func (e *Executor) keepWaitGroup(reqID string, waitGroup *sync.WaitGroup) {
+    if waitGroup == nil {
+        return
+    }
+    e.waitGroupsLock.Lock()
+    e.waitGroups[reqID] = waitGroup
+    e.waitGroupsLock.Unlock()
+}
+
+// put pushes the request into the queue with its current priority and data, and empties its previous
+// item, if any.
+func (e *Executor) put(st *requestState) error {
+    e.dequeue(st)
+    item, err := e.queue.Enqueue(st.priority, st.data)
+    if err != nil {
+        return err
+    }
+    st.queued = true
+    st.itemID = item.ID
+    st.itemPriority = item.Priority
+    return nil
+}
+
+// enqueue puts the request into the queue and runs the executor if it is not running. It must be called
+// while statesLock is held.
+func (e *Executor) enqueue(st *requestState) error {
+    err := e.put(st)
     if err != nil {
         return err
     }
+
     e.mtx.Lock()
     if !e.running {
         e.running = true
@@ -169,6 +385,39 @@ func (e *Executor) Execute(req Request) error {
     return nil
 }
 
+// dequeue empties the item of the request in the queue, hence it would be skipped when it is dequeued
+// and would not be restored after a restart. It must be called while statesLock is held.
+func (e *Executor) dequeue(st *requestState) {
+    if !st.queued {
+        return
+    }
+    st.queued = false
+    // The item could have been dequeued already
+    _, _ = e.queue.Update(st.itemPriority, st.itemID, nil)
+}
+
+// Execute pushes the request into the queue with normal priority. This function is non-blocking. If you
+// need a blocking call you should use ExecuteAndWait function
+func (e *Executor) Execute(req Request) error {
+    return e.ExecuteWithPriority(req, PriorityNormal)
+}
+
+// ExecuteWithPriority pushes the request into the queue with the given priority.
+func (e *Executor) ExecuteWithPriority(req Request, priority uint8) error {
+    e.statesLock.Lock()
+    defer e.statesLock.Unlock()
+    st := e.states[req.GetID()]
+    if st != nil {
+        e.dequeue(st)
+    }
+    st = &requestState{
+        priority: priority,
+        data:     req.Serialize(),
+    }
+    e.states[req.GetID()] = st
+    return e.enqueue(st)
+}
+
 // ExecuteAndWait accepts a waitGroup which its Done() function will be called  when process is done
 func (e *Executor) ExecuteAndWait(waitGroup *sync.WaitGroup, req Request) error {
     e.waitGroupsLock.Lock()
@@ -177,6 +426,131 @@ func (e *Executor) ExecuteAndWait(waitGroup *sync.WaitGroup, req Request) error
     return e.Execute(req)
 }
 
+// Pause stops running the request. If the request is running, no more action will be started and
+// its concurrency slot will be released as soon as the running actions are done. It returns false if
+// the request is not known to the executor.
+func (e *Executor) Pause(reqID string) bool {
+    e.statesLock.Lock()
+    defer e.statesLock.Unlock()
+    st := e.states[reqID]
+    if st == nil {
+        return false
+    }
+    st.paused = true
+    e.dequeue(st)
+    return true
+}
+
+// Resume pushes back a paused request into the queue. It returns false if the request is not known
+// to the executor, i.e. it has been paused before the app restarted.
+func (e *Executor) Resume(reqID string) bool {
+    e.statesLock.Lock()
+    defer e.statesLock.Unlock()
+    st := e.states[reqID]
+    if st == nil {
+        return false
+    }
+    if !st.paused {
+        return true
+    }
+    st.paused = false
+
+    // If the request is still finishing its last actions, it would be pushed back when they are done
+    if st.running {
+        return true
+    }
+    err := e.enqueue(st)
+    if err != nil {
+        e.logger.Warn("got error on resume", zap.String("ReqID", reqID), zap.Error(err))
+        return false
+    }
+    return true
+}
+
+// IsPaused returns true if the request is paused.
+func (e *Executor) IsPaused(reqID string) bool {
+    e.statesLock.Lock()
+    defer e.statesLock.Unlock()
+    st := e.states[reqID]
+    return st != nil && st.paused
+}
+
+// Priority returns the current priority of the request. It returns false if the request is not known
+// to the executor.
+func (e *Executor) Priority(reqID string) (uint8, bool) {
+    e.statesLock.Lock()
+    defer e.statesLock.Unlock()
+    st := e.states[reqID]
+    if st == nil {
+        return PriorityNormal, false
+    }
+    return st.priority, true
+}
+
+// SetPriority changes the priority of a request which is not running yet. It returns false if the request
+// is not known to the executor.
+func (e *Executor) SetPriority(reqID string, priority uint8) bool {
+    e.statesLock.Lock()
+    defer e.statesLock.Unlock()
+    st := e.states[reqID]
+    if st == nil {
+        return false
+    }
+    if st.priority == priority {
+        return true
+    }
+    st.priority = priority
+
+    // The old item in the queue is replaced by a new one with the new priority
+    if !st.running && !st.paused {
+        err := e.enqueue(st)
+        if err != nil {
+            e.logger.Warn("got error on set priority", zap.String("ReqID", reqID), zap.Error(err))
+            return false
+        }
+    }
+    return true
+}
+
+// Discard forgets the request if it is not running. Running requests must be canceled by their
+// own logic. The waiter of the discarded request is released.
+func (e *Executor) Discard(reqID string) {
+    e.statesLock.Lock()
+    st := e.states[reqID]
+    discarded := st != nil && !st.running
+    if discarded {
+        e.dequeue(st)
+        delete(e.states, reqID)
+    }
+    e.statesLock.Unlock()
+    if !discarded {
+        return
+    }
+
+    e.waitGroupsLock.Lock()
+    waitGroup := e.waitGroups[reqID]
+    delete(e.waitGroups, reqID)
+    e.waitGroupsLock.Unlock()
+    if waitGroup != nil {
+        waitGroup.Done()
+    }
+}
+
+// RestorePaused registers the request, which has been paused before the restart, as a paused request,
+// hence it could be resumed by Resume.
+func (e *Executor) RestorePaused(req Request, priority uint8) {
+    e.statesLock.Lock()
+    if st := e.states[req.GetID()]; st != nil {
+        e.dequeue(st)
+    }
+    e.states[req.GetID()] = &requestState{
+        priority: priority,
+        paused:   true,
+        data:     req.Serialize(),
+    }
+    e.statesLock.Unlock()
+}
+
 // Option to config Executor
 type Option func(e *Executor)
 
@@ -185,3 +559,12 @@ func WithConcurrency(c int32) Option {
         e.rt = make(chan struct{}, c)
     }
 }
+
+// WithActionConcurrency sets the maximum number of actions of each request which run in parallel
+func WithActionConcurrency(c int32) Option {
+    return func(e *Executor) {
+        if c > 0 {
+            e.ac = c
+        }
+    }
+}
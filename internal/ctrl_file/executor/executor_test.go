@@ -4,10 +4,12 @@ import (
     "context"
     "encoding/json"
     "os"
+    "path/filepath"
     "sync"
     "testing"
     "time"
 
+    "github.com/beeker1121/goque"
     "github.com/ronaksoft/river-sdk/internal/testenv"
     "github.com/ronaksoft/rony/tools"
     . "github.com/smartystreets/goconvey/convey"
@@ -33,6 +35,7 @@ func (d *dummyAction) ID() int32 {
 }
 
 func (d *dummyAction) Do(ctx context.Context) {
+    time.Sleep(d.req.Delay)
     testenv.Log().Info("Do",
         zap.String("ReqID", d.req.GetID()),
         zap.Int32("ActionID", d.id),
@@ -41,16 +44,25 @@ func (d *dummyAction) Do(ctx context.Context) {
 
 type dummyRequest struct {
     ID      string
+    Delay   time.Duration
     chunks  chan int32
     Done    []int32
     NextReq *dummyRequest
 }
 
+var (
+    preparedLock sync.Mutex
+    prepared     []string
+)
+
 func (d *dummyRequest) GetID() string {
     return d.ID
 }
 
 func (d *dummyRequest) Prepare() error {
+    preparedLock.Lock()
+    prepared = append(prepared, d.ID)
+    preparedLock.Unlock()
     d.chunks = make(chan int32, 10)
     d.Done = d.Done[:0]
     for i := int32(0); i < 10; i++ {
@@ -139,3 +151,266 @@ func TestNewExecutor(t *testing.T) {
 
     })
 }
+
+func TestExecutorPriority(t *testing.T) {
+    _ = os.MkdirAll("./_hdd", os.ModePerm)
+    e, err := NewExecutor("./_hdd", "dummyPriority",
+        func(data []byte) Request {
+            r := &dummyRequest{}
+            err := json.Unmarshal(data, r)
+            if err != nil {
+                panic(err)
+            }
+            return r
+        },
+        WithConcurrency(1),
+    )
+    if err != nil {
+        t.Fatal(err)
+    }
+    waitFor := func(reqID string) {
+        for {
+            if _, ok := e.Priority(reqID); !ok {
+                return
+            }
+            time.Sleep(time.Millisecond * 50)
+        }
+    }
+    Convey("Executor With Priority", t, func(c C) {
+        Convey("Higher Priority Runs First", func(c C) {
+            preparedLock.Lock()
+            prepared = prepared[:0]
+            preparedLock.Unlock()
+
+            busy := &dummyRequest{ID: tools.RandomID(32), Delay: time.Millisecond * 100}
+            low := &dummyRequest{ID: tools.RandomID(32)}
+            high := &dummyRequest{ID: tools.RandomID(32)}
+            moved := &dummyRequest{ID: tools.RandomID(32)}
+            c.So(e.Execute(busy), ShouldBeNil)
+            time.Sleep(time.Millisecond * 50)
+            c.So(e.ExecuteWithPriority(low, PriorityLow), ShouldBeNil)
+            c.So(e.Execute(moved), ShouldBeNil)
+            c.So(e.ExecuteWithPriority(high, PriorityHigh), ShouldBeNil)
+            c.So(e.SetPriority(moved.ID, PriorityLow), ShouldBeTrue)
+            waitFor(busy.ID)
+            waitFor(low.ID)
+            waitFor(high.ID)
+            waitFor(moved.ID)
+
+            preparedLock.Lock()
+            c.So(prepared, ShouldResemble, []string{busy.ID, high.ID, low.ID, moved.ID})
+            preparedLock.Unlock()
+        })
+        Convey("Pause and Resume", func(c C) {
+            r := &dummyRequest{ID: tools.RandomID(32), Delay: time.Millisecond * 100}
+            other := &dummyRequest{ID: tools.RandomID(32)}
+            c.So(e.Execute(r), ShouldBeNil)
+            time.Sleep(time.Millisecond * 50)
+            c.So(e.Pause(r.ID), ShouldBeTrue)
+            c.So(e.IsPaused(r.ID), ShouldBeTrue)
+
+            // Paused request must not hold the only slot we have
+            c.So(e.Execute(other), ShouldBeNil)
+            waitFor(other.ID)
+            _, ok := e.Priority(r.ID)
+            c.So(ok, ShouldBeTrue)
+
+            c.So(e.Resume(r.ID), ShouldBeTrue)
+            c.So(e.IsPaused(r.ID), ShouldBeFalse)
+            waitFor(r.ID)
+        })
+        Convey("Resume While Finishing", func(c C) {
+            preparedLock.Lock()
+            prepared = prepared[:0]
+            preparedLock.Unlock()
+
+            r := &dummyRequest{ID: tools.RandomID(32), Delay: time.Millisecond * 200}
+            waitGroup := &sync.WaitGroup{}
+            waitGroup.Add(1)
+            c.So(e.ExecuteAndWait(waitGroup, r), ShouldBeNil)
+            time.Sleep(time.Millisecond * 50)
+            c.So(e.Pause(r.ID), ShouldBeTrue)
+            // The first actions are done and the request waits for the action which has been started
+            // before it noticed the pause
+            time.Sleep(time.Millisecond * 250)
+            c.So(e.Resume(r.ID), ShouldBeTrue)
+            waitGroup.Wait()
+
+            // The request must be pushed back and run again after its running actions are done
+            preparedLock.Lock()
+            c.So(prepared, ShouldResemble, []string{r.ID, r.ID})
+            preparedLock.Unlock()
+            _, ok := e.Priority(r.ID)
+            c.So(ok, ShouldBeFalse)
+        })
+        Convey("Superseded Items", func(c C) {
+            busy := &dummyRequest{ID: tools.RandomID(32), Delay: time.Millisecond * 200}
+            r := &dummyRequest{ID: tools.RandomID(32)}
+            var items [][2]uint64
+            lastItem := func() {
+                e.statesLock.Lock()
+                st := e.states[r.ID]
+                items = append(items, [2]uint64{uint64(st.itemPriority), st.itemID})
+                e.statesLock.Unlock()
+            }
+            isEmpty := func(i int) bool {
+                item, err := e.queue.PeekByPriorityID(uint8(items[i][0]), items[i][1])
+                c.So(err, ShouldBeNil)
+                return len(item.Value) == 0
+            }
+            c.So(e.Execute(busy), ShouldBeNil)
+            time.Sleep(time.Millisecond * 50)
+            c.So(e.Execute(r), ShouldBeNil)
+            lastItem()
+            c.So(e.SetPriority(r.ID, PriorityHigh), ShouldBeTrue)
+            lastItem()
+            c.So(e.SetPriority(r.ID, PriorityLow), ShouldBeTrue)
+            lastItem()
+            c.So(isEmpty(0), ShouldBeTrue)
+            c.So(isEmpty(1), ShouldBeTrue)
+            c.So(isEmpty(2), ShouldBeFalse)
+            c.So(e.Pause(r.ID), ShouldBeTrue)
+            c.So(isEmpty(2), ShouldBeTrue)
+            c.So(e.Resume(r.ID), ShouldBeTrue)
+            lastItem()
+            c.So(isEmpty(3), ShouldBeFalse)
+            waitFor(busy.ID)
+            waitFor(r.ID)
+        })
+        Convey("Discard Paused Request", func(c C) {
+            r := &dummyRequest{ID: tools.RandomID(32), Delay: time.Millisecond * 100}
+            c.So(e.Pause(r.ID), ShouldBeFalse)
+            c.So(e.Execute(r), ShouldBeNil)
+            c.So(e.Pause(r.ID), ShouldBeTrue)
+            time.Sleep(time.Millisecond * 500)
+            e.Discard(r.ID)
+            c.So(e.Resume(r.ID), ShouldBeFalse)
+        })
+    })
+}
+
+func newDummyExecutor(name string, opts ...Option) (*Executor, error) {
+    return NewExecutor("./_hdd", name,
+        func(data []byte) Request {
+            r := &dummyRequest{}
+            err := json.Unmarshal(data, r)
+            if err != nil {
+                panic(err)
+            }
+            return r
+        },
+        opts...,
+    )
+}
+
+func TestExecutorRestore(t *testing.T) {
+    _ = os.MkdirAll("./_hdd", os.ModePerm)
+    waitFor := func(e *Executor, reqID string) {
+        for {
+            if _, ok := e.Priority(reqID); !ok {
+                return
+            }
+            time.Sleep(time.Millisecond * 50)
+        }
+    }
+    Convey("Executor Restore", t, func(c C) {
+        Convey("Restore From Priority Queue", func(c C) {
+            name := tools.RandomID(12)
+            r1 := &dummyRequest{ID: tools.RandomID(32)}
+            r2 := &dummyRequest{ID: tools.RandomID(32)}
+            q, err := goque.OpenPriorityQueue(filepath.Join("./_hdd", name), goque.DESC)
+            c.So(err, ShouldBeNil)
+            _, err = q.Enqueue(PriorityHigh, r1.Serialize())
+            c.So(err, ShouldBeNil)
+            _, err = q.Enqueue(PriorityLow, r2.Serialize())
+            c.So(err, ShouldBeNil)
+            // stale item of r1 with lower priority
+            _, err = q.Enqueue(PriorityLow, r1.Serialize())
+            c.So(err, ShouldBeNil)
+            c.So(q.Close(), ShouldBeNil)
+
+            e, err := newDummyExecutor(name)
+            c.So(err, ShouldBeNil)
+            p, ok := e.Priority(r1.ID)
+            c.So(ok, ShouldBeTrue)
+            c.So(p, ShouldEqual, PriorityHigh)
+            p, ok = e.Priority(r2.ID)
+            c.So(ok, ShouldBeTrue)
+            c.So(p, ShouldEqual, PriorityLow)
+
+            e.RestorePaused(r2, PriorityLow)
+            e.Start()
+            waitFor(e, r1.ID)
+            c.So(e.IsPaused(r2.ID), ShouldBeTrue)
+            c.So(e.Resume(r2.ID), ShouldBeTrue)
+            waitFor(e, r2.ID)
+        })
+        Convey("Migrate Stack", func(c C) {
+            name := tools.RandomID(12)
+            r1 := &dummyRequest{ID: tools.RandomID(32)}
+            r2 := &dummyRequest{ID: tools.RandomID(32)}
+            s, err := goque.OpenStack(filepath.Join("./_hdd", name))
+            c.So(err, ShouldBeNil)
+            _, err = s.Push(r1.Serialize())
+            c.So(err, ShouldBeNil)
+            _, err = s.Push(r2.Serialize())
+            c.So(err, ShouldBeNil)
+            c.So(s.Close(), ShouldBeNil)
+
+            e, err := newDummyExecutor(name, WithConcurrency(1))
+            c.So(err, ShouldBeNil)
+            c.So(e.queue.Length(), ShouldEqual, 2)
+            p, ok := e.Priority(r1.ID)
+            c.So(ok, ShouldBeTrue)
+            c.So(p, ShouldEqual, PriorityNormal)
+
+            preparedLock.Lock()
+            prepared = prepared[:0]
+            preparedLock.Unlock()
+            e.Start()
+            waitFor(e, r1.ID)
+            waitFor(e, r2.ID)
+            preparedLock.Lock()
+            c.So(prepared, ShouldResemble, []string{r1.ID, r2.ID})
+            preparedLock.Unlock()
+        })
+        Convey("Waiter Of Paused Request", func(c C) {
+            e, err := newDummyExecutor(tools.RandomID(12))
+            c.So(err, ShouldBeNil)
+            done := make(chan struct{}, 1)
+            wait := func(r *dummyRequest) {
+                waitGroup := &sync.WaitGroup{}
+                waitGroup.Add(1)
+                c.So(e.ExecuteAndWait(waitGroup, r), ShouldBeNil)
+                go func() {
+                    waitGroup.Wait()
+                    done <- struct{}{}
+                }()
+            }
+            isDone := func(d time.Duration) bool {
+                select {
+                case <-done:
+                    return true
+                case <-time.After(d):
+                    return false
+                }
+            }
+
+            r := &dummyRequest{ID: tools.RandomID(32), Delay: time.Millisecond * 100}
+            wait(r)
+            time.Sleep(time.Millisecond * 50)
+            c.So(e.Pause(r.ID), ShouldBeTrue)
+            c.So(isDone(time.Millisecond*500), ShouldBeFalse)
+            c.So(e.Resume(r.ID), ShouldBeTrue)
+            c.So(isDone(time.Second*5), ShouldBeTrue)
+
+            r = &dummyRequest{ID: tools.RandomID(32), Delay: time.Millisecond * 100}
+            wait(r)
+            time.Sleep(time.Millisecond * 50)
+            c.So(e.Pause(r.ID), ShouldBeTrue)
+            c.So(isDone(time.Millisecond*500), ShouldBeFalse)
+            e.Discard(r.ID)
+            c.So(isDone(time.Second), ShouldBeTrue)
+        })
+    })
+}
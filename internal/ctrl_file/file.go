@@ -39,6 +39,7 @@ type Config struct {
     Network              *networkCtrl.Controller
     MaxInflightDownloads int32
     MaxInflightUploads   int32
+    UploadWindow         int32
     DbPath               string
     PostUploadProcessCB  func(req *msg.ClientFileRequest) bool
     ProgressChangedCB    func(reqID string, clusterID int32, fileID, accessHash int64, percent int64, peerID int64)
@@ -51,6 +52,17 @@ type Controller struct {
     downloader *executor.Executor
     uploader   *executor.Executor
 
+    // Downloads which are running, streams use them to prioritize the parts they need
+    activeDownloads  sync.Map
+    streamServer     *streamServer
+    streamServerLock sync.Mutex
+
+    // Bandwidth limits and auto download rules
+    profiles        *networkProfiles
+    uploadLimiter   *bandwidthLimiter
+    downloadLimiter *bandwidthLimiter
+    chunkTuner      *chunkTuner
+
     // Callbacks
     onProgressChanged func(reqID string, clusterID int32, fileID, accessHash int64, percent int64, peerID int64)
     onCompleted       func(reqID string, clusterID int32, fileID, accessHash int64, filePath string, peerID int64)
@@ -66,6 +78,13 @@ func New(config Config) *Controller {
     ctrl := &Controller{
         network:           config.Network,
         postUploadProcess: config.PostUploadProcessCB,
+        profiles:          newNetworkProfiles(),
+        uploadLimiter:     newBandwidthLimiter(0),
+        downloadLimiter:   newBandwidthLimiter(0),
+        chunkTuner:        newChunkTuner(),
+    }
+    if config.UploadWindow <= 0 {
+        config.UploadWindow = DefaultUploadWindow
     }
 
     if config.CompletedCB == nil {
@@ -103,7 +122,10 @@ func New(config Config) *Controller {
         }
         _ = r.cfr.Unmarshal(data)
         return r
-    }, executor.WithConcurrency(config.MaxInflightUploads))
+    },
+        executor.WithConcurrency(config.MaxInflightUploads),
+        executor.WithActionConcurrency(config.UploadWindow),
+    )
     if err != nil {
         logger.Fatal("got error on initializing uploader", zap.Error(err))
     }
@@ -112,14 +134,30 @@ func New(config Config) *Controller {
 }
 
 func (ctrl *Controller) Start() {
+    ctrl.profiles.load()
+    ctrl.applyNetworkProfile()
+
     reqs, _ := repo.Files.GetAllFileRequests()
     for _, req := range reqs {
-        _ = repo.Files.DeleteFileRequest(getRequestID(req.ClusterID, req.FileID, req.AccessHash))
+        reqID := getRequestID(req.ClusterID, req.FileID, req.AccessHash)
+        e := ctrl.getExecutor(req)
+        // Paused requests are kept to be continued when they are resumed
+        if priority, paused := repo.Files.GetPausedFileRequest(reqID); paused {
+            e.RestorePaused(ctrl.newRequest(req), priority)
+            continue
+        }
+        // Requests which are left in the queue continue from where they were
+        if _, queued := e.Priority(reqID); queued {
+            continue
+        }
+        _ = repo.Files.DeleteFileRequest(reqID)
     }
+    ctrl.downloader.Start()
+    ctrl.uploader.Start()
 }
 
 func (ctrl *Controller) Stop() {
-    // Nothing
+    ctrl.stopStreamServer()
 }
 
 func (ctrl *Controller) GetUploadRequest(fileID int64) *msg.ClientFileRequest {
@@ -128,6 +166,12 @@ func (ctrl *Controller) GetUploadRequest(fileID int64) *msg.ClientFileRequest {
 func (ctrl *Controller) GetDownloadRequest(clusterID int32, fileID int64, accessHash uint64) *msg.ClientFileRequest {
     return ctrl.GetRequest(clusterID, fileID, accessHash)
 }
+func (ctrl *Controller) GetUploadRequestID(fileID int64) string {
+    return getRequestID(0, fileID, 0)
+}
+func (ctrl *Controller) GetDownloadRequestID(clusterID int32, fileID int64, accessHash uint64) string {
+    return getRequestID(clusterID, fileID, accessHash)
+}
 func (ctrl *Controller) GetRequest(clusterID int32, fileID int64, accessHash uint64) *msg.ClientFileRequest {
     req, err := repo.Files.GetFileRequest(getRequestID(clusterID, fileID, accessHash))
     if err != nil {
@@ -148,9 +192,108 @@ func (ctrl *Controller) CancelDownloadRequest(clusterID int32, fileID int64, acc
 }
 func (ctrl *Controller) CancelRequest(reqID string) {
     _ = repo.Files.DeleteFileRequest(reqID)
+    ctrl.downloader.Discard(reqID)
+    ctrl.uploader.Discard(reqID)
+}
+
+// PauseRequest pauses the upload/download request. The progress of the request is kept and it does not
+// occupy any concurrency slot until it is resumed.
+func (ctrl *Controller) PauseRequest(reqID string) error {
+    logger.Info("pauses Request", zap.String("ReqID", reqID))
+    req, err := repo.Files.GetFileRequest(reqID)
+    if err != nil {
+        return err
+    }
+    e := ctrl.getExecutor(req)
+    e.Pause(reqID)
+    priority, _ := e.Priority(reqID)
+    return repo.Files.PauseFileRequest(reqID, priority)
+}
+
+// ResumeRequest continues the paused upload/download request with the priority it had before being paused.
+func (ctrl *Controller) ResumeRequest(reqID string) error {
+    logger.Info("resumes Request", zap.String("ReqID", reqID))
+    req, err := repo.Files.GetFileRequest(reqID)
+    if err != nil {
+        return err
+    }
+    priority, paused := repo.Files.GetPausedFileRequest(reqID)
+    if !paused {
+        return nil
+    }
+    err = repo.Files.UnpauseFileRequest(reqID)
+    if err != nil {
+        return err
+    }
+    // Resumed by the user, so it is not an auto download anymore
+    _ = repo.Files.UnmarkAutoFileRequest(reqID)
+
+    e := ctrl.getExecutor(req)
+    if e.Resume(reqID) {
+        return nil
+    }
+
+    // Executor does not know the request, so we rebuild it from its saved state
+    return e.ExecuteWithPriority(ctrl.newRequest(req), priority)
+}
+
+// newRequest rebuilds the upload/download request from its saved state
+func (ctrl *Controller) newRequest(req *msg.ClientFileRequest) executor.Request {
+    if req.ClusterID == 0 {
+        return &UploadRequest{
+            cfr:       req,
+            ctrl:      ctrl,
+            startTime: domain.Now(),
+        }
+    }
+
+    reqBytes, _ := req.Marshal()
+    downloadReq := &DownloadRequest{
+        ctrl: ctrl,
+    }
+    _ = downloadReq.Unmarshal(reqBytes)
+    if downloadReq.TempPath == "" {
+        downloadReq.TempPath = fmt.Sprintf("%s.tmp", downloadReq.FilePath)
+    }
+    return downloadReq
+}
+
+// SetRequestPriority changes the priority of the upload/download request. Requests with higher priority
+// are started first, however changing the priority does not interrupt already running requests.
+func (ctrl *Controller) SetRequestPriority(reqID string, priority uint8) error {
+    logger.Info("sets Request priority", zap.String("ReqID", reqID), zap.Uint8("Priority", priority))
+    req, err := repo.Files.GetFileRequest(reqID)
+    if err != nil {
+        return err
+    }
+    known := ctrl.getExecutor(req).SetPriority(reqID, priority)
+    if _, paused := repo.Files.GetPausedFileRequest(reqID); paused {
+        return repo.Files.PauseFileRequest(reqID, priority)
+    }
+    if !known {
+        return domain.ErrNotFound
+    }
+    return nil
+}
+
+// IsPaused returns true if the upload/download request is paused
+func (ctrl *Controller) IsPaused(reqID string) bool {
+    _, paused := repo.Files.GetPausedFileRequest(reqID)
+    return paused
+}
+
+func (ctrl *Controller) getExecutor(req *msg.ClientFileRequest) *executor.Executor {
+    if req.ClusterID == 0 {
+        return ctrl.uploader
+    }
+    return ctrl.downloader
 }
 
 func (ctrl *Controller) DownloadAsync(clusterID int32, fileID int64, accessHash uint64, skipDelegates bool) (reqID string, err error) {
+    return ctrl.downloadAsync(clusterID, fileID, accessHash, skipDelegates, false)
+}
+
+func (ctrl *Controller) downloadAsync(clusterID int32, fileID int64, accessHash uint64, skipDelegates bool, auto bool) (reqID string, err error) {
     defer logger.RecoverPanic(
         "FileCtrl::DownloadASync",
         domain.M{
@@ -180,6 +323,7 @@ func (ctrl *Controller) DownloadAsync(clusterID int32, fileID int64, accessHash
             SkipDelegateCall: skipDelegates,
             PeerID:           clientFile.PeerID,
         },
+        auto: auto,
     }, false)
     logger.WarnOnErr("Error On DownloadAsync", err,
         zap.Int32("ClusterID", clusterID),
@@ -189,6 +333,21 @@ func (ctrl *Controller) DownloadAsync(clusterID int32, fileID int64, accessHash
 
     return getRequestID(clusterID, fileID, accessHash), err
 }
+
+// AutoDownloadAsync must be used for the downloads which are not requested by the user. It checks the
+// auto download rules of the current network profile and returns domain.ErrAutoDownloadDisabled if the file
+// must not be downloaded automatically. The rules are checked again when the download is started by the
+// executor, since the network type could change while the request is waiting in the queue.
+func (ctrl *Controller) AutoDownloadAsync(clusterID int32, fileID int64, accessHash uint64, skipDelegates bool) (reqID string, err error) {
+    clientFile, err := repo.Files.Get(clusterID, fileID, accessHash)
+    if err != nil {
+        return "", err
+    }
+    if !ctrl.CanAutoDownload(clientFile) {
+        return "", domain.ErrAutoDownloadDisabled
+    }
+    return ctrl.downloadAsync(clusterID, fileID, accessHash, skipDelegates, true)
+}
 func (ctrl *Controller) DownloadSync(clusterID int32, fileID int64, accessHash uint64, skipDelegate bool) (filePath string, err error) {
     defer logger.RecoverPanic(
         "FileCtrl::DownloadSync",
@@ -238,11 +397,21 @@ func (ctrl *Controller) DownloadSync(clusterID int32, fileID int64, accessHash u
                             return
                         }
 
+                        // If we know the file size, make sure we have got the whole file
+                        if clientFile.FileSize > 0 && int64(len(x.Bytes)) != clientFile.FileSize {
+                            err = domain.ErrCorruptedFile
+                            return
+                        }
+
                         // write to file path
                         err = ioutil.WriteFile(filePath, x.Bytes, 0666)
                         if err != nil {
                             return
                         }
+                        _ = repo.Files.SaveIntegrityResult(
+                            clientFile.ClusterID, clientFile.FileID, clientFile.AccessHash,
+                            int64(len(x.Bytes)), repo.FileIntegrityVerified,
+                        )
 
                         // save to DB
                         _ = repo.Files.Save(clientFile)
@@ -290,16 +459,21 @@ func (ctrl *Controller) download(req *DownloadRequest, blocking bool) error {
     if req.ClusterID == 0 {
         return domain.ErrInvalidData
     }
-    _, err := repo.Files.GetFileRequest(getRequestID(req.ClusterID, req.FileID, req.AccessHash))
+    reqID := getRequestID(req.ClusterID, req.FileID, req.AccessHash)
+    _, err := repo.Files.GetFileRequest(reqID)
     if err == nil {
+        if !req.auto {
+            // The user wants the file which is being downloaded automatically, so the auto download
+            // rules must not stop it anymore
+            _ = repo.Files.UnmarkAutoFileRequest(reqID)
+        }
         return domain.ErrAlreadyDownloading
     }
 
-    _, _ = repo.Files.SaveFileRequest(
-        getRequestID(req.ClusterID, req.FileID, req.AccessHash),
-        &req.ClientFileRequest,
-        false,
-    )
+    _, _ = repo.Files.SaveFileRequest(reqID, &req.ClientFileRequest, false)
+    if req.auto {
+        _ = repo.Files.MarkAutoFileRequest(reqID)
+    }
 
     req.TempPath = fmt.Sprintf("%s.tmp", req.FilePath)
     if blocking {
@@ -309,7 +483,11 @@ func (ctrl *Controller) download(req *DownloadRequest, blocking bool) error {
         if err != nil {
             return err
         }
+        // The waiter is kept waiting while the request is paused, and it is released when the request
+        // is finished or canceled, hence the file exists only if the download has been completed.
         waitGroup.Wait()
+        _, err = os.Stat(req.FilePath)
+        return err
     } else {
         err = ctrl.downloader.Execute(req)
         if err != nil {
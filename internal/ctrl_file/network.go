@@ -0,0 +1,226 @@
+package fileCtrl
+
+import (
+    "encoding/json"
+    "sync"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "go.uber.org/zap"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+type NetworkType int32
+
+const (
+    NetworkWifi NetworkType = iota
+    NetworkCellular
+    NetworkRoaming
+)
+
+func (t NetworkType) String() string {
+    switch t {
+    case NetworkWifi:
+        return "Wifi"
+    case NetworkCellular:
+        return "Cellular"
+    case NetworkRoaming:
+        return "Roaming"
+    }
+    return "Unknown"
+}
+
+// AutoDownloadRule defines if a media type could be downloaded without user's request. MaxSize is in bytes
+// and zero means there is no limit on the size of the file.
+type AutoDownloadRule struct {
+    Enabled bool  `json:"enabled"`
+    MaxSize int64 `json:"max_size"`
+}
+
+// NetworkProfile holds the bandwidth limits and auto download rules which must be applied when the device
+// is connected to a network type. Rates are in bytes per second and zero means unlimited.
+type NetworkProfile struct {
+    UploadRate   int64                                    `json:"upload_rate"`
+    DownloadRate int64                                    `json:"download_rate"`
+    AutoDownload map[msg.ClientMediaType]AutoDownloadRule `json:"auto_download"`
+}
+
+func (p *NetworkProfile) clone() *NetworkProfile {
+    p2 := &NetworkProfile{
+        UploadRate:   p.UploadRate,
+        DownloadRate: p.DownloadRate,
+        AutoDownload: make(map[msg.ClientMediaType]AutoDownloadRule, len(p.AutoDownload)),
+    }
+    for mt, r := range p.AutoDownload {
+        p2.AutoDownload[mt] = r
+    }
+    return p2
+}
+
+func defaultNetworkProfiles() map[NetworkType]*NetworkProfile {
+    return map[NetworkType]*NetworkProfile{
+        NetworkWifi: {
+            AutoDownload: map[msg.ClientMediaType]AutoDownloadRule{
+                msg.ClientMediaType_ClientMediaMedia: {Enabled: true},
+                msg.ClientMediaType_ClientMediaVoice: {Enabled: true},
+                msg.ClientMediaType_ClientMediaGif:   {Enabled: true},
+                msg.ClientMediaType_ClientMediaAudio: {Enabled: true, MaxSize: 10 << 20},
+                msg.ClientMediaType_ClientMediaFile:  {Enabled: true, MaxSize: 10 << 20},
+            },
+        },
+        NetworkCellular: {
+            AutoDownload: map[msg.ClientMediaType]AutoDownloadRule{
+                msg.ClientMediaType_ClientMediaMedia: {Enabled: true, MaxSize: 1 << 20},
+                msg.ClientMediaType_ClientMediaVoice: {Enabled: true},
+                msg.ClientMediaType_ClientMediaGif:   {Enabled: true, MaxSize: 1 << 20},
+            },
+        },
+        NetworkRoaming: {
+            AutoDownload: map[msg.ClientMediaType]AutoDownloadRule{},
+        },
+    }
+}
+
+// networkProfiles keeps the profiles of all the network types and the network type the device is
+// currently connected to.
+type networkProfiles struct {
+    mtx      sync.RWMutex
+    current  NetworkType
+    profiles map[NetworkType]*NetworkProfile
+}
+
+func newNetworkProfiles() *networkProfiles {
+    return &networkProfiles{
+        profiles: defaultNetworkProfiles(),
+    }
+}
+
+func (np *networkProfiles) load() {
+    np.mtx.Lock()
+    defer np.mtx.Unlock()
+    np.profiles = defaultNetworkProfiles()
+    b, _ := repo.System.LoadBytes(domain.SkNetworkProfiles)
+    if len(b) > 0 {
+        profiles := map[NetworkType]*NetworkProfile{}
+        err := json.Unmarshal(b, &profiles)
+        if err != nil {
+            logger.Warn("got error on loading network profiles", zap.Error(err))
+        } else {
+            for t, p := range profiles {
+                if p.AutoDownload == nil {
+                    p.AutoDownload = map[msg.ClientMediaType]AutoDownloadRule{}
+                }
+                np.profiles[t] = p
+            }
+        }
+    }
+    t, _ := repo.System.LoadInt(domain.SkNetworkType)
+    np.current = NetworkType(t)
+}
+
+func (np *networkProfiles) save() error {
+    b, err := json.Marshal(np.profiles)
+    if err != nil {
+        return err
+    }
+    return repo.System.SaveBytes(domain.SkNetworkProfiles, b)
+}
+
+func (np *networkProfiles) get(t NetworkType) *NetworkProfile {
+    np.mtx.RLock()
+    defer np.mtx.RUnlock()
+    p, ok := np.profiles[t]
+    if !ok {
+        return &NetworkProfile{AutoDownload: map[msg.ClientMediaType]AutoDownloadRule{}}
+    }
+    return p.clone()
+}
+
+func (np *networkProfiles) set(t NetworkType, p *NetworkProfile) error {
+    np.mtx.Lock()
+    defer np.mtx.Unlock()
+    p = p.clone()
+    np.profiles[t] = p
+    return np.save()
+}
+
+func (np *networkProfiles) setCurrent(t NetworkType) error {
+    np.mtx.Lock()
+    np.current = t
+    np.mtx.Unlock()
+    return repo.System.SaveInt(domain.SkNetworkType, uint64(t))
+}
+
+func (np *networkProfiles) getCurrent() (NetworkType, *NetworkProfile) {
+    np.mtx.RLock()
+    t := np.current
+    np.mtx.RUnlock()
+    return t, np.get(t)
+}
+
+// SetNetworkType must be called by the app whenever the device connects to a different network type. The
+// bandwidth limits of the new network profile are applied to the running transfers too.
+func (ctrl *Controller) SetNetworkType(t NetworkType) error {
+    logger.Info("sets network type", zap.String("Type", t.String()))
+    err := ctrl.profiles.setCurrent(t)
+    ctrl.applyNetworkProfile()
+    return err
+}
+
+func (ctrl *Controller) GetNetworkType() NetworkType {
+    t, _ := ctrl.profiles.getCurrent()
+    return t
+}
+
+// SetNetworkProfile stores the profile of the network type. If the device is currently connected to this
+// network type, the new bandwidth limits are applied immediately.
+func (ctrl *Controller) SetNetworkProfile(t NetworkType, p *NetworkProfile) error {
+    logger.Info("sets network profile",
+        zap.String("Type", t.String()),
+        zap.Int64("UploadRate", p.UploadRate),
+        zap.Int64("DownloadRate", p.DownloadRate),
+    )
+    err := ctrl.profiles.set(t, p)
+    ctrl.applyNetworkProfile()
+    return err
+}
+
+// GetNetworkProfile returns a copy of the profile of the network type
+func (ctrl *Controller) GetNetworkProfile(t NetworkType) *NetworkProfile {
+    return ctrl.profiles.get(t)
+}
+
+func (ctrl *Controller) applyNetworkProfile() {
+    _, p := ctrl.profiles.getCurrent()
+    ctrl.uploadLimiter.SetRate(p.UploadRate)
+    ctrl.downloadLimiter.SetRate(p.DownloadRate)
+}
+
+// CanAutoDownload checks the auto download rules of the current network profile and returns true if the
+// file could be downloaded without the user's request.
+func (ctrl *Controller) CanAutoDownload(clientFile *msg.ClientFile) bool {
+    switch clientFile.Type {
+    case msg.ClientFileType_GroupProfilePhoto, msg.ClientFileType_AccountProfilePhoto,
+        msg.ClientFileType_Thumbnail, msg.ClientFileType_Wallpaper:
+        // These files are small and the UI cannot be rendered without them
+        return true
+    }
+    _, p := ctrl.profiles.getCurrent()
+    rule, ok := p.AutoDownload[getMediaType(clientFile.Attributes)]
+    if !ok || !rule.Enabled {
+        return false
+    }
+    if rule.MaxSize > 0 && clientFile.FileSize > rule.MaxSize {
+        return false
+    }
+    return true
+}
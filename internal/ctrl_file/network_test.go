@@ -0,0 +1,87 @@
+package fileCtrl
+
+import (
+    "fmt"
+    "path/filepath"
+    "testing"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+// newTestAutoDownload saves a 5MB photo and returns a download request of it which is marked as auto
+// download. The repo is initialized by file_test.go.
+func newTestAutoDownload(ctrl *Controller) *DownloadRequest {
+    clientFile := &msg.ClientFile{
+        ClusterID:  1,
+        FileID:     domain.RandomInt63(),
+        AccessHash: 1,
+        Type:       msg.ClientFileType_Message,
+        FileSize:   5 << 20,
+        Attributes: []*msg.DocumentAttribute{{Type: msg.DocumentAttributeType_AttributeTypePhoto}},
+    }
+    So(repo.Files.Save(clientFile), ShouldBeNil)
+    filePath := filepath.Join("./_hdd", fmt.Sprintf("auto-%d", clientFile.FileID))
+    d := &DownloadRequest{
+        ClientFileRequest: msg.ClientFileRequest{
+            ClusterID:  clientFile.ClusterID,
+            FileID:     clientFile.FileID,
+            AccessHash: clientFile.AccessHash,
+            FileSize:   clientFile.FileSize,
+            ChunkSize:  DefaultChunkSize,
+            FilePath:   filePath,
+            TempPath:   filePath + ".tmp",
+        },
+        ctrl: ctrl,
+    }
+    _, _ = repo.Files.SaveFileRequest(d.GetID(), &d.ClientFileRequest, false)
+    So(repo.Files.MarkAutoFileRequest(d.GetID()), ShouldBeNil)
+    return d
+}
+
+func TestNetworkProfiles(t *testing.T) {
+    Convey("NetworkProfiles", t, func(c C) {
+        Convey("Set Before Load", func(c C) {
+            np := newNetworkProfiles()
+            c.So(np.set(NetworkCellular, &NetworkProfile{UploadRate: 1 << 10}), ShouldBeNil)
+            c.So(np.get(NetworkCellular).UploadRate, ShouldEqual, 1<<10)
+            c.So(np.get(NetworkWifi).AutoDownload, ShouldNotBeEmpty)
+        })
+        Convey("Auto Download Rules Are Checked On Start", func(c C) {
+            ctrl := &Controller{
+                profiles: newNetworkProfiles(),
+                onCancel: func(reqID string, clusterID int32, fileID, accessHash int64, hasError bool, peerID int64) {},
+            }
+
+            // photos larger than 1MB are not downloaded automatically on cellular networks
+            c.So(ctrl.profiles.setCurrent(NetworkCellular), ShouldBeNil)
+            d := newTestAutoDownload(ctrl)
+            c.So(d.Prepare(), ShouldEqual, domain.ErrAutoDownloadDisabled)
+            _, err := repo.Files.GetFileRequest(d.GetID())
+            c.So(err, ShouldNotBeNil)
+            c.So(repo.Files.IsAutoFileRequest(d.GetID()), ShouldBeFalse)
+
+            // the user has asked for the file, so the rules are not checked
+            d = newTestAutoDownload(ctrl)
+            c.So(repo.Files.UnmarkAutoFileRequest(d.GetID()), ShouldBeNil)
+            c.So(d.Prepare(), ShouldBeNil)
+            _ = d.file.Close()
+
+            c.So(ctrl.profiles.setCurrent(NetworkWifi), ShouldBeNil)
+            d = newTestAutoDownload(ctrl)
+            c.So(d.Prepare(), ShouldBeNil)
+            _ = d.file.Close()
+        })
+    })
+}
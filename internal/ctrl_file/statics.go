@@ -4,6 +4,7 @@ import (
     "fmt"
     "math"
 
+    "github.com/ronaksoft/river-msg/go/msg"
     mon "github.com/ronaksoft/river-sdk/internal/monitoring"
 )
 
@@ -38,3 +39,30 @@ func bestChunkSize(fileSize int64) int32 {
 func getRequestID(clusterID int32, fileID int64, accessHash uint64) string {
     return fmt.Sprintf("%d.%d.%d", clusterID, fileID, accessHash)
 }
+
+// getMediaType returns the media type of the document by its attributes, it is the same type that is used
+// to index the messages' media.
+func getMediaType(attrs []*msg.DocumentAttribute) msg.ClientMediaType {
+    mediaType := msg.ClientMediaType_ClientMediaNone
+    for _, da := range attrs {
+        switch da.Type {
+        case msg.DocumentAttributeType_AttributeTypeAudio:
+            a := &msg.DocumentAttributeAudio{}
+            _ = a.Unmarshal(da.Data)
+            if a.Voice {
+                mediaType = msg.ClientMediaType_ClientMediaVoice
+            } else {
+                mediaType = msg.ClientMediaType_ClientMediaAudio
+            }
+        case msg.DocumentAttributeType_AttributeTypeVideo, msg.DocumentAttributeType_AttributeTypePhoto:
+            mediaType = msg.ClientMediaType_ClientMediaMedia
+        case msg.DocumentAttributeType_AttributeTypeAnimated:
+            return msg.ClientMediaType_ClientMediaGif
+        case msg.DocumentAttributeType_AttributeTypeFile:
+            if mediaType == msg.ClientMediaType_ClientMediaNone {
+                mediaType = msg.ClientMediaType_ClientMediaFile
+            }
+        }
+    }
+    return mediaType
+}
@@ -0,0 +1,251 @@
+package fileCtrl
+
+import (
+    "context"
+    "errors"
+    "io"
+    "os"
+    "time"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/ctrl_file/executor"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "go.uber.org/zap"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+const (
+    // streamPollInterval is used when the download request is not running (e.g. it is waiting in the queue)
+    // and we cannot be notified when the parts are downloaded
+    streamPollInterval = 250 * time.Millisecond
+)
+
+var (
+    ErrStreamClosed = errors.New("stream is closed")
+    ErrStreamPaused = errors.New("stream is paused")
+)
+
+// Stream is an io.ReadSeeker over a file which might be still downloading. Read blocks until the part
+// which covers the current offset is downloaded, and that part is downloaded before the other parts
+// of the file.
+type Stream struct {
+    ctrl       *Controller
+    clientFile *msg.ClientFile
+    reqID      string
+    filePath   string
+    size       int64
+    offset     int64
+    ctx        context.Context
+    cf         context.CancelFunc
+
+    // file is the opened file we read from, it is the temp file until the download is finished
+    file     *os.File
+    complete bool
+    // available is the end offset of the readable bytes in the temp file
+    available int64
+}
+
+// OpenStream opens a stream over the file, if the file is not downloaded yet, it starts downloading it
+// with the highest priority. If the download has been paused, it is resumed.
+func (ctrl *Controller) OpenStream(clusterID int32, fileID int64, accessHash uint64) (*Stream, error) {
+    clientFile, err := repo.Files.Get(clusterID, fileID, accessHash)
+    if err != nil {
+        return nil, err
+    }
+    if clientFile.FileSize <= 0 {
+        // We need to know the size to find the parts
+        return nil, domain.ErrInvalidData
+    }
+
+    s := &Stream{
+        ctrl:       ctrl,
+        clientFile: clientFile,
+        reqID:      getRequestID(clusterID, fileID, accessHash),
+        filePath:   repo.Files.GetFilePath(clientFile),
+        size:       clientFile.FileSize,
+    }
+    s.ctx, s.cf = context.WithCancel(context.Background())
+
+    if _, err = os.Stat(s.filePath); err == nil {
+        return s, nil
+    }
+
+    _, err = ctrl.DownloadAsync(clusterID, fileID, accessHash, false)
+    switch err {
+    case nil, domain.ErrAlreadyDownloading:
+    default:
+        return nil, err
+    }
+    if ctrl.IsPaused(s.reqID) {
+        err = ctrl.ResumeRequest(s.reqID)
+        if err != nil {
+            return nil, err
+        }
+    }
+    _ = ctrl.SetRequestPriority(s.reqID, executor.PriorityHigh)
+
+    logger.Info("opens Stream", zap.String("ReqID", s.reqID), zap.Int64("Size", s.size))
+    return s, nil
+}
+
+func (s *Stream) Size() int64 {
+    return s.size
+}
+
+func (s *Stream) MimeType() string {
+    return s.clientFile.MimeType
+}
+
+func (s *Stream) Read(p []byte) (int, error) {
+    if s.offset >= s.size {
+        return 0, io.EOF
+    }
+    err := s.waitFor(s.offset)
+    if err != nil {
+        return 0, err
+    }
+
+    end := s.size
+    if !s.complete && s.available < end {
+        end = s.available
+    }
+    if int64(len(p)) > end-s.offset {
+        p = p[:end-s.offset]
+    }
+    n, err := s.file.ReadAt(p, s.offset)
+    s.offset += int64(n)
+    if err == io.EOF && n > 0 {
+        err = nil
+    }
+    return n, err
+}
+
+func (s *Stream) Seek(offset int64, whence int) (int64, error) {
+    switch whence {
+    case io.SeekStart:
+    case io.SeekCurrent:
+        offset += s.offset
+    case io.SeekEnd:
+        offset += s.size
+    default:
+        return 0, domain.ErrInvalidData
+    }
+    if offset < 0 {
+        return 0, domain.ErrInvalidData
+    }
+    s.offset = offset
+    return offset, nil
+}
+
+func (s *Stream) Close() error {
+    s.cf()
+    if s.file != nil {
+        return s.file.Close()
+    }
+    return nil
+}
+
+// waitFor blocks until the byte at offset is downloaded. If the download is paused meanwhile, it returns
+// ErrStreamPaused, since the byte would not be downloaded until the download is resumed.
+func (s *Stream) waitFor(offset int64) error {
+    for {
+        if s.complete {
+            return nil
+        }
+        if s.ctx.Err() != nil {
+            return ErrStreamClosed
+        }
+
+        // If the download is finished we switch to the final file
+        if _, err := os.Stat(s.filePath); err == nil {
+            return s.open(s.filePath, true, s.size)
+        }
+
+        var (
+            signal    <-chan struct{}
+            chunkSize int32
+            tempPath  string
+            finished  []int32
+        )
+        if v, ok := s.ctrl.activeDownloads.Load(s.reqID); ok {
+            d := v.(*DownloadRequest)
+            d.mtx.Lock()
+            chunkSize, tempPath = d.ChunkSize, d.TempPath
+            finished = append(finished, d.FinishedParts...)
+            d.mtx.Unlock()
+            signal = d.waitSignal()
+            if chunkSize > 0 {
+                d.prioritize(int32(offset / int64(chunkSize)))
+            }
+        } else {
+            req, err := repo.Files.GetFileRequest(s.reqID)
+            if err != nil {
+                // The request is removed and the file does not exist, so it has been canceled
+                if _, err := os.Stat(s.filePath); err == nil {
+                    continue
+                }
+                return domain.ErrNotFound
+            }
+            chunkSize, tempPath, finished = req.ChunkSize, req.TempPath, req.FinishedParts
+        }
+
+        if chunkSize > 0 && hasPart(finished, int32(offset/int64(chunkSize))) {
+            // Extend the readable range over the next downloaded parts
+            end := (offset/int64(chunkSize) + 1) * int64(chunkSize)
+            for end < s.size && hasPart(finished, int32(end/int64(chunkSize))) {
+                end += int64(chunkSize)
+            }
+            if end > s.size {
+                end = s.size
+            }
+            // If we could not open the temp file, it has been renamed meanwhile, so we try again
+            if err := s.open(tempPath, false, end); err == nil {
+                return nil
+            }
+        }
+        if s.ctrl.IsPaused(s.reqID) {
+            return ErrStreamPaused
+        }
+
+        select {
+        case <-signal:
+        case <-time.After(streamPollInterval):
+        case <-s.ctx.Done():
+            return ErrStreamClosed
+        }
+    }
+}
+
+func (s *Stream) open(filePath string, complete bool, available int64) error {
+    if s.file == nil || s.file.Name() != filePath {
+        f, err := os.Open(filePath)
+        if err != nil {
+            return err
+        }
+        if s.file != nil {
+            _ = s.file.Close()
+        }
+        s.file = f
+    }
+    s.complete = complete
+    s.available = available
+    return nil
+}
+
+func hasPart(parts []int32, partIndex int32) bool {
+    for _, idx := range parts {
+        if idx == partIndex {
+            return true
+        }
+    }
+    return false
+}
@@ -0,0 +1,104 @@
+package fileCtrl
+
+import (
+    "fmt"
+    "net"
+    "net/http"
+    "strconv"
+    "strings"
+    "time"
+
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/rony/tools"
+    "go.uber.org/zap"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+// streamServer is a loopback http server which serves the files through Streams, hence media players could
+// play the files while they are being downloaded. It supports Range requests. The urls contain a random
+// token, so other apps on the device could not read the files.
+type streamServer struct {
+    ctrl     *Controller
+    token    string
+    listener net.Listener
+    server   *http.Server
+}
+
+func (ss *streamServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+    // Path: /{token}/{clusterID}/{fileID}/{accessHash}
+    parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+    if len(parts) != 4 || parts[0] != ss.token {
+        http.NotFound(w, r)
+        return
+    }
+    clusterID, err1 := strconv.ParseInt(parts[1], 10, 32)
+    fileID, err2 := strconv.ParseInt(parts[2], 10, 64)
+    accessHash, err3 := strconv.ParseUint(parts[3], 10, 64)
+    if err1 != nil || err2 != nil || err3 != nil {
+        http.NotFound(w, r)
+        return
+    }
+
+    s, err := ss.ctrl.OpenStream(int32(clusterID), fileID, accessHash)
+    if err != nil {
+        logger.Warn("got error on opening Stream", zap.Error(err))
+        http.NotFound(w, r)
+        return
+    }
+    defer s.Close()
+
+    if mimeType := s.MimeType(); mimeType != "" {
+        w.Header().Set("Content-Type", mimeType)
+    }
+    http.ServeContent(w, r, "", time.Time{}, s)
+}
+
+// StreamURL returns a loopback url which serves the file, the file is downloaded on demand and the
+// requested ranges are downloaded first.
+func (ctrl *Controller) StreamURL(clusterID int32, fileID int64, accessHash uint64) (string, error) {
+    ctrl.streamServerLock.Lock()
+    defer ctrl.streamServerLock.Unlock()
+    if ctrl.streamServer == nil {
+        l, err := net.Listen("tcp", "127.0.0.1:0")
+        if err != nil {
+            return "", err
+        }
+        ss := &streamServer{
+            ctrl:     ctrl,
+            token:    tools.RandomID(32),
+            listener: l,
+        }
+        ss.server = &http.Server{
+            Handler:     ss,
+            ReadTimeout: domain.HttpRequestTimeout,
+        }
+        go func() {
+            err := ss.server.Serve(l)
+            if err != http.ErrServerClosed {
+                logger.Warn("stream server stopped", zap.Error(err))
+            }
+        }()
+        ctrl.streamServer = ss
+        logger.Info("started stream server", zap.String("Addr", l.Addr().String()))
+    }
+    return fmt.Sprintf("http://%s/%s/%d/%d/%d",
+        ctrl.streamServer.listener.Addr().String(), ctrl.streamServer.token, clusterID, fileID, accessHash,
+    ), nil
+}
+
+func (ctrl *Controller) stopStreamServer() {
+    ctrl.streamServerLock.Lock()
+    defer ctrl.streamServerLock.Unlock()
+    if ctrl.streamServer != nil {
+        _ = ctrl.streamServer.server.Close()
+        ctrl.streamServer = nil
+    }
+}
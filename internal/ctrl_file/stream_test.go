@@ -0,0 +1,260 @@
+package fileCtrl
+
+import (
+    "bytes"
+    "fmt"
+    "hash/crc32"
+    "io"
+    "io/ioutil"
+    "net/http"
+    "os"
+    "testing"
+    "time"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/ctrl_file/executor"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+const testStreamChunkSize = 1024
+
+var testStreamCtrl *Controller
+
+// getTestStreamController returns a controller whose downloader is not started, so the streams are fed by
+// the tests instead of the network. The executor holds its queue open, hence it is created once.
+func getTestStreamController(t *testing.T) *Controller {
+    if testStreamCtrl != nil {
+        return testStreamCtrl
+    }
+    ctrl := &Controller{}
+    var err error
+    ctrl.downloader, err = executor.NewExecutor("./_hdd/stream", "downloader", func(data []byte) executor.Request {
+        r := &DownloadRequest{ctrl: ctrl}
+        _ = r.Unmarshal(data)
+        return r
+    })
+    if err != nil {
+        t.Fatal(err)
+    }
+    testStreamCtrl = ctrl
+    return ctrl
+}
+
+// newTestStreamDownload registers a running download request of a file with 10 parts, none of the parts
+// are downloaded yet. The repo is initialized by file_test.go.
+func newTestStreamDownload(ctrl *Controller) (*DownloadRequest, []byte) {
+    content := make([]byte, 10*testStreamChunkSize)
+    for i := range content {
+        content[i] = byte(i % 251)
+    }
+    clientFile := &msg.ClientFile{
+        ClusterID:  1,
+        FileID:     domain.RandomInt63(),
+        AccessHash: 1,
+        Type:       msg.ClientFileType_Message,
+        MimeType:   "video/mp4",
+        Extension:  ".mp4",
+        FileSize:   int64(len(content)),
+    }
+    So(repo.Files.Save(clientFile), ShouldBeNil)
+    filePath := repo.Files.GetFilePath(clientFile)
+    d := &DownloadRequest{
+        ClientFileRequest: msg.ClientFileRequest{
+            ClusterID:        clientFile.ClusterID,
+            FileID:           clientFile.FileID,
+            AccessHash:       clientFile.AccessHash,
+            FileSize:         clientFile.FileSize,
+            ChunkSize:        testStreamChunkSize,
+            FilePath:         filePath,
+            TempPath:         fmt.Sprintf("%s.tmp", filePath),
+            SkipDelegateCall: true,
+        },
+        ctrl: ctrl,
+    }
+    _, _ = repo.Files.SaveFileRequest(d.GetID(), &d.ClientFileRequest, false)
+    So(d.Prepare(), ShouldBeNil)
+    return d, content
+}
+
+func writeTestPart(d *DownloadRequest, content []byte, partIndex int32) {
+    offset, size := d.partRange(partIndex, d.FileSize)
+    part := content[offset : offset+size]
+    _, err := d.file.WriteAt(part, offset)
+    So(err, ShouldBeNil)
+    d.addToDownloaded(partIndex, crc32.ChecksumIEEE(part))
+}
+
+type testReadResult struct {
+    b   []byte
+    err error
+}
+
+func readAsync(s *Stream, n int) chan testReadResult {
+    ch := make(chan testReadResult, 1)
+    go func() {
+        b := make([]byte, n)
+        n, err := s.Read(b)
+        ch <- testReadResult{b: b[:n], err: err}
+    }()
+    return ch
+}
+
+func TestStream(t *testing.T) {
+    ctrl := getTestStreamController(t)
+    Convey("Stream", t, func(c C) {
+        Convey("Read Before Part Is Downloaded", func(c C) {
+            d, content := newTestStreamDownload(ctrl)
+            s, err := ctrl.OpenStream(d.ClusterID, d.FileID, d.AccessHash)
+            c.So(err, ShouldBeNil)
+            defer s.Close()
+
+            _, err = s.Seek(5*testStreamChunkSize+10, io.SeekStart)
+            c.So(err, ShouldBeNil)
+            ch := readAsync(s, 2*testStreamChunkSize)
+            select {
+            case <-ch:
+                c.So("read must block until the part is downloaded", ShouldBeEmpty)
+            case <-time.After(100 * time.Millisecond):
+            }
+            // the stream has asked for the part to be downloaded before the others
+            c.So(<-d.urgent, ShouldEqual, 5)
+
+            writeTestPart(d, content, 5)
+            select {
+            case r := <-ch:
+                c.So(r.err, ShouldBeNil)
+                // only the downloaded part is readable
+                c.So(r.b, ShouldResemble, content[5*testStreamChunkSize+10:6*testStreamChunkSize])
+            case <-time.After(time.Second):
+                c.So("read is not unblocked by the downloaded part", ShouldBeEmpty)
+            }
+
+            // the readable range extends over the next downloaded parts
+            writeTestPart(d, content, 6)
+            writeTestPart(d, content, 7)
+            b := make([]byte, 2*testStreamChunkSize)
+            n, err := s.Read(b)
+            c.So(err, ShouldBeNil)
+            c.So(b[:n], ShouldResemble, content[6*testStreamChunkSize:8*testStreamChunkSize])
+        })
+        Convey("Switch To Completed File", func(c C) {
+            d, content := newTestStreamDownload(ctrl)
+            s, err := ctrl.OpenStream(d.ClusterID, d.FileID, d.AccessHash)
+            c.So(err, ShouldBeNil)
+            defer s.Close()
+
+            writeTestPart(d, content, 0)
+            b := make([]byte, testStreamChunkSize)
+            _, err = io.ReadFull(s, b)
+            c.So(err, ShouldBeNil)
+            c.So(b, ShouldResemble, content[:testStreamChunkSize])
+
+            for partIndex := int32(1); partIndex < d.TotalParts; partIndex++ {
+                writeTestPart(d, content, partIndex)
+            }
+            _ = d.file.Close()
+            c.So(os.Rename(d.TempPath, d.FilePath), ShouldBeNil)
+            d.complete()
+
+            rest, err := ioutil.ReadAll(s)
+            c.So(err, ShouldBeNil)
+            c.So(rest, ShouldResemble, content[testStreamChunkSize:])
+            c.So(s.complete, ShouldBeTrue)
+        })
+        Convey("Range Request", func(c C) {
+            d, content := newTestStreamDownload(ctrl)
+            defer ctrl.stopStreamServer()
+            url, err := ctrl.StreamURL(d.ClusterID, d.FileID, d.AccessHash)
+            c.So(err, ShouldBeNil)
+
+            writeTestPart(d, content, 2)
+            writeTestPart(d, content, 3)
+            req, _ := http.NewRequest(http.MethodGet, url, nil)
+            req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", 2*testStreamChunkSize+100, 4*testStreamChunkSize-1))
+            res, err := http.DefaultClient.Do(req)
+            c.So(err, ShouldBeNil)
+            defer res.Body.Close()
+            c.So(res.StatusCode, ShouldEqual, http.StatusPartialContent)
+            c.So(res.Header.Get("Content-Type"), ShouldEqual, "video/mp4")
+            c.So(res.Header.Get("Content-Range"), ShouldEqual,
+                fmt.Sprintf("bytes %d-%d/%d", 2*testStreamChunkSize+100, 4*testStreamChunkSize-1, len(content)),
+            )
+            body, err := ioutil.ReadAll(res.Body)
+            c.So(err, ShouldBeNil)
+            c.So(bytes.Equal(body, content[2*testStreamChunkSize+100:4*testStreamChunkSize]), ShouldBeTrue)
+
+            // urls with a wrong token are rejected
+            res2, err := http.Get(fmt.Sprintf("http://%s/%s/%d/%d/%d",
+                ctrl.streamServer.listener.Addr().String(), "wrongToken", d.ClusterID, d.FileID, d.AccessHash,
+            ))
+            c.So(err, ShouldBeNil)
+            _ = res2.Body.Close()
+            c.So(res2.StatusCode, ShouldEqual, http.StatusNotFound)
+        })
+        Convey("Close Stream", func(c C) {
+            d, _ := newTestStreamDownload(ctrl)
+            s, err := ctrl.OpenStream(d.ClusterID, d.FileID, d.AccessHash)
+            c.So(err, ShouldBeNil)
+
+            ch := readAsync(s, testStreamChunkSize)
+            time.Sleep(50 * time.Millisecond)
+            c.So(s.Close(), ShouldBeNil)
+            select {
+            case r := <-ch:
+                c.So(r.err, ShouldEqual, ErrStreamClosed)
+            case <-time.After(time.Second):
+                c.So("read is not unblocked by closing the stream", ShouldBeEmpty)
+            }
+            d.cancel(nil)
+        })
+        Convey("Pause Download", func(c C) {
+            d, content := newTestStreamDownload(ctrl)
+            s, err := ctrl.OpenStream(d.ClusterID, d.FileID, d.AccessHash)
+            c.So(err, ShouldBeNil)
+            defer s.Close()
+
+            writeTestPart(d, content, 0)
+            ch := readAsync(s, 2*testStreamChunkSize)
+            c.So((<-ch).err, ShouldBeNil)
+            ch = readAsync(s, testStreamChunkSize)
+            time.Sleep(50 * time.Millisecond)
+            c.So(ctrl.PauseRequest(d.GetID()), ShouldBeNil)
+            select {
+            case r := <-ch:
+                c.So(r.err, ShouldEqual, ErrStreamPaused)
+            case <-time.After(time.Second):
+                c.So("read is not unblocked by pausing the download", ShouldBeEmpty)
+            }
+            _ = d.file.Close()
+            d.cancel(nil)
+        })
+        Convey("Cancel Download", func(c C) {
+            d, _ := newTestStreamDownload(ctrl)
+            s, err := ctrl.OpenStream(d.ClusterID, d.FileID, d.AccessHash)
+            c.So(err, ShouldBeNil)
+            defer s.Close()
+
+            ch := readAsync(s, testStreamChunkSize)
+            time.Sleep(50 * time.Millisecond)
+            _ = d.file.Close()
+            d.cancel(nil)
+            select {
+            case r := <-ch:
+                c.So(r.err, ShouldEqual, domain.ErrNotFound)
+            case <-time.After(time.Second):
+                c.So("read is not unblocked by canceling the download", ShouldBeEmpty)
+            }
+        })
+    })
+}
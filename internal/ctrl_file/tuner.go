@@ -0,0 +1,152 @@
+package fileCtrl
+
+import (
+    "sync"
+    "time"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+const (
+    tunerAlpha        = 0.2
+    tunerMinSamples   = 5
+    tunerLatencyLow   = 2 * time.Second
+    tunerLatencyHigh  = 8 * time.Second
+    tunerFailRateLow  = 0.05
+    tunerFailRateHigh = 0.2
+)
+
+// chunkTuner adapts the chunk size of the uploads to the network condition. It keeps the moving averages
+// of the parts' latency and failure rate, and moves to a smaller chunk size if parts are slow or failing,
+// and to a bigger one if the network is doing well.
+type chunkTuner struct {
+    mtx         sync.Mutex
+    level       int // index of the current chunk size in chunkSizesKB, -1 if we have no samples yet
+    sinceChange int
+    latency     float64 // seconds
+    failRate    float64
+}
+
+func newChunkTuner() *chunkTuner {
+    return &chunkTuner{
+        level: -1,
+    }
+}
+
+// record must be called with the result of each uploaded part
+func (t *chunkTuner) record(chunkSize int32, d time.Duration, failed bool) {
+    t.mtx.Lock()
+    defer t.mtx.Unlock()
+
+    failure := 0.0
+    if failed {
+        failure = 1.0
+    }
+    if t.level < 0 {
+        t.level = chunkSizeLevel(chunkSize)
+        t.failRate = failure
+        if !failed {
+            t.latency = d.Seconds()
+        }
+    } else {
+        t.failRate = tunerAlpha*failure + (1-tunerAlpha)*t.failRate
+        if !failed {
+            t.latency = tunerAlpha*d.Seconds() + (1-tunerAlpha)*t.latency
+        }
+    }
+
+    t.sinceChange++
+    if t.sinceChange < tunerMinSamples {
+        return
+    }
+    switch {
+    case t.failRate > tunerFailRateHigh || t.latency > tunerLatencyHigh.Seconds():
+        if t.level > 0 {
+            t.level--
+            t.sinceChange = 0
+        }
+    case t.failRate < tunerFailRateLow && t.latency < tunerLatencyLow.Seconds():
+        if t.level < len(chunkSizesKB)-1 {
+            t.level++
+            t.sinceChange = 0
+        }
+    }
+}
+
+// suggest returns the chunk size for a new upload. The number of parts will not exceed MaxParts.
+func (t *chunkTuner) suggest(fileSize int64) int32 {
+    if fileSize <= MaxChunkSize {
+        return DefaultChunkSize
+    }
+    t.mtx.Lock()
+    level := t.level
+    t.mtx.Unlock()
+    if level < 0 {
+        return bestChunkSize(fileSize)
+    }
+    if cs, ok := fitChunkSize(level, fileSize, MaxParts); ok {
+        return cs
+    }
+    return chunkSizesKB[len(chunkSizesKB)-1] << 10
+}
+
+// suggestRemaining returns the chunk size for the remaining bytes of a running upload, which must be cut
+// into maxParts parts at most. It returns false if there is no sample yet.
+func (t *chunkTuner) suggestRemaining(remaining int64, maxParts int64) (int32, bool) {
+    t.mtx.Lock()
+    level := t.level
+    t.mtx.Unlock()
+    if level < 0 {
+        return 0, false
+    }
+    return fitChunkSize(level, remaining, maxParts)
+}
+
+// fitChunkSize returns the smallest chunk size, starting from the level, which keeps the number of parts
+// within maxParts
+func fitChunkSize(level int, size int64, maxParts int64) (int32, bool) {
+    for _, cs := range chunkSizesKB[level:] {
+        if int64(cs<<10)*maxParts >= size {
+            return cs << 10, true
+        }
+    }
+    return 0, false
+}
+
+// smallerChunkSize returns the next smaller chunk size which keeps the number of parts within maxParts
+func smallerChunkSize(size int64, chunkSize int32, maxParts int64) (int32, bool) {
+    for i := len(chunkSizesKB) - 1; i >= 0; i-- {
+        cs := chunkSizesKB[i] << 10
+        if cs >= chunkSize {
+            continue
+        }
+        if int64(cs)*maxParts < size {
+            return 0, false
+        }
+        return cs, true
+    }
+    return 0, false
+}
+
+func countParts(size int64, chunkSize int32) int32 {
+    if chunkSize <= 0 {
+        return 0
+    }
+    return int32((size + int64(chunkSize) - 1) / int64(chunkSize))
+}
+
+func chunkSizeLevel(chunkSize int32) int {
+    for idx, cs := range chunkSizesKB {
+        if cs<<10 >= chunkSize {
+            return idx
+        }
+    }
+    return len(chunkSizesKB) - 1
+}
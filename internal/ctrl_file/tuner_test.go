@@ -0,0 +1,73 @@
+package fileCtrl
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func TestChunkTuner(t *testing.T) {
+	Convey("ChunkTuner", t, func(c C) {
+		Convey("Slow Network", func(c C) {
+			ct := newChunkTuner()
+			for i := 0; i < 10; i++ {
+				ct.record(512<<10, 10*time.Second, false)
+			}
+			c.So(ct.suggest(10<<20), ShouldBeLessThan, 512<<10)
+		})
+		Convey("Failing Network", func(c C) {
+			ct := newChunkTuner()
+			for i := 0; i < 10; i++ {
+				ct.record(256<<10, time.Second, i%2 == 0)
+			}
+			c.So(ct.suggest(10<<20), ShouldBeLessThan, 256<<10)
+		})
+		Convey("Good Network", func(c C) {
+			ct := newChunkTuner()
+			for i := 0; i < 10; i++ {
+				ct.record(128<<10, 100*time.Millisecond, false)
+			}
+			c.So(ct.suggest(10<<20), ShouldBeGreaterThan, 128<<10)
+		})
+		Convey("Respect MaxParts", func(c C) {
+			ct := newChunkTuner()
+			for i := 0; i < 50; i++ {
+				ct.record(64<<10, 20*time.Second, true)
+			}
+			fileSize := int64(700 << 20)
+			c.So(int64(ct.suggest(fileSize))*MaxParts, ShouldBeGreaterThanOrEqualTo, fileSize)
+			_, ok := smallerChunkSize(fileSize, 256<<10, MaxParts)
+			c.So(ok, ShouldBeFalse)
+			cs, ok := smallerChunkSize(10<<20, 256<<10, MaxParts)
+			c.So(ok, ShouldBeTrue)
+			c.So(cs, ShouldEqual, 128<<10)
+		})
+		Convey("Remaining Parts", func(c C) {
+			ct := newChunkTuner()
+			_, ok := ct.suggestRemaining(10<<20, MaxParts)
+			c.So(ok, ShouldBeFalse)
+			for i := 0; i < 10; i++ {
+				ct.record(64<<10, 100*time.Millisecond, false)
+			}
+			cs, ok := ct.suggestRemaining(10<<20, MaxParts)
+			c.So(ok, ShouldBeTrue)
+			c.So(cs, ShouldEqual, 256<<10)
+			// the parts which have been cut already are counted
+			cs, ok = ct.suggestRemaining(10<<20, 20)
+			c.So(ok, ShouldBeTrue)
+			c.So(cs, ShouldEqual, 512<<10)
+			_, ok = ct.suggestRemaining(10<<20, 10)
+			c.So(ok, ShouldBeFalse)
+		})
+	})
+}
@@ -36,10 +36,25 @@ type UploadRequest struct {
     file          *os.File
     parts         chan int32
     done          chan struct{}
-    lastPartSent  bool
     progress      int64
     failedActions int32
     startTime     time.Time
+
+    // cut holds the ranges of the parts which have been cut from the file so far. Parts are cut when they
+    // are dispatched, hence each part gets the chunk size which suits the network condition at that moment.
+    // The ranges are saved with the uploaded parts, since they are needed when the request is prepared again.
+    cut []uploadPart
+
+    // gen is increased whenever the upload is restarted from the beginning, hence the results of the
+    // actions of the older generations will be ignored.
+    gen          int32
+    partAttempts map[int32]int32
+    err          error
+}
+
+type uploadPart struct {
+    offset int64
+    size   int32
 }
 
 func (u *UploadRequest) checkSha256() (err error) {
@@ -61,6 +76,7 @@ func (u *UploadRequest) checkSha256() (err error) {
                 u.cfr.AccessHash = x.AccessHash
                 u.cfr.FileID = x.FileID
                 u.cfr.TotalParts = -1 // dirty hack, which queue.Start() knows the upload request is completed
+                _ = repo.Files.SaveSha256(x.ClusterID, x.FileID, x.AccessHash, u.cfr.FileSha256)
                 return
             case rony.C_Error:
                 x := &rony.Error{}
@@ -98,11 +114,15 @@ func (u *UploadRequest) isUploaded(partIndex int32) bool {
     return false
 }
 
-func (u *UploadRequest) addToUploaded(partIndex int32) {
+func (u *UploadRequest) addToUploaded(gen, partIndex int32) {
     if u.isUploaded(partIndex) {
         return
     }
     u.mtx.Lock()
+    if gen != u.gen {
+        u.mtx.Unlock()
+        return
+    }
     u.cfr.FinishedParts = append(u.cfr.FinishedParts, partIndex)
     progress := int64(float64(len(u.cfr.FinishedParts)) / float64(u.cfr.TotalParts) * 100)
     skipOnProgress := false
@@ -111,20 +131,240 @@ func (u *UploadRequest) addToUploaded(partIndex int32) {
     } else {
         u.progress = progress
     }
+    sizes := make([]int32, 0, len(u.cut))
+    for _, p := range u.cut {
+        sizes = append(sizes, p.size)
+    }
     u.mtx.Unlock()
 
+    _ = repo.Files.SaveUploadParts(u.GetID(), sizes)
     saved, _ := repo.Files.SaveFileRequest(u.GetID(), u.cfr, true)
     if saved && !u.cfr.SkipDelegateCall && !skipOnProgress {
         u.ctrl.onProgressChanged(u.GetID(), 0, u.cfr.FileID, 0, progress, u.cfr.PeerID)
     }
 }
 
+// retryPart pushes back the failed part to be uploaded again, unless it has been failed too many times.
+func (u *UploadRequest) retryPart(ctx context.Context, gen, partIndex int32) {
+    atomic.AddInt32(&u.failedActions, 1)
+    u.mtx.Lock()
+    if gen != u.gen {
+        u.mtx.Unlock()
+        return
+    }
+    u.partAttempts[partIndex]++
+    attempts := u.partAttempts[partIndex]
+    if attempts > PartRetryMaxAttempts {
+        u.err = domain.ErrLimitReached
+        u.mtx.Unlock()
+        return
+    }
+    u.mtx.Unlock()
+
+    // Back off before retrying the part, more attempts more waiting
+    t := time.NewTimer(RetryWaitTime * time.Duration(attempts))
+    defer t.Stop()
+    select {
+    case <-t.C:
+    case <-ctx.Done():
+    }
+    u.parts <- partIndex
+}
+
+// split resets the parts, hence they are cut from the beginning of the file. The number of parts is fixed
+// here by the current chunk size, since every part carries it to the server. It must be called while no
+// action is running or u.mtx is locked.
+func (u *UploadRequest) split() {
+    u.cut = u.cut[:0]
+    u.cfr.FinishedParts = u.cfr.FinishedParts[:0]
+    u.cfr.TotalParts = countParts(u.cfr.FileSize, u.cfr.ChunkSize)
+    u.partAttempts = make(map[int32]int32)
+}
+
+// restoreCut restores the parts which have been cut before the request is prepared again, e.g. after a pause
+// or a restart, and pushes the parts which are not uploaded yet. It returns false if there is no saved part
+// or the file does not match the saved parts anymore. It must be called while no action is running.
+func (u *UploadRequest) restoreCut() bool {
+    sizes := repo.Files.GetUploadParts(u.GetID())
+    if len(sizes) == 0 || int32(len(sizes)) > u.cfr.TotalParts {
+        return false
+    }
+    cut := make([]uploadPart, 0, len(sizes))
+    offset := int64(0)
+    for _, size := range sizes {
+        cut = append(cut, uploadPart{offset: offset, size: size})
+        offset += int64(size)
+    }
+    if !canCut(u.cfr.FileSize-offset, int64(u.cfr.TotalParts)-int64(len(cut))) {
+        return false
+    }
+
+    u.cut = cut
+    u.partAttempts = make(map[int32]int32)
+    finishedParts := u.cfr.FinishedParts[:0]
+    for _, partIndex := range u.cfr.FinishedParts {
+        if partIndex >= 0 && partIndex < int32(len(cut)) {
+            finishedParts = append(finishedParts, partIndex)
+        }
+    }
+    u.cfr.FinishedParts = finishedParts
+
+    pushed := false
+    for partIndex := int32(0); partIndex < int32(len(cut)); partIndex++ {
+        if !u.isUploaded(partIndex) {
+            u.parts <- partIndex
+            pushed = true
+        }
+    }
+    if pushed {
+        return true
+    }
+
+    // All the cut parts are uploaded, but the next part might be the last one which NextAction does not cut.
+    // If there is no part left, we send the last part again to complete the upload.
+    if partIndex, ok := u.cutPart(true); ok {
+        u.parts <- partIndex
+        return true
+    }
+    lastPart := int32(len(cut) - 1)
+    u.cfr.FinishedParts = u.cfr.FinishedParts[:0]
+    for partIndex := int32(0); partIndex < lastPart; partIndex++ {
+        u.cfr.FinishedParts = append(u.cfr.FinishedParts, partIndex)
+    }
+    u.parts <- lastPart
+    return true
+}
+
+// cutOffset returns the offset of the bytes which have not been cut into parts yet. It must be called while
+// u.mtx is locked.
+func (u *UploadRequest) cutOffset() int64 {
+    if len(u.cut) == 0 {
+        return 0
+    }
+    p := u.cut[len(u.cut)-1]
+    return p.offset + int64(p.size)
+}
+
+// cutPart cuts the next part from the remaining bytes of the file by the current chunk size, as long as the
+// remaining parts could still cover the rest of the file, since the number of parts does not change. The
+// last part of a multi-part upload is cut only if lastPart is set, since it must be sent after all the other
+// parts are uploaded. It must be called while u.mtx is locked.
+func (u *UploadRequest) cutPart(lastPart bool) (int32, bool) {
+    offset := u.cutOffset()
+    remaining := u.cfr.FileSize - offset
+    parts := int64(u.cfr.TotalParts) - int64(len(u.cut))
+    if remaining <= 0 || parts <= 0 {
+        return 0, false
+    }
+    size := remaining
+    if parts > 1 {
+        size = partSize(remaining, parts, u.cfr.ChunkSize)
+    } else if !lastPart && len(u.cut) > 0 {
+        return 0, false
+    }
+    u.cut = append(u.cut, uploadPart{offset: offset, size: int32(size)})
+    return int32(len(u.cut) - 1), true
+}
+
+// partSize returns the size of the next part of the remaining bytes, which must be cut into the given number
+// of parts. It is the chunk size unless the other parts could not cover the rest of the bytes by the chunk
+// sizes we use.
+func partSize(remaining, parts int64, chunkSize int32) int64 {
+    minSize := remaining - (parts-1)*MaxChunkSize
+    maxSize := remaining - (parts-1)*int64(chunkSizesKB[0]<<10)
+    size := int64(chunkSize)
+    if size > maxSize {
+        size = maxSize
+    }
+    if size < minSize {
+        size = minSize
+    }
+    if size <= 0 {
+        size = (remaining + parts - 1) / parts
+    }
+    return size
+}
+
+// canCut returns true if the remaining bytes could be cut into the given number of parts by the chunk
+// sizes we use
+func canCut(remaining, parts int64) bool {
+    if parts <= 0 {
+        return remaining == 0
+    }
+    return remaining > (parts-1)*int64(chunkSizesKB[0]<<10) && remaining <= parts*MaxChunkSize
+}
+
+// adapt changes the chunk size of the parts which have not been cut yet. It moves to a smaller chunk size if
+// the parts are failing too much, otherwise it follows the tuner which knows how the recent parts of all the
+// uploads were doing. The parts which have been cut keep their ranges, since they might be on the server, and
+// the number of parts does not change, hence the chunk size is only followed as far as partSize allows.
+func (u *UploadRequest) adapt(smaller bool) {
+    u.mtx.Lock()
+    defer u.mtx.Unlock()
+    remaining := u.cfr.FileSize - u.cutOffset()
+    if remaining <= 0 {
+        return
+    }
+    var (
+        chunkSize int32
+        ok        bool
+    )
+    if smaller {
+        chunkSize, ok = smallerChunkSize(remaining, u.cfr.ChunkSize, MaxParts)
+    } else {
+        chunkSize, ok = u.ctrl.chunkTuner.suggestRemaining(remaining, MaxParts)
+    }
+    if !ok || chunkSize == u.cfr.ChunkSize {
+        return
+    }
+
+    logger.Debug("changes UploadRequest chunk size",
+        zap.String("ReqID", u.GetID()),
+        zap.Int32("From", u.cfr.ChunkSize),
+        zap.Int32("To", chunkSize),
+        zap.Int("CutParts", len(u.cut)),
+    )
+    u.cfr.ChunkSize = chunkSize
+}
+
+// shrink restarts the upload with a smaller chunk size. It is only possible if no part has been uploaded
+// yet, otherwise the parts which are on the server would not match the new ones.
+func (u *UploadRequest) shrink() bool {
+    u.mtx.Lock()
+    defer u.mtx.Unlock()
+    if len(u.cfr.FinishedParts) > 0 {
+        return false
+    }
+    chunkSize, ok := smallerChunkSize(u.cfr.FileSize, u.cfr.ChunkSize, MaxParts)
+    if !ok {
+        return false
+    }
+
+    logger.Info("shrinks UploadRequest chunk size",
+        zap.String("ReqID", u.GetID()),
+        zap.Int32("From", u.cfr.ChunkSize),
+        zap.Int32("To", chunkSize),
+    )
+    u.gen++
+    u.err = nil
+    u.cfr.ChunkSize = chunkSize
+
+    // Drain the parts of the old generation
+    for {
+        select {
+        case <-u.parts:
+            continue
+        default:
+        }
+        break
+    }
+    u.split()
+    return true
+}
+
 func (u *UploadRequest) reset() {
     // Reset failed counter
     atomic.StoreInt32(&u.failedActions, 0)
-
-    // Reset the uploaded list
-    u.resetUploadedList()
     u.progress = 0
 
     if u.file != nil {
@@ -206,36 +446,21 @@ func (u *UploadRequest) Prepare() error {
         return err
     }
 
-    // If chunk size is not set recalculate it
+    // If chunk size is not set, we ask the tuner which knows how the recent uploads were doing
     if u.cfr.ChunkSize <= 0 {
-        u.cfr.ChunkSize = bestChunkSize(u.cfr.FileSize)
-    }
-
-    // Calculate number of parts based on our chunk size
-    dividend := int32(u.cfr.FileSize / int64(u.cfr.ChunkSize))
-    if u.cfr.FileSize%int64(u.cfr.ChunkSize) > 0 {
-        u.cfr.TotalParts = dividend + 1
-    } else {
-        u.cfr.TotalParts = dividend
+        u.cfr.ChunkSize = u.ctrl.chunkTuner.suggest(u.cfr.FileSize)
     }
 
-    // Reset FinishedParts if all parts are finished. Probably something went wrong, it is better to retry
-    if int32(len(u.cfr.FinishedParts)) == u.cfr.TotalParts {
-        u.cfr.FinishedParts = u.cfr.FinishedParts[:0]
-    }
-
-    // Prepare Channels to active the system dynamics
-    u.parts = make(chan int32, u.cfr.TotalParts)
+    // Prepare Channels to active the system dynamics. The parts channel carries the parts which must be
+    // sent again and the last part, we make room for the maximum number of parts.
+    u.parts = make(chan int32, MaxParts)
     u.done = make(chan struct{}, 1)
-    maxPartIndex := u.cfr.TotalParts - 1
-    if u.cfr.TotalParts == 1 {
-        maxPartIndex = u.cfr.TotalParts
-    }
-    for partIndex := int32(0); partIndex < maxPartIndex; partIndex++ {
-        if u.isUploaded(partIndex) {
-            continue
-        }
-        u.parts <- partIndex
+
+    // The parts which have been uploaded before the request is prepared again are kept, otherwise we start
+    // from the beginning of the file
+    if !u.restoreCut() {
+        u.resetUploadedList()
+        u.split()
     }
 
     st3 := domain.Now()
@@ -256,23 +481,46 @@ func (u *UploadRequest) NextAction() executor.Action {
         return nil
     }
 
+    // The parts which must be sent again come first, then we cut a new part
+    select {
+    case partID := <-u.parts:
+        return u.newAction(partID)
+    case <-u.done:
+        return nil
+    default:
+    }
+    u.mtx.Lock()
+    partID, ok := u.cutPart(false)
+    u.mtx.Unlock()
+    if ok {
+        return u.newAction(partID)
+    }
+
     // Wait for next part, or return nil if we finished
     select {
     case partID := <-u.parts:
-        logger.Debug("got next upload part",
-            zap.String("ReqID", u.GetID()),
-            zap.Int32("PartID", partID),
-            zap.Duration("D", domain.Now().Sub(u.startTime)),
-        )
-        return &UploadAction{
-            id:  partID,
-            req: u,
-        }
+        return u.newAction(partID)
     case <-u.done:
         return nil
     }
 }
 
+func (u *UploadRequest) newAction(partID int32) executor.Action {
+    logger.Debug("got next upload part",
+        zap.String("ReqID", u.GetID()),
+        zap.Int32("PartID", partID),
+        zap.Duration("D", domain.Now().Sub(u.startTime)),
+    )
+    u.mtx.Lock()
+    gen := u.gen
+    u.mtx.Unlock()
+    return &UploadAction{
+        id:  partID,
+        gen: gen,
+        req: u,
+    }
+}
+
 func (u *UploadRequest) ActionDone(id int32) {
     logger.Info("finished upload part",
         zap.String("ID", u.GetID()),
@@ -280,34 +528,46 @@ func (u *UploadRequest) ActionDone(id int32) {
         zap.Duration("D", domain.Now().Sub(u.startTime)),
         zap.String("Progress", fmt.Sprintf("%d / %d", len(u.cfr.FinishedParts), u.cfr.TotalParts)),
     )
-    // If we have failed too many times, and we can decrease the chunk size the we do it again.
+    // If a part has been failed too many times, we restart with a smaller chunk size or give up. After the
+    // restart the first part is cut and pushed below, since NextAction might be waiting for a part.
+    u.mtx.Lock()
+    err := u.err
+    u.mtx.Unlock()
+    if err != nil && !u.shrink() {
+        logger.Warn("gives up UploadRequest", zap.String("ReqID", u.GetID()), zap.Error(err))
+        select {
+        case u.done <- struct{}{}:
+        default:
+        }
+        _ = u.file.Close()
+        u.cancel(err)
+        return
+    }
+
+    // The parts which have not been cut yet get the chunk size which suits the network condition
+    failing := false
     if atomic.LoadInt32(&u.failedActions) > RetryMaxAttempts {
         atomic.StoreInt32(&u.failedActions, 0)
-        logger.Debug("Max Attempts",
-            zap.Int32("ChunkSize", u.cfr.ChunkSize),
-        )
+        failing = true
     }
+    u.adapt(failing)
 
-    // For single part uploads we are done
-    // For n-part uploads if we have done n-1 part then we add the last part
-    finishedParts := int32(len(u.cfr.FinishedParts))
-    switch u.cfr.TotalParts {
-    case 1:
-        if finishedParts != u.cfr.TotalParts {
-            return
-        }
-    default:
-        switch {
-        case finishedParts < u.cfr.TotalParts-1:
-            return
-        case finishedParts == u.cfr.TotalParts-1:
-            if u.lastPartSent {
-                return
-            }
-            u.lastPartSent = true
-            u.parts <- u.cfr.TotalParts - 1
-            return
-        }
+    // If all the parts which have been cut are uploaded, we cut the next part. It is the last part, unless
+    // the chunk size has been decreased meanwhile.
+    u.mtx.Lock()
+    finishedParts, cutParts := len(u.cfr.FinishedParts), len(u.cut)
+    remaining := u.cfr.FileSize - u.cutOffset()
+    partID, ok := int32(0), false
+    if finishedParts == cutParts && remaining > 0 {
+        partID, ok = u.cutPart(true)
+    }
+    u.mtx.Unlock()
+    if ok {
+        u.parts <- partID
+        return
+    }
+    if finishedParts < cutParts || remaining > 0 {
+        return
     }
 
     // This is last part so we make the executor free to run the next job if exist
@@ -349,6 +609,7 @@ func (u *UploadRequest) Next() executor.Request {
 
 type UploadAction struct {
     id  int32
+    gen int32
     req *UploadRequest
 }
 
@@ -359,14 +620,21 @@ func (a *UploadAction) ID() int32 {
 func (a *UploadAction) Do(ctx context.Context) {
     startTime := domain.Now()
 
-    bytes := pools.Bytes.GetLen(int(a.req.cfr.ChunkSize))
-    defer pools.Bytes.Put(bytes)
+    // If the parts have been recalculated, this action is not valid anymore
+    a.req.mtx.Lock()
+    if a.gen != a.req.gen {
+        a.req.mtx.Unlock()
+        return
+    }
+    part, totalParts := a.req.cut[a.id], a.req.cfr.TotalParts
+    a.req.mtx.Unlock()
+    chunkSize := part.size
 
-    // Calculate offset based on chunk id and the chunk size
-    offset := a.id * a.req.cfr.ChunkSize
+    bytes := pools.Bytes.GetLen(int(chunkSize))
+    defer pools.Bytes.Put(bytes)
 
     // We try to read the chunk, if it failed we try one more time
-    n, err := a.req.file.ReadAt(bytes, int64(offset))
+    n, err := a.req.file.ReadAt(bytes, part.offset)
     if err != nil && err != io.EOF {
         logger.Warn("got error in ReadFile (Upload)", zap.Error(err))
         a.req.parts <- a.id
@@ -377,13 +645,19 @@ func (a *UploadAction) Do(ctx context.Context) {
     if n == 0 {
         logger.Fatal("read zero bytes from file",
             zap.String("FilePath", a.req.cfr.FilePath),
-            zap.Int32("TotalParts", a.req.cfr.TotalParts),
-            zap.Int32("ChunkSize", a.req.cfr.ChunkSize),
+            zap.Int32("TotalParts", totalParts),
+            zap.Int32("ChunkSize", chunkSize),
         )
     }
 
+    // Wait for our share of the upload bandwidth
+    if !a.req.ctrl.uploadLimiter.Wait(ctx, int64(n)) {
+        a.req.parts <- a.id
+        return
+    }
+
     req := &msg.FileSavePart{
-        TotalParts: a.req.cfr.TotalParts,
+        TotalParts: totalParts,
         Bytes:      bytes[:n],
         FileID:     a.req.cfr.FileID,
         PartID:     a.id + 1,
@@ -391,13 +665,14 @@ func (a *UploadAction) Do(ctx context.Context) {
     reqCB := request.NewCallback(
         0, 0, domain.NextRequestID(), msg.C_FileSavePart, req,
         func() {
-            atomic.AddInt32(&a.req.failedActions, 1)
-            a.req.parts <- a.id
+            a.req.ctrl.chunkTuner.record(chunkSize, domain.Now().Sub(startTime), true)
+            a.req.retryPart(ctx, a.gen, a.id)
         },
         func(res *rony.MessageEnvelope) {
             switch res.Constructor {
             case msg.C_Bool:
-                a.req.addToUploaded(a.id)
+                a.req.ctrl.chunkTuner.record(chunkSize, domain.Now().Sub(startTime), false)
+                a.req.addToUploaded(a.gen, a.id)
                 logger.Debug("upload action done",
                     zap.String("ID", a.req.GetID()),
                     zap.Int32("PartID", a.ID()),
@@ -411,8 +686,8 @@ func (a *UploadAction) Do(ctx context.Context) {
                     zap.String("Code", x.Code),
                     zap.String("Item", x.Items),
                 )
-                atomic.AddInt32(&a.req.failedActions, 1)
-                a.req.parts <- a.id
+                a.req.ctrl.chunkTuner.record(chunkSize, domain.Now().Sub(startTime), true)
+                a.req.retryPart(ctx, a.gen, a.id)
             default:
                 logger.Fatal("received unexpected response (Upload)", zap.String("C", registry.ConstructorName(res.Constructor)))
                 return
@@ -0,0 +1,249 @@
+package fileCtrl
+
+import (
+    "bytes"
+    "crypto/rand"
+    "io/ioutil"
+    "os"
+    "path/filepath"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    networkCtrl "github.com/ronaksoft/river-sdk/internal/ctrl_network"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/river-sdk/internal/testenv/fakeserver"
+    "github.com/ronaksoft/rony"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+type testSavedPart struct {
+    size       int
+    totalParts int32
+}
+
+func TestUploadCutParts(t *testing.T) {
+    Convey("Upload Cut Parts", t, func(c C) {
+        u := &UploadRequest{
+            cfr: &msg.ClientFileRequest{
+                FileSize:  1000 << 10,
+                ChunkSize: 256 << 10,
+            },
+            ctrl: &Controller{chunkTuner: newChunkTuner()},
+        }
+        u.split()
+        c.So(u.cfr.TotalParts, ShouldEqual, 4)
+
+        p0, ok := u.cutPart(false)
+        c.So(ok, ShouldBeTrue)
+        c.So(p0, ShouldEqual, 0)
+
+        // the parts are failing, so the remaining parts get smaller, but the number of parts does not change
+        u.adapt(true)
+        c.So(u.cfr.ChunkSize, ShouldEqual, 128<<10)
+        c.So(u.cfr.TotalParts, ShouldEqual, 4)
+        p1, ok := u.cutPart(false)
+        c.So(ok, ShouldBeTrue)
+        c.So(u.cut[p0], ShouldResemble, uploadPart{offset: 0, size: 256 << 10})
+        c.So(u.cut[p1], ShouldResemble, uploadPart{offset: 256 << 10, size: 128 << 10})
+
+        // the network is doing well, so the remaining parts get bigger
+        for i := 0; i < 10; i++ {
+            u.ctrl.chunkTuner.record(256<<10, 100*time.Millisecond, false)
+        }
+        u.adapt(false)
+        c.So(u.cfr.ChunkSize, ShouldEqual, 512<<10)
+        p2, ok := u.cutPart(false)
+        c.So(ok, ShouldBeTrue)
+        c.So(u.cut[p2], ShouldResemble, uploadPart{offset: 384 << 10, size: 512 << 10})
+        c.So(u.cfr.TotalParts, ShouldEqual, 4)
+
+        // the last part is cut after the other parts are uploaded, it covers the rest of the file
+        _, ok = u.cutPart(false)
+        c.So(ok, ShouldBeFalse)
+        p3, ok := u.cutPart(true)
+        c.So(ok, ShouldBeTrue)
+        c.So(u.cut[p3], ShouldResemble, uploadPart{offset: 896 << 10, size: 104 << 10})
+        c.So(u.cfr.TotalParts, ShouldEqual, 4)
+        _, ok = u.cutPart(true)
+        c.So(ok, ShouldBeFalse)
+    })
+    Convey("Upload Part Size", t, func(c C) {
+        // the chunk size is followed while the other parts could cover the rest
+        c.So(partSize(1000<<10, 4, 256<<10), ShouldEqual, 256<<10)
+        // the other parts could not cover the rest by the biggest chunk size
+        c.So(partSize(1700<<10, 4, 64<<10), ShouldEqual, (1700-3*512)<<10)
+        // the other parts would be smaller than the smallest chunk size
+        c.So(partSize(400<<10, 4, 512<<10), ShouldEqual, (400-3*64)<<10)
+        c.So(canCut(400<<10, 4), ShouldBeTrue)
+        c.So(canCut(100<<10, 4), ShouldBeFalse)
+        c.So(canCut(3000<<10, 4), ShouldBeFalse)
+        c.So(canCut(0, 0), ShouldBeTrue)
+    })
+}
+
+func TestUploadRestoreCut(t *testing.T) {
+    Convey("Upload Restore Cut", t, func(c C) {
+        newRequest := func(finishedParts ...int32) *UploadRequest {
+            return &UploadRequest{
+                cfr: &msg.ClientFileRequest{
+                    FileID:        domain.RandomInt63(),
+                    FileSize:      1000 << 10,
+                    ChunkSize:     256 << 10,
+                    TotalParts:    4,
+                    FinishedParts: finishedParts,
+                },
+                ctrl:  &Controller{chunkTuner: newChunkTuner()},
+                parts: make(chan int32, MaxParts),
+            }
+        }
+        pushedParts := func(u *UploadRequest) []int32 {
+            var parts []int32
+            for len(u.parts) > 0 {
+                parts = append(parts, <-u.parts)
+            }
+            return parts
+        }
+
+        Convey("Keep Uploaded Parts", func(c C) {
+            u := newRequest(0, 2)
+            c.So(repo.Files.SaveUploadParts(u.GetID(), []int32{256 << 10, 128 << 10, 512 << 10}), ShouldBeNil)
+            c.So(u.restoreCut(), ShouldBeTrue)
+            c.So(u.cut, ShouldResemble, []uploadPart{
+                {offset: 0, size: 256 << 10},
+                {offset: 256 << 10, size: 128 << 10},
+                {offset: 384 << 10, size: 512 << 10},
+            })
+            c.So(u.cfr.FinishedParts, ShouldResemble, []int32{0, 2})
+            c.So(u.cfr.TotalParts, ShouldEqual, 4)
+            c.So(pushedParts(u), ShouldResemble, []int32{1})
+        })
+        Convey("Cut The Last Part", func(c C) {
+            u := newRequest(0, 1, 2)
+            c.So(repo.Files.SaveUploadParts(u.GetID(), []int32{256 << 10, 128 << 10, 512 << 10}), ShouldBeNil)
+            c.So(u.restoreCut(), ShouldBeTrue)
+            c.So(pushedParts(u), ShouldResemble, []int32{3})
+            c.So(u.cut[3], ShouldResemble, uploadPart{offset: 896 << 10, size: 104 << 10})
+        })
+        Convey("Send The Last Part Again", func(c C) {
+            u := newRequest(0, 1, 2, 3)
+            c.So(repo.Files.SaveUploadParts(u.GetID(), []int32{256 << 10, 128 << 10, 512 << 10, 104 << 10}), ShouldBeNil)
+            c.So(u.restoreCut(), ShouldBeTrue)
+            c.So(pushedParts(u), ShouldResemble, []int32{3})
+            c.So(u.cfr.FinishedParts, ShouldResemble, []int32{0, 1, 2})
+        })
+        Convey("File Does Not Match", func(c C) {
+            u := newRequest(0)
+            c.So(repo.Files.SaveUploadParts(u.GetID(), []int32{256 << 10, 128 << 10, 512 << 10}), ShouldBeNil)
+            u.cfr.FileSize = 800 << 10
+            c.So(u.restoreCut(), ShouldBeFalse)
+            c.So(newRequest().restoreCut(), ShouldBeFalse)
+        })
+        Convey("Parts Are Deleted With Request", func(c C) {
+            u := newRequest()
+            c.So(repo.Files.SaveUploadParts(u.GetID(), []int32{256 << 10}), ShouldBeNil)
+            c.So(repo.Files.DeleteFileRequest(u.GetID()), ShouldBeNil)
+            c.So(repo.Files.GetUploadParts(u.GetID()), ShouldBeEmpty)
+        })
+    })
+}
+
+func TestUploadAdaptiveChunkSize(t *testing.T) {
+    Convey("Upload Adaptive Chunk Size", t, func(c C) {
+        s, err := fakeserver.New()
+        c.So(err, ShouldBeNil)
+        defer s.Close()
+
+        var (
+            savedLock sync.Mutex
+            saved     = make(map[int32]testSavedPart)
+            data      = make(map[int32][]byte)
+        )
+        s.Handle(msg.C_FileSavePart, func(ctx *fakeserver.Context) {
+            req := &msg.FileSavePart{}
+            _ = req.Unmarshal(ctx.Request().Message)
+            savedLock.Lock()
+            saved[req.PartID] = testSavedPart{size: len(req.Bytes), totalParts: req.TotalParts}
+            data[req.PartID] = append([]byte(nil), req.Bytes...)
+            savedLock.Unlock()
+            ctx.Reply(msg.C_Bool, &msg.Bool{Result: true})
+        })
+
+        network := networkCtrl.New(networkCtrl.Config{
+            SeedHosts:   []string{s.Addr()},
+            HttpTimeout: 10 * time.Second,
+        })
+        network.OnWebsocketConnect = func() error { return nil }
+        network.OnNetworkStatusChange = func(newStatus domain.NetworkStatus) {}
+        network.MessageChan = make(chan []*rony.MessageEnvelope, 100)
+        network.UpdateChan = make(chan *msg.UpdateContainer, 100)
+        network.Start()
+        network.Connect()
+        defer network.Stop()
+        network.SetAuthorization(s.CreateAuthKey())
+        uploaded := make(chan *msg.ClientFileRequest, 1)
+        ctrl := New(Config{
+            Network:            network,
+            MaxInflightUploads: 1,
+            DbPath:             "./_hdd/adaptive",
+            PostUploadProcessCB: func(req *msg.ClientFileRequest) bool {
+                uploaded <- req
+                return true
+            },
+        })
+        ctrl.Start()
+
+        content := make([]byte, 3<<20)
+        _, _ = rand.Read(content)
+        filePath := filepath.Join("./_hdd", "adaptive-upload")
+        c.So(ioutil.WriteFile(filePath, content, 0666), ShouldBeNil)
+        defer os.Remove(filePath)
+
+        // the fast network makes the chunk size grow, since the number of parts is fixed by the chunk size
+        // we start with, the last parts get smaller
+        c.So(ctrl.upload(&msg.ClientFileRequest{
+            FileID:           domain.RandomInt63(),
+            FilePath:         filePath,
+            ChunkSize:        256 << 10,
+            SkipDelegateCall: true,
+        }), ShouldBeNil)
+
+        var req *msg.ClientFileRequest
+        select {
+        case req = <-uploaded:
+        case <-time.After(30 * time.Second):
+            c.So("upload is not completed", ShouldBeEmpty)
+        }
+        c.So(req.ChunkSize, ShouldBeGreaterThan, 256<<10)
+        c.So(req.TotalParts, ShouldEqual, 12)
+
+        savedLock.Lock()
+        defer savedLock.Unlock()
+        c.So(saved, ShouldHaveLength, req.TotalParts)
+        var file []byte
+        sizes := make(map[int]struct{})
+        for partID := int32(1); partID <= req.TotalParts; partID++ {
+            file = append(file, data[partID]...)
+            if partID < req.TotalParts {
+                sizes[saved[partID].size] = struct{}{}
+            }
+        }
+        c.So(bytes.Equal(file, content), ShouldBeTrue)
+        c.So(len(sizes), ShouldBeGreaterThan, 1)
+        // all the parts carry the same number of parts
+        for _, part := range saved {
+            c.So(part.totalParts, ShouldEqual, req.TotalParts)
+        }
+    })
+}
@@ -156,7 +156,8 @@ func (ctrl *Controller) executor(reqCB request.Callback) {
         case msg.C_MessagesReadHistory, msg.C_MessagesGetHistory,
             msg.C_ContactsImport, msg.C_ContactsGet,
             msg.C_AuthSendCode, msg.C_AuthRegister, msg.C_AuthLogin,
-            msg.C_LabelsAddToMessage, msg.C_LabelsRemoveFromMessage:
+            msg.C_LabelsAddToMessage, msg.C_LabelsRemoveFromMessage,
+            msg.C_MessagesEdit, msg.C_MessagesEditMedia:
             ctrl.addToWaitingList(reqCB)
             return
         default:
@@ -181,6 +182,21 @@ func (ctrl *Controller) executor(reqCB request.Callback) {
 
                 }
             }
+        case msg.C_MessagesEdit, msg.C_MessagesEditMedia:
+            // The edit has been applied locally, it is rolled back here since the callback of the request is not
+            // restored if the client has been restarted.
+            if res.Constructor == rony.C_Error {
+                errMsg := &rony.Error{}
+                _ = errMsg.Unmarshal(res.Message)
+                if domain.CheckError(errMsg, msg.ErrCodeInvalid, msg.ErrItemSalt) {
+                    ctrl.addToWaitingList(reqCB)
+                    break
+                }
+                _, err := repo.Messages.RollbackEdit(reqCB.RequestID())
+                if err != nil && err != domain.ErrNotFound {
+                    logger.Warn("could not roll back message edit", zap.Uint64("ReqID", reqCB.RequestID()), zap.Error(err))
+                }
+            }
         default:
             switch res.Constructor {
             case rony.C_Error:
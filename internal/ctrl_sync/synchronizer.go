@@ -354,7 +354,7 @@ func onGetDifferenceSucceed(ctrl *Controller, x *msg.UpdateDifference) {
                     break
                 }
                 mtx.Lock()
-                updContainer.Updates = append(updContainer.Updates, externalHandlerUpdates...)
+                updContainer.Updates = appendAppliedUpdates(updContainer.Updates, ue, externalHandlerUpdates)
                 mtx.Unlock()
             } else {
                 mtx.Lock()
@@ -556,11 +556,7 @@ func (ctrl *Controller) UpdateApplier(updateContainer *msg.UpdateContainer, outO
                 zap.String("C", registry.ConstructorName(update.Constructor)),
                 zap.Int64("UpdateID", update.UpdateID),
             )
-            switch update.Constructor {
-            case msg.C_UpdateMessageID:
-            default:
-                udpContainer.Updates = append(udpContainer.Updates, externalHandlerUpdates...)
-            }
+            udpContainer.Updates = appendAppliedUpdates(udpContainer.Updates, update, externalHandlerUpdates)
         } else {
             udpContainer.Updates = append(udpContainer.Updates, update)
         }
@@ -570,7 +566,16 @@ func (ctrl *Controller) UpdateApplier(updateContainer *msg.UpdateContainer, outO
     }
 
     udpContainer.Length = int32(len(udpContainer.Updates))
-    uiexec.ExecUpdate(msg.C_UpdateContainer, updateContainer)
+    uiexec.ExecUpdate(msg.C_UpdateContainer, udpContainer)
+}
+
+// appendAppliedUpdates appends the updates which the applier returned for the UI, if the applier returned nil the
+// received update is passed as is.
+func appendAppliedUpdates(updates []*msg.UpdateEnvelope, u *msg.UpdateEnvelope, applied []*msg.UpdateEnvelope) []*msg.UpdateEnvelope {
+    if applied == nil {
+        return append(updates, u)
+    }
+    return append(updates, applied...)
 }
 
 // ResetIDs reset updateID
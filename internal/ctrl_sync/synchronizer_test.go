@@ -0,0 +1,257 @@
+package syncCtrl_test
+
+import (
+    "testing"
+    "time"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    networkCtrl "github.com/ronaksoft/river-sdk/internal/ctrl_network"
+    queueCtrl "github.com/ronaksoft/river-sdk/internal/ctrl_queue"
+    syncCtrl "github.com/ronaksoft/river-sdk/internal/ctrl_sync"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/river-sdk/internal/request"
+    "github.com/ronaksoft/river-sdk/internal/testenv"
+    "github.com/ronaksoft/river-sdk/internal/testenv/fakeserver"
+    "github.com/ronaksoft/river-sdk/internal/uiexec"
+    "github.com/ronaksoft/rony"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func init() {
+    repo.MustInit("./_data", false)
+    testenv.Log().SetLogLevel(2)
+}
+
+func TestUpdateApplier(t *testing.T) {
+    Convey("UpdateApplier", t, func(c C) {
+        containers := make(chan *msg.UpdateContainer, 10)
+        uiexec.Init(
+            func(constructor int64, b []byte) {
+                if constructor != msg.C_UpdateContainer {
+                    return
+                }
+                x := &msg.UpdateContainer{}
+                _ = x.Unmarshal(b)
+                containers <- x
+            },
+            func(dialogs, contacts, gifs bool) {},
+        )
+
+        ctrl := syncCtrl.NewSyncController(syncCtrl.Config{})
+        synthesized := &msg.UpdateEnvelope{Constructor: msg.C_ClientUpdateMessagesDeleted}
+        ctrl.RegisterUpdateApplier(msg.C_UpdateUsername, func(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
+            return []*msg.UpdateEnvelope{u}, nil
+        })
+        ctrl.RegisterUpdateApplier(msg.C_UpdateUserBlocked, func(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
+            return []*msg.UpdateEnvelope{}, nil
+        })
+        ctrl.RegisterUpdateApplier(msg.C_UpdateNewMessage, func(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
+            return []*msg.UpdateEnvelope{u, synthesized}, nil
+        })
+        ctrl.RegisterUpdateApplier(msg.C_UpdateMessageID, func(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
+            return []*msg.UpdateEnvelope{u}, nil
+        })
+        ctrl.RegisterUpdateApplier(msg.C_UpdateTeam, func(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
+            return nil, nil
+        })
+
+        ctrl.UpdateApplier(&msg.UpdateContainer{
+            Updates: []*msg.UpdateEnvelope{
+                {Constructor: msg.C_UpdateUsername, UpdateID: 11},
+                {Constructor: msg.C_UpdateUserBlocked, UpdateID: 12},
+                {Constructor: msg.C_UpdateMessageID},
+                {Constructor: msg.C_UpdateNewMessage, UpdateID: 13},
+                {Constructor: msg.C_UpdateTeam},
+                {Constructor: msg.C_UpdateUserTyping},
+            },
+            MinUpdateID: 11,
+            MaxUpdateID: 13,
+        }, false)
+
+        select {
+        case x := <-containers:
+            // the suppressed update is dropped, the synthesized update is delivered and the update whose applier
+            // returned nil is delivered as is
+            c.So(x.Length, ShouldEqual, 6)
+            c.So(x.Updates, ShouldHaveLength, 6)
+            c.So(x.Updates[0].Constructor, ShouldEqual, msg.C_UpdateUsername)
+            c.So(x.Updates[1].Constructor, ShouldEqual, msg.C_UpdateMessageID)
+            c.So(x.Updates[2].Constructor, ShouldEqual, msg.C_UpdateNewMessage)
+            c.So(x.Updates[3].Constructor, ShouldEqual, msg.C_ClientUpdateMessagesDeleted)
+            c.So(x.Updates[4].Constructor, ShouldEqual, msg.C_UpdateTeam)
+            c.So(x.Updates[5].Constructor, ShouldEqual, msg.C_UpdateUserTyping)
+            c.So(x.MinUpdateID, ShouldEqual, 11)
+            c.So(x.MaxUpdateID, ShouldEqual, 13)
+        case <-time.After(time.Second):
+            c.So("update container is not delivered", ShouldBeEmpty)
+        }
+        c.So(ctrl.GetUpdateID(), ShouldEqual, 13)
+    })
+}
+
+// newTestClient wires the network, queue and sync controllers to the fake server the same way the SDK does,
+// the responses are passed to their callbacks and the updates are applied by the sync controller.
+func newTestClient(s *fakeserver.Server) (*networkCtrl.Controller, *queueCtrl.Controller, *syncCtrl.Controller) {
+    network := networkCtrl.New(networkCtrl.Config{
+        SeedHosts: []string{s.Addr()},
+    })
+    network.OnWebsocketConnect = func() error { return nil }
+    network.OnNetworkStatusChange = func(newStatus domain.NetworkStatus) {}
+    network.MessageChan = make(chan []*rony.MessageEnvelope, 100)
+    network.UpdateChan = make(chan *msg.UpdateContainer, 100)
+    queue := queueCtrl.New(nil, network, "./_data")
+    ctrl := syncCtrl.NewSyncController(syncCtrl.Config{
+        NetworkCtrl: network,
+        QueueCtrl:   queue,
+    })
+
+    go func() {
+        for msgs := range network.MessageChan {
+            ctrl.MessageApplier(msgs)
+            for _, m := range msgs {
+                reqCB := request.GetCallback(m.RequestID)
+                if reqCB == nil {
+                    continue
+                }
+                select {
+                case reqCB.ResponseChan() <- m:
+                default:
+                }
+            }
+        }
+    }()
+    go func() {
+        for uc := range network.UpdateChan {
+            if uc.MinUpdateID != 0 && uc.MinUpdateID > ctrl.GetUpdateID()+1 {
+                go ctrl.Sync()
+                continue
+            }
+            ctrl.UpdateApplier(uc, false)
+        }
+    }()
+
+    network.SetAuthorization(s.CreateAuthKey())
+    network.Start()
+    network.Connect()
+    queue.Start(true)
+    return network, queue, ctrl
+}
+
+func TestSyncWithFakeServer(t *testing.T) {
+    Convey("Sync With Fake Server", t, func(c C) {
+        s, err := fakeserver.New()
+        c.So(err, ShouldBeNil)
+        defer s.Close()
+
+        containers := make(chan *msg.UpdateContainer, 10)
+        uiexec.Init(
+            func(constructor int64, b []byte) {
+                if constructor != msg.C_UpdateContainer {
+                    return
+                }
+                x := &msg.UpdateContainer{}
+                _ = x.Unmarshal(b)
+                containers <- x
+            },
+            func(dialogs, contacts, gifs bool) {},
+        )
+        // updates of different targets are applied in parallel, hence they are checked regardless of their order
+        nextUpdates := func() map[int64]*msg.UpdateEnvelope {
+            select {
+            case x := <-containers:
+                updates := make(map[int64]*msg.UpdateEnvelope)
+                for _, u := range x.Updates {
+                    updates[u.Constructor] = u
+                }
+                return updates
+            case <-time.After(5 * time.Second):
+                return nil
+            }
+        }
+
+        // the client has missed two updates while it was offline
+        s.SetUpdateID(10)
+        s.NewUpdate(msg.C_UpdateUsername, &msg.UpdateUsername{UserID: 100, Username: "river"})
+        s.NewUpdate(msg.C_UpdateUserBlocked, &msg.UpdateUserBlocked{UserID: 200, Blocked: true})
+
+        network, queue, ctrl := newTestClient(s)
+        defer network.Stop()
+        defer queue.Stop()
+        ctrl.SetUserID(100)
+        c.So(ctrl.SetUpdateID(10), ShouldBeNil)
+        network.WaitForNetwork(false)
+
+        // connect and sync
+        ctrl.Sync()
+        updates := nextUpdates()
+        c.So(updates, ShouldHaveLength, 2)
+        c.So(updates, ShouldContainKey, msg.C_UpdateUsername)
+        c.So(updates, ShouldContainKey, msg.C_UpdateUserBlocked)
+        c.So(ctrl.GetUpdateID(), ShouldEqual, 12)
+
+        // send a message, the server replies and pushes the updates of the sent message
+        s.Handle(msg.C_MessagesSend, func(ctx *fakeserver.Context) {
+            req := &msg.MessagesSend{}
+            _ = req.Unmarshal(ctx.Request().Message)
+            ctx.Reply(msg.C_Bool, &msg.Bool{Result: true})
+            u1 := s.NewUpdate(msg.C_UpdateMessageID, &msg.UpdateMessageID{MessageID: 1000, RandomID: req.RandomID})
+            u2 := s.NewUpdate(msg.C_UpdateNewMessage, &msg.UpdateNewMessage{
+                Message: &msg.UserMessage{
+                    ID:       1000,
+                    PeerID:   req.Peer.ID,
+                    PeerType: int32(req.Peer.Type),
+                    SenderID: 100,
+                    Body:     req.Body,
+                },
+            })
+            s.PushUpdateContainer(0, &msg.UpdateContainer{
+                Updates:     []*msg.UpdateEnvelope{u1, u2},
+                MinUpdateID: u1.UpdateID,
+                MaxUpdateID: u2.UpdateID,
+            })
+        })
+        sent := make(chan *rony.MessageEnvelope, 1)
+        randomID := domain.RandomInt63()
+        queue.EnqueueCommand(
+            request.NewCallback(
+                0, 0, uint64(randomID), msg.C_MessagesSend,
+                &msg.MessagesSend{
+                    RandomID: randomID,
+                    Peer:     &msg.InputPeer{ID: 200, Type: msg.PeerType_PeerUser},
+                    Body:     "Hello",
+                },
+                nil,
+                func(m *rony.MessageEnvelope) {
+                    sent <- m
+                },
+                nil, false, 0, domain.WebsocketRequestTimeout,
+            ),
+        )
+        select {
+        case m := <-sent:
+            c.So(m.Constructor, ShouldEqual, msg.C_Bool)
+        case <-time.After(5 * time.Second):
+            c.So("message is not sent", ShouldBeEmpty)
+        }
+
+        // the pushed updates are applied and delivered to the UI
+        updates = nextUpdates()
+        c.So(updates, ShouldHaveLength, 2)
+        c.So(updates, ShouldContainKey, msg.C_UpdateMessageID)
+        c.So(updates, ShouldContainKey, msg.C_UpdateNewMessage)
+        m := &msg.UpdateNewMessage{}
+        c.So(m.Unmarshal(updates[msg.C_UpdateNewMessage].Update), ShouldBeNil)
+        c.So(m.Message.Body, ShouldEqual, "Hello")
+        c.So(ctrl.GetUpdateID(), ShouldEqual, 14)
+    })
+}
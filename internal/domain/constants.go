@@ -108,6 +108,9 @@ const (
     SkReIndexTime        = "RE_INDEX_TS"
     SkGifHash            = "GIF_HASH"
     SkTeam               = "TEAM"
+    SkNetworkProfiles    = "NETWORK_PROFILES"
+    SkNetworkType        = "NETWORK_TYPE"
+    SkBlockedSynced      = "BLOCKED_SYNCED"
 )
 
 func GetContactsGetHashKey(teamID int64) string {
@@ -183,3 +186,9 @@ const (
     ConnectionWifi
     ConnectionCellular
 )
+
+// Session Closed Reasons, passed to MainDelegate.OnSessionClosed
+const (
+    SessionClosedLogout = iota
+    SessionClosedAuthorizationReset
+)
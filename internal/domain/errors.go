@@ -25,6 +25,9 @@ var (
 	ErrServer                = errors.New("server error")
 	ErrFileTooLarge          = errors.New("file is too large")
 	ErrNoPostProcess         = errors.New("no post process")
+	ErrCorruptedFile         = errors.New("corrupted file")
+	ErrAutoDownloadDisabled  = errors.New("auto download is disabled")
+	ErrMessageIsPending      = errors.New("message is pending")
 )
 
 // ParseServerError ...
@@ -32,7 +32,9 @@ type ErrorHandler func(requestID uint64, u *rony.Error)
 // MessageHandler success callback/delegate
 type MessageHandler func(m *rony.MessageEnvelope)
 
-// UpdateApplier on receive update in SyncController, cache client data, there are some applier function for each proto message
+// UpdateApplier on receive update in SyncController, cache client data, there are some applier function for each proto message.
+// The returned updates are passed to the UI instead of the received update, hence an empty slice hides the update from
+// the UI. If nil is returned the received update is passed to the UI as is.
 type UpdateApplier func(envelope *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error)
 
 // MessageApplier on receive response in SyncController, cache client data, there are some applier function for each proto message
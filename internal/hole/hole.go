@@ -1,7 +1,9 @@
 package hole
 
 import (
+    "encoding/binary"
     "encoding/json"
+    "errors"
     "fmt"
     "sort"
     "strings"
@@ -14,7 +16,8 @@ import (
 )
 
 var (
-    logger *logs.Logger
+    logger         *logs.Logger
+    ErrCorruptData = errors.New("corrupt hole data")
 )
 
 func init() {
@@ -51,144 +54,176 @@ type Detector struct {
     Bars     []Bar
 }
 
-func readFromDB(teamID, peerID int64, peerType int32, cat msg.MediaCategory) *Detector {
-    m := &Detector{}
-    b := repo.MessagesExtra.GetHoles(teamID, peerID, peerType, cat)
-    _ = json.Unmarshal(b, &m.Bars)
-    m.MaxIndex = 0
+const (
+    encodingVersion byte = 1
+)
+
+// MarshalBinary encodes the detector in a compact form. Since the bars are contiguous and holes and fills
+// alternate, only the filled bars are stored, each as the distance from the previous bar and its length.
+// Holes are reconstructed on decode.
+func (m *Detector) MarshalBinary() []byte {
+    m.mtx.Lock()
+    defer m.mtx.Unlock()
+    return m.marshalBinary()
+}
+
+func (m *Detector) marshalBinary() []byte {
+    fills := 0
     for idx := range m.Bars {
-        if m.Bars[idx].Max > m.MaxIndex {
-            m.MaxIndex = m.Bars[idx].Max
+        if m.Bars[idx].Type == Filled {
+            fills++
         }
     }
-    return m
-}
 
-func writeToDB(teamID, peerID int64, peerType int32, cat msg.MediaCategory, hm *Detector) {
-    b, err := json.Marshal(hm.Bars)
-    if err != nil {
-        logger.Error("got error on marshalling hole", zap.Error(err))
-        return
+    b := make([]byte, 0, 1+binary.MaxVarintLen64*(2+2*fills))
+    b = append(b, encodingVersion)
+    // We store MaxIndex + 1, then zero means there is no bar
+    if len(m.Bars) == 0 {
+        b = appendVarint(b, 0)
+    } else {
+        b = appendVarint(b, m.MaxIndex+1)
+    }
+    b = appendUvarint(b, uint64(fills))
+    prevMax := int64(-1)
+    for idx := range m.Bars {
+        if m.Bars[idx].Type != Filled {
+            continue
+        }
+        b = appendVarint(b, m.Bars[idx].Min-prevMax)
+        b = appendUvarint(b, uint64(m.Bars[idx].Max-m.Bars[idx].Min))
+        prevMax = m.Bars[idx].Max
     }
-    repo.MessagesExtra.SaveHoles(teamID, peerID, peerType, cat, b)
+    return b
 }
 
-func load(teamID, peerID int64, peerType int32, cat msg.MediaCategory) *Detector {
-    keyID := fmt.Sprintf("%d.%d.%d.%d", teamID, peerID, peerType, cat)
-    cache.mtx.Lock()
-    defer cache.mtx.Unlock()
-    hm, ok := cache.list[keyID]
-    if !ok {
-        hm = readFromDB(teamID, peerID, peerType, cat)
-        cache.list[keyID] = hm
+// UnmarshalBinary decodes the output of MarshalBinary. It also accepts the json encoded bars of the older
+// versions, which are compacted after decoding.
+func (m *Detector) UnmarshalBinary(data []byte) error {
+    m.mtx.Lock()
+    defer m.mtx.Unlock()
+    return m.unmarshalBinary(data)
+}
+
+func (m *Detector) unmarshalBinary(data []byte) error {
+    m.Bars = m.Bars[:0]
+    m.MaxIndex = 0
+    if len(data) == 0 {
+        return nil
     }
 
-    if !hm.Valid() {
-        logger.Error("load invalid data, we reset hole",
-            zap.Int64("TeamID", teamID),
-            zap.Int64("PeerID", peerID),
-            zap.String("Dump", hm.String()),
-        )
-        hm = &Detector{}
-        b, _ := json.Marshal(hm)
-        repo.MessagesExtra.SaveHoles(teamID, peerID, peerType, cat, b)
-        cache.list[keyID] = hm
+    switch data[0] {
+    case '[', 'n':
+        err := json.Unmarshal(data, &m.Bars)
+        if err != nil {
+            return err
+        }
+        m.compact()
+        return nil
+    case encodingVersion:
+    default:
+        return ErrCorruptData
     }
-    return hm
+
+    data = data[1:]
+    maxIndex, n := binary.Varint(data)
+    if n <= 0 {
+        return ErrCorruptData
+    }
+    data = data[n:]
+    fills, n := binary.Uvarint(data)
+    if n <= 0 {
+        return ErrCorruptData
+    }
+    data = data[n:]
+
+    nextMin := int64(0)
+    prevMax := int64(-1)
+    for ; fills > 0; fills-- {
+        delta, n := binary.Varint(data)
+        if n <= 0 {
+            return ErrCorruptData
+        }
+        data = data[n:]
+        length, n := binary.Uvarint(data)
+        if n <= 0 {
+            return ErrCorruptData
+        }
+        data = data[n:]
+
+        b := Bar{Min: prevMax + delta, Type: Filled}
+        b.Max = b.Min + int64(length)
+        if b.Min < nextMin || b.Max < b.Min {
+            return ErrCorruptData
+        }
+        if b.Min > nextMin {
+            m.Bars = append(m.Bars, Bar{Min: nextMin, Max: b.Min - 1, Type: Hole})
+        }
+        m.Bars = append(m.Bars, b)
+        prevMax = b.Max
+        nextMin = b.Max + 1
+    }
+    if maxIndex-1 >= nextMin {
+        m.Bars = append(m.Bars, Bar{Min: nextMin, Max: maxIndex - 1, Type: Hole})
+    }
+    if len(m.Bars) > 0 {
+        m.MaxIndex = m.Bars[len(m.Bars)-1].Max
+    }
+    return nil
 }
 
 func (m *Detector) InsertBar(b Bar) {
     m.mtx.Lock()
     defer m.mtx.Unlock()
+    m.insertBar(b)
+}
+
+func (m *Detector) insertBar(b Bar) {
+    if b.Min > b.Max {
+        return
+    }
 
     // If it is the first bar
     if len(m.Bars) == 0 {
         if b.Min > 0 {
             m.Bars = append(m.Bars, Bar{Min: 0, Max: b.Min - 1, Type: Hole})
         }
+        m.appendBar(b)
         m.MaxIndex = b.Max
-        m.Bars = append(m.Bars, b)
         return
     }
 
-    // We sort the bar to find the max point, if b.Max is larger than biggest index, then we have to
-    // insert a hole to increase the domain
-    // TODO:: We must make sure that Bars are sorted all the time then we don't need to sort every time
-    sort.Slice(m.Bars, func(i, j int) bool {
-        return m.Bars[i].Min < m.Bars[j].Min
-    })
-    maxIndex := m.Bars[len(m.Bars)-1].Max
-    if b.Max > maxIndex {
-        m.Bars = append(m.Bars, Bar{Min: maxIndex + 1, Max: b.Max, Type: Hole})
+    // Bars are always sorted and contiguous, if the new bar is out of the current domain, we extend the domain
+    // with holes, then the new bar always splits the bars into a left part, itself and a right part.
+    currentBars := m.Bars
+    if first := currentBars[0]; b.Min < first.Min {
+        currentBars = append([]Bar{{Min: b.Min, Max: first.Min - 1, Type: Hole}}, currentBars...)
+    }
+    if last := currentBars[len(currentBars)-1]; b.Max > last.Max {
+        currentBars = append(currentBars, Bar{Min: last.Max + 1, Max: b.Max, Type: Hole})
     }
 
-    currentBars := m.Bars
-    m.Bars = make([]Bar, 0, len(currentBars)+1)
-
-    // Initially the biggest index is b.Max. We will update the MaxIndex during the range over Bars if
-    // necessary. In the first loop (InsertLoop) we go until we can insert the new bar into the list
-    idx := 0
-    m.MaxIndex = b.Max
-InsertLoop:
-    for idx := 0; idx < len(currentBars); idx++ {
-        switch {
-        case b.Min > currentBars[idx].Max:
-            m.appendBar(currentBars[idx])
-        case b.Min > currentBars[idx].Min:
-            switch {
-            case b.Max < currentBars[idx].Max:
-                m.appendBar(
-                    Bar{Min: currentBars[idx].Min, Max: b.Min - 1, Type: currentBars[idx].Type},
-                    b,
-                    Bar{Min: b.Max + 1, Max: currentBars[idx].Max, Type: currentBars[idx].Type},
-                )
-                m.MaxIndex = currentBars[idx].Max
-            case b.Max == currentBars[idx].Max:
-                m.appendBar(
-                    Bar{Min: currentBars[idx].Min, Max: b.Min - 1, Type: currentBars[idx].Type},
-                    b,
-                )
-            case b.Max > currentBars[idx].Max:
-                m.appendBar(
-                    Bar{Min: currentBars[idx].Min, Max: b.Min - 1, Type: currentBars[idx].Type},
-                    b,
-                )
-            }
-            break InsertLoop
-        case b.Min == currentBars[idx].Min:
-            switch {
-            case b.Max < currentBars[idx].Max:
-                m.appendBar(
-                    Bar{Min: b.Min, Max: b.Max, Type: b.Type},
-                    Bar{Min: b.Max + 1, Max: currentBars[idx].Max, Type: currentBars[idx].Type},
-                )
-                m.MaxIndex = currentBars[idx].Max
-            default:
-                m.appendBar(b)
-            }
-            break InsertLoop
+    m.Bars = make([]Bar, 0, len(currentBars)+2)
+    for _, cb := range currentBars {
+        if cb.Min >= b.Min {
+            break
         }
+        m.appendBar(Bar{Min: cb.Min, Max: minInt64(cb.Max, b.Min-1), Type: cb.Type})
     }
-
-    // In this loop, we are assured that the new bar has been already added, we try to append the remaining
-    // Bars to the list
-    for ; idx < len(currentBars); idx++ {
-        switch {
-        case currentBars[idx].Min < m.MaxIndex:
-            switch {
-            case currentBars[idx].Max > m.MaxIndex:
-                m.appendBar(Bar{Min: m.MaxIndex + 1, Max: currentBars[idx].Max, Type: currentBars[idx].Type})
-                m.MaxIndex = currentBars[idx].Max
-            }
-        case currentBars[idx].Min == m.MaxIndex:
-            if currentBars[idx].Max > currentBars[idx].Min {
-                m.appendBar(Bar{Min: currentBars[idx].Min + 1, Max: currentBars[idx].Max, Type: currentBars[idx].Type})
-                m.MaxIndex = currentBars[idx].Max
-            }
-        default:
-            m.appendBar(currentBars[idx])
-            m.MaxIndex = currentBars[idx].Max
+    m.appendBar(b)
+    for _, cb := range currentBars {
+        if cb.Max <= b.Max {
+            continue
         }
+        m.appendBar(Bar{Min: maxInt64(cb.Min, b.Max+1), Max: cb.Max, Type: cb.Type})
+    }
+    m.MaxIndex = m.Bars[len(m.Bars)-1].Max
+
+    if logs.IsDebug() && !m.valid() {
+        logger.Error("hole is invalid after insert",
+            zap.Int64("Min", b.Min),
+            zap.Int64("Max", b.Max),
+            zap.String("Dump", m.string()),
+        )
     }
 }
 
@@ -203,6 +238,57 @@ func (m *Detector) appendBar(bars ...Bar) {
     }
 }
 
+// Compact sorts the bars and merges the overlapping and adjacent fills, then the gaps between the fills are
+// rebuilt as holes. It repairs the bars which are stored by the older versions.
+func (m *Detector) Compact() {
+    m.mtx.Lock()
+    defer m.mtx.Unlock()
+    m.compact()
+}
+
+func (m *Detector) compact() {
+    maxIndex := int64(-1)
+    fills := make([]Bar, 0, len(m.Bars))
+    for _, b := range m.Bars {
+        if b.Min > b.Max {
+            continue
+        }
+        if b.Max > maxIndex {
+            maxIndex = b.Max
+        }
+        if b.Type == Filled {
+            fills = append(fills, b)
+        }
+    }
+    sort.Slice(fills, func(i, j int) bool {
+        return fills[i].Min < fills[j].Min
+    })
+
+    m.Bars = m.Bars[:0]
+    m.MaxIndex = 0
+    if maxIndex < 0 {
+        return
+    }
+    nextMin := int64(0)
+    for _, b := range fills {
+        if b.Max < nextMin {
+            continue
+        }
+        if b.Min < nextMin {
+            b.Min = nextMin
+        }
+        if b.Min > nextMin {
+            m.Bars = append(m.Bars, Bar{Min: nextMin, Max: b.Min - 1, Type: Hole})
+        }
+        m.appendBar(b)
+        nextMin = b.Max + 1
+    }
+    if maxIndex >= nextMin {
+        m.Bars = append(m.Bars, Bar{Min: nextMin, Max: maxIndex, Type: Hole})
+    }
+    m.MaxIndex = maxIndex
+}
+
 func (m *Detector) IsRangeFilled(min, max int64) bool {
     m.mtx.Lock()
     defer m.mtx.Unlock()
@@ -266,24 +352,36 @@ func (m *Detector) GetLowerFilled(pt int64) (bool, Bar) {
 }
 
 func (m *Detector) SetUpperFilled(pt int64) bool {
+    m.mtx.Lock()
+    defer m.mtx.Unlock()
     if pt <= m.MaxIndex {
         return false
     }
-    m.InsertBar(Bar{Type: Filled, Min: m.MaxIndex + 1, Max: pt})
+    m.insertBar(Bar{Type: Filled, Min: m.MaxIndex + 1, Max: pt})
     return true
 }
 
 func (m *Detector) SetLowerFilled() {
+    m.mtx.Lock()
+    defer m.mtx.Unlock()
+    maxMin := int64(0)
     for _, b := range m.Bars {
-        if b.Type == Filled {
-            if b.Min != 0 {
-                m.InsertBar(Bar{Min: 0, Max: b.Min, Type: Filled})
-            }
+        if b.Type == Filled && b.Min > maxMin {
+            maxMin = b.Min
         }
     }
+    if maxMin != 0 {
+        m.insertBar(Bar{Min: 0, Max: maxMin, Type: Filled})
+    }
 }
 
 func (m *Detector) String() string {
+    m.mtx.Lock()
+    defer m.mtx.Unlock()
+    return m.string()
+}
+
+func (m *Detector) string() string {
     sb := strings.Builder{}
     for _, bar := range m.Bars {
         sb.WriteString(fmt.Sprintf("[%s: %d - %d]", bar.Type.String(), bar.Min, bar.Max))
@@ -291,40 +389,83 @@ func (m *Detector) String() string {
     return sb.String()
 }
 
+// Valid checks the invariants of the detector: bars are sorted and contiguous, two adjacent bars never have
+// the same type and MaxIndex is the end of the last bar.
 func (m *Detector) Valid() bool {
     m.mtx.Lock()
     defer m.mtx.Unlock()
-    idx := int64(-1)
-    for _, bar := range m.Bars {
+    return m.valid()
+}
+
+func (m *Detector) valid() bool {
+    if len(m.Bars) == 0 {
+        return m.MaxIndex == 0
+    }
+    for idx, bar := range m.Bars {
         if bar.Min > bar.Max {
             return false
         }
-        if bar.Min <= idx {
+        if bar.Type != Hole && bar.Type != Filled {
             return false
         }
-        idx = bar.Max
+        if idx > 0 {
+            if bar.Min != m.Bars[idx-1].Max+1 || bar.Type == m.Bars[idx-1].Type {
+                return false
+            }
+        }
     }
-    return true
+    return m.MaxIndex == m.Bars[len(m.Bars)-1].Max
 }
 
-var cache = struct {
-    mtx  sync.Mutex
-    list map[string]*Detector
-}{
-    list: make(map[string]*Detector),
+func decode(teamID, peerID int64, peerType int32, cat msg.MediaCategory, b []byte) *Detector {
+    hm := &Detector{}
+    err := hm.unmarshalBinary(b)
+    if err != nil || !hm.valid() {
+        logger.Error("load invalid data, we reset hole",
+            zap.Int64("TeamID", teamID),
+            zap.Int64("PeerID", peerID),
+            zap.Int32("PeerType", peerType),
+            zap.String("Dump", hm.string()),
+            zap.Error(err),
+        )
+        hm = &Detector{}
+    }
+    return hm
 }
 
-func Init() {
-    cache.list = make(map[string]*Detector)
+func load(teamID, peerID int64, peerType int32, cat msg.MediaCategory) *Detector {
+    return decode(teamID, peerID, peerType, cat, repo.MessagesExtra.GetHoles(teamID, peerID, peerType, cat))
 }
 
+// InsertFill marks the range [minID-maxID] as filled. The holes are read, modified and written in one
+// transaction, hence concurrent calls never lose each other's fills.
 func InsertFill(teamID, peerID int64, peerType int32, cat msg.MediaCategory, minID, maxID int64) {
     if minID > maxID {
         return
     }
-    hm := load(teamID, peerID, peerType, cat)
-    hm.InsertBar(Bar{Type: Filled, Min: minID, Max: maxID})
-    writeToDB(teamID, peerID, peerType, cat, hm)
+    err := repo.MessagesExtra.UpdateHoles(teamID, peerID, peerType, cat, func(b []byte) ([]byte, error) {
+        hm := decode(teamID, peerID, peerType, cat, b)
+        hm.insertBar(Bar{Type: Filled, Min: minID, Max: maxID})
+        return hm.marshalBinary(), nil
+    })
+    if err != nil {
+        logger.Warn("got error on inserting fill",
+            zap.Int64("TeamID", teamID),
+            zap.Int64("PeerID", peerID),
+            zap.Int64("MinID", minID),
+            zap.Int64("MaxID", maxID),
+            zap.Error(err),
+        )
+    }
+}
+
+// Compact merges the adjacent fills of the stored holes
+func Compact(teamID, peerID int64, peerType int32, cat msg.MediaCategory) error {
+    return repo.MessagesExtra.UpdateHoles(teamID, peerID, peerType, cat, func(b []byte) ([]byte, error) {
+        hm := decode(teamID, peerID, peerType, cat, b)
+        hm.compact()
+        return hm.marshalBinary(), nil
+    })
 }
 
 // IsHole Checks if there is any hole in the range [minID-maxID].
@@ -348,10 +489,35 @@ func GetLowerFilled(teamID, peerID int64, peerType int32, cat msg.MediaCategory,
 }
 
 func PrintHole(teamID, peerID int64, peerType int32, cat msg.MediaCategory) string {
-    hm := load(teamID, peerID, peerType, cat)
-    sb := strings.Builder{}
-    for _, bar := range hm.Bars {
-        sb.WriteString(fmt.Sprintf("[%s: %d - %d]", bar.Type.String(), bar.Min, bar.Max))
+    return load(teamID, peerID, peerType, cat).String()
+}
+
+// Dump returns the json encoded bars, it is used for debugging
+func Dump(teamID, peerID int64, peerType int32, cat msg.MediaCategory) []byte {
+    b, _ := json.Marshal(load(teamID, peerID, peerType, cat).Bars)
+    return b
+}
+
+func appendVarint(b []byte, v int64) []byte {
+    var buf [binary.MaxVarintLen64]byte
+    return append(b, buf[:binary.PutVarint(buf[:], v)]...)
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+    var buf [binary.MaxVarintLen64]byte
+    return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
+
+func minInt64(a, b int64) int64 {
+    if a < b {
+        return a
     }
-    return sb.String()
+    return b
+}
+
+func maxInt64(a, b int64) int64 {
+    if a > b {
+        return a
+    }
+    return b
 }
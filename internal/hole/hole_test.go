@@ -1,6 +1,8 @@
 package hole
 
 import (
+    "math/rand"
+    "sync"
     "testing"
 
     "github.com/ronaksoft/river-sdk/internal/repo"
@@ -108,3 +110,116 @@ func TestHole(t *testing.T) {
     })
 
 }
+
+// checkDetector compares the detector against a simple model which keeps the type of each point
+func checkDetector(m *Detector, model []BarType) bool {
+    if !m.Valid() {
+        return false
+    }
+    if len(model) == 0 {
+        return len(m.Bars) == 0
+    }
+    if m.MaxIndex != int64(len(model)-1) {
+        return false
+    }
+    for pt := range model {
+        if m.IsPointHole(int64(pt)) != (model[pt] != Filled) {
+            return false
+        }
+    }
+
+    decoded := &Detector{}
+    if err := decoded.UnmarshalBinary(m.MarshalBinary()); err != nil {
+        return false
+    }
+    if decoded.MaxIndex != m.MaxIndex || len(decoded.Bars) != len(m.Bars) {
+        return false
+    }
+    for idx := range m.Bars {
+        if decoded.Bars[idx] != m.Bars[idx] {
+            return false
+        }
+    }
+    return true
+}
+
+func insertToModel(model []BarType, b Bar) []BarType {
+    for int64(len(model)) <= b.Max {
+        model = append(model, Hole)
+    }
+    for pt := b.Min; pt <= b.Max; pt++ {
+        model[pt] = b.Type
+    }
+    return model
+}
+
+func TestDetectorProperties(t *testing.T) {
+    Convey("Detector Properties", t, func(c C) {
+        Convey("Random Insertion Order", func(c C) {
+            for i := 0; i < 500; i++ {
+                r := rand.New(rand.NewSource(int64(i)))
+                m := &Detector{}
+                var model []BarType
+                for j := r.Intn(30); j >= 0; j-- {
+                    b := Bar{Min: r.Int63n(200), Type: Filled}
+                    b.Max = b.Min + r.Int63n(30)
+                    if r.Intn(4) == 0 {
+                        b.Type = Hole
+                    }
+                    m.InsertBar(b)
+                    model = insertToModel(model, b)
+                    if !checkDetector(m, model) {
+                        c.So(m.String(), ShouldBeEmpty)
+                    }
+                }
+            }
+        })
+        Convey("Compact Legacy Data", func(c C) {
+            m := &Detector{}
+            err := m.UnmarshalBinary([]byte(`[{"Min":10,"Max":20,"Type":2},{"Min":15,"Max":30,"Type":2},{"Min":0,"Max":9,"Type":1},{"Min":31,"Max":40,"Type":2}]`))
+            c.So(err, ShouldBeNil)
+            c.So(m.Valid(), ShouldBeTrue)
+            c.So(m.Bars, ShouldResemble, []Bar{{Min: 0, Max: 9, Type: Hole}, {Min: 10, Max: 40, Type: Filled}})
+
+            c.So(m.UnmarshalBinary([]byte{encodingVersion, 0x10}), ShouldEqual, ErrCorruptData)
+            c.So(m.UnmarshalBinary([]byte{0xFF}), ShouldEqual, ErrCorruptData)
+        })
+        Convey("Concurrent InsertFill", func(c C) {
+            peerID := tools.RandomInt64(0)
+            wg := sync.WaitGroup{}
+            for i := int64(0); i < 20; i++ {
+                wg.Add(1)
+                go func(i int64) {
+                    defer wg.Done()
+                    InsertFill(0, peerID, 1, 0, i*10+1, i*10+10)
+                }(i)
+            }
+            wg.Wait()
+            fill, r := GetUpperFilled(0, peerID, 1, 0, 1)
+            c.So(fill, ShouldBeTrue)
+            c.So(r.Max, ShouldEqual, 200)
+            c.So(Compact(0, peerID, 1, 0), ShouldBeNil)
+            c.So(PrintHole(0, peerID, 1, 0), ShouldEqual, "[H: 0 - 0][F: 1 - 200]")
+        })
+    })
+}
+
+func FuzzInsertBar(f *testing.F) {
+    f.Add([]byte{10, 2, 1, 11, 3, 1, 15, 2, 1, 17, 3, 1})
+    f.Add([]byte{100, 10, 0, 0, 50, 1, 40, 20, 0})
+    f.Fuzz(func(t *testing.T, data []byte) {
+        m := &Detector{}
+        var model []BarType
+        for ; len(data) >= 3; data = data[3:] {
+            b := Bar{Min: int64(data[0]), Max: int64(data[0]) + int64(data[1]%32), Type: Filled}
+            if data[2]%4 == 0 {
+                b.Type = Hole
+            }
+            m.InsertBar(b)
+            model = insertToModel(model, b)
+            if !checkDetector(m, model) {
+                t.Fatalf("invalid detector after inserting %v: %s", b, m.String())
+            }
+        }
+    })
+}
@@ -145,6 +145,11 @@ func Directory() string {
 	return _LogDir
 }
 
+// IsDebug returns true if the debug logs are enabled, it is used to run the expensive sanity checks only in debug mode
+func IsDebug() bool {
+	return _LogLevel.Enabled(zapcore.DebugLevel)
+}
+
 func PanicF(format string, args ...interface{}) {
 	panic(fmt.Sprintf(format, args...))
 }
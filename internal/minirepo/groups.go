@@ -1,7 +1,7 @@
 package minirepo
 
 import (
-    "strings"
+    "encoding/binary"
 
     "github.com/boltdb/bolt"
     "github.com/ronaksoft/river-msg/go/msg"
@@ -40,10 +40,17 @@ func (d *repoGroups) Save(groups ...*msg.Group) error {
     return d.db.Update(func(tx *bolt.Tx) error {
         b := tx.Bucket(bucketGroups)
         for _, group := range groups {
-            err := b.Put(
-                alloc.Gen(group.TeamID, group.ID),
-                alloc.Marshal(group),
-            )
+            key := alloc.Gen(group.TeamID, group.ID)
+            var oldGroup *msg.Group
+            if v := b.Get(key); len(v) > 0 {
+                oldGroup = &msg.Group{}
+                _ = oldGroup.Unmarshal(v)
+            }
+            err := b.Put(key, alloc.Marshal(group))
+            if err != nil {
+                return err
+            }
+            err = updateIndex(tx.Bucket(bucketSearchGroups), key, groupFields(oldGroup), groupFields(group))
             if err != nil {
                 return err
             }
@@ -58,11 +65,16 @@ func (d *repoGroups) Delete(teamID int64, groupID int64) error {
 
     return d.db.Update(func(tx *bolt.Tx) error {
         b := tx.Bucket(bucketGroups)
-        err := b.Delete(alloc.Gen(teamID, groupID))
-        if err != nil {
-            return err
+        key := alloc.Gen(teamID, groupID)
+        if v := b.Get(key); len(v) > 0 {
+            oldGroup := &msg.Group{}
+            _ = oldGroup.Unmarshal(v)
+            err := updateIndex(tx.Bucket(bucketSearchGroups), key, groupFields(oldGroup), nil)
+            if err != nil {
+                return err
+            }
         }
-        return nil
+        return b.Delete(key)
     })
 }
 
@@ -108,28 +120,21 @@ func (d *repoGroups) ReadMany(teamID int64, groupIDs ...int64) ([]*msg.Group, er
     return groups, nil
 }
 
+// Search returns the groups of the team whose title match the phrase, ranked by relevance
 func (d *repoGroups) Search(teamID int64, phrase string, limit int) []*msg.Group {
-    alloc := tools.NewAllocator()
-    defer alloc.ReleaseAll()
-
     groups := make([]*msg.Group, 0, limit)
     _ = d.db.View(func(tx *bolt.Tx) error {
         b := tx.Bucket(bucketGroups)
-        _ = b.ForEach(func(k, v []byte) error {
-            g := &msg.Group{}
-            _ = g.Unmarshal(v)
-            if g.TeamID != teamID {
-                return nil
-            }
-            if strings.Contains(strings.ToLower(g.Title), phrase) {
+        keys := searchIndex(tx.Bucket(bucketSearchGroups), phrase, limit, func(key []byte) bool {
+            return len(key) >= 9 && binary.BigEndian.Uint64(key[1:9]) == uint64(teamID)
+        })
+        for _, key := range keys {
+            if v := b.Get(key); len(v) > 0 {
+                g := &msg.Group{}
+                _ = g.Unmarshal(v)
                 groups = append(groups, g)
-                limit--
-            }
-            if limit < 0 {
-                return domain.ErrLimitReached
             }
-            return nil
-        })
+        }
         return nil
     })
     return groups
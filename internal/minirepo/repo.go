@@ -60,9 +60,15 @@ func Init(dbPath string) (err error) {
     } else {
         r.db = boldDB
     }
+    needReindex := false
     _ = r.db.Update(func(tx *bolt.Tx) error {
+        // Search indices are added later, if they do not exist we build them from the stored data
+        needReindex = tx.Bucket(bucketSearchUsers) == nil ||
+                tx.Bucket(bucketSearchContacts) == nil ||
+                tx.Bucket(bucketSearchGroups) == nil
         buckets := [][]byte{
             bucketGroups, bucketUsers, bucketGenerals, bucketContacts, bucketDialogs, bucketTeams,
+            bucketSearchUsers, bucketSearchContacts, bucketSearchGroups,
         }
         for _, b := range buckets {
             _, err = tx.CreateBucketIfNotExists(b)
@@ -72,6 +78,11 @@ func Init(dbPath string) (err error) {
         }
         return nil
     })
+    if needReindex {
+        if err := r.reindex(); err != nil {
+            logger.Error("MiniRepo got error on building search indices", zap.Error(err))
+        }
+    }
 
     // Initialize BuntDB Indexer
     buntPath := filepath.Join(dbPath, "bunty")
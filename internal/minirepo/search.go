@@ -0,0 +1,283 @@
+package minirepo
+
+import (
+    "bytes"
+    "sort"
+    "strings"
+    "unicode"
+
+    "github.com/boltdb/bolt"
+    "github.com/ronaksoft/river-msg/go/msg"
+    "golang.org/x/text/unicode/norm"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+// Search indices keep one key per (token, document): token + 0x00 + documentKey, and the value is the weight
+// of the field which the token belongs to. Hence a prefix search is a cursor seek and the tokens of a document
+// are replaced by diffing its old and new tokens.
+var (
+    bucketSearchUsers    = []byte("SRCH_USR")
+    bucketSearchContacts = []byte("SRCH_CNTC")
+    bucketSearchGroups   = []byte("SRCH_GRP")
+)
+
+// Field weights used for ranking the results
+const (
+    weightLow  byte = 1
+    weightMid  byte = 2
+    weightHigh byte = 3
+)
+
+type searchField struct {
+    text   string
+    weight byte
+    // whole if set, the concatenation of the tokens is indexed too, e.g. 'john_doe' is found by 'johndoe'
+    whole bool
+}
+
+func userFields(u *msg.User) []searchField {
+    if u == nil {
+        return nil
+    }
+    return []searchField{
+        {text: u.FirstName, weight: weightHigh},
+        {text: u.LastName, weight: weightMid},
+        {text: u.Username, weight: weightLow, whole: true},
+    }
+}
+
+func contactFields(c *msg.ContactUser) []searchField {
+    if c == nil {
+        return nil
+    }
+    return []searchField{
+        {text: c.FirstName, weight: weightHigh},
+        {text: c.LastName, weight: weightMid},
+        {text: c.Username, weight: weightLow, whole: true},
+    }
+}
+
+func groupFields(g *msg.Group) []searchField {
+    if g == nil {
+        return nil
+    }
+    return []searchField{
+        {text: g.Title, weight: weightHigh},
+    }
+}
+
+// normalizeRune maps the Persian/Arabic variants of a letter and the non-latin digits to a single form
+func normalizeRune(r rune) rune {
+    switch r {
+    case 'ي', 'ى':
+        return 'ی'
+    case 'ك':
+        return 'ک'
+    case 'ة':
+        return 'ه'
+    case 'ٱ':
+        return 'ا'
+    }
+    switch {
+    case r >= '۰' && r <= '۹':
+        return '0' + r - '۰'
+    case r >= '٠' && r <= '٩':
+        return '0' + r - '٠'
+    }
+    return unicode.ToLower(r)
+}
+
+// tokenize splits the text into normalized tokens. Text is decomposed (NFD) to drop the diacritics, e.g.
+// 'é' and 'آ' are indexed as 'e' and 'ا'. Tatweel and zero-width joiners are dropped without splitting the word.
+func tokenize(text string) []string {
+    var (
+        tokens []string
+        sb     strings.Builder
+    )
+    flush := func() {
+        if sb.Len() > 0 {
+            tokens = append(tokens, sb.String())
+            sb.Reset()
+        }
+    }
+    for _, r := range norm.NFD.String(text) {
+        switch {
+        case unicode.Is(unicode.Mn, r), r == '\u0640', r == '\u200c', r == '\u200d':
+        case unicode.IsLetter(r), unicode.IsDigit(r):
+            sb.WriteRune(normalizeRune(r))
+        default:
+            flush()
+        }
+    }
+    flush()
+    return tokens
+}
+
+func indexTokens(fields []searchField) map[string]byte {
+    tokens := make(map[string]byte)
+    add := func(t string, w byte) {
+        if tokens[t] < w {
+            tokens[t] = w
+        }
+    }
+    for _, f := range fields {
+        fieldTokens := tokenize(f.text)
+        for _, t := range fieldTokens {
+            add(t, f.weight)
+        }
+        if f.whole && len(fieldTokens) > 1 {
+            add(strings.Join(fieldTokens, ""), f.weight)
+        }
+    }
+    return tokens
+}
+
+func indexKey(token string, docKey []byte) []byte {
+    k := make([]byte, 0, len(token)+1+len(docKey))
+    k = append(k, token...)
+    k = append(k, 0)
+    return append(k, docKey...)
+}
+
+// updateIndex replaces the tokens of the document in the index, it removes the tokens of the old fields which
+// do not exist in the new fields.
+func updateIndex(b *bolt.Bucket, docKey []byte, oldFields, newFields []searchField) error {
+    oldTokens := indexTokens(oldFields)
+    newTokens := indexTokens(newFields)
+    for t := range oldTokens {
+        if _, ok := newTokens[t]; !ok {
+            if err := b.Delete(indexKey(t, docKey)); err != nil {
+                return err
+            }
+        }
+    }
+    for t, w := range newTokens {
+        if oldTokens[t] == w {
+            continue
+        }
+        if err := b.Put(indexKey(t, docKey), []byte{w}); err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// searchIndex returns the keys of the documents which have a token starting with each of the terms of the
+// phrase. Results are ranked by the weight of the matched fields, and exact token matches are preferred.
+// If filter is set, documents which it returns false for are skipped.
+func searchIndex(b *bolt.Bucket, phrase string, limit int, filter func(docKey []byte) bool) [][]byte {
+    terms := tokenize(phrase)
+    if len(terms) == 0 {
+        return nil
+    }
+
+    var scores map[string]int
+    for idx, term := range terms {
+        termScores := make(map[string]int)
+        prefix := []byte(term)
+        c := b.Cursor()
+        for k, v := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, v = c.Next() {
+            sep := bytes.IndexByte(k[len(prefix):], 0)
+            if sep < 0 || len(v) == 0 {
+                continue
+            }
+            docKey := k[len(prefix)+sep+1:]
+            if filter != nil && !filter(docKey) {
+                continue
+            }
+            score := int(v[0]) * 4
+            if sep == 0 {
+                score += 2
+            }
+            if score > termScores[string(docKey)] {
+                termScores[string(docKey)] = score
+            }
+        }
+
+        if idx == 0 {
+            scores = termScores
+            continue
+        }
+        for docKey, score := range scores {
+            if termScore, ok := termScores[docKey]; ok {
+                scores[docKey] = score + termScore
+            } else {
+                delete(scores, docKey)
+            }
+        }
+    }
+
+    docKeys := make([]string, 0, len(scores))
+    for docKey := range scores {
+        docKeys = append(docKeys, docKey)
+    }
+    sort.Slice(docKeys, func(i, j int) bool {
+        if scores[docKeys[i]] != scores[docKeys[j]] {
+            return scores[docKeys[i]] > scores[docKeys[j]]
+        }
+        return docKeys[i] < docKeys[j]
+    })
+    if limit > 0 && len(docKeys) > limit {
+        docKeys = docKeys[:limit]
+    }
+    res := make([][]byte, 0, len(docKeys))
+    for _, docKey := range docKeys {
+        res = append(res, []byte(docKey))
+    }
+    return res
+}
+
+// reindex builds the search indices from the stored documents, it is used when the indices are created for
+// a database which already has data.
+func (r *repository) reindex() error {
+    return r.db.Update(func(tx *bolt.Tx) error {
+        indices := []struct {
+            bucket []byte
+            index  []byte
+            fields func(v []byte) []searchField
+        }{
+            {
+                bucket: bucketUsers, index: bucketSearchUsers,
+                fields: func(v []byte) []searchField {
+                    u := &msg.User{}
+                    _ = u.Unmarshal(v)
+                    return userFields(u)
+                },
+            },
+            {
+                bucket: bucketContacts, index: bucketSearchContacts,
+                fields: func(v []byte) []searchField {
+                    c := &msg.ContactUser{}
+                    _ = c.Unmarshal(v)
+                    return contactFields(c)
+                },
+            },
+            {
+                bucket: bucketGroups, index: bucketSearchGroups,
+                fields: func(v []byte) []searchField {
+                    g := &msg.Group{}
+                    _ = g.Unmarshal(v)
+                    return groupFields(g)
+                },
+            },
+        }
+        for _, idx := range indices {
+            ib := tx.Bucket(idx.index)
+            err := tx.Bucket(idx.bucket).ForEach(func(k, v []byte) error {
+                return updateIndex(ib, k, nil, idx.fields(v))
+            })
+            if err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+}
@@ -0,0 +1,94 @@
+package minirepo_test
+
+import (
+    "testing"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/minirepo"
+    "github.com/ronaksoft/rony/tools"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func contactIDs(contacts []*msg.ContactUser) []int64 {
+    ids := make([]int64, 0, len(contacts))
+    for _, c := range contacts {
+        ids = append(ids, c.ID)
+    }
+    return ids
+}
+
+func TestSearch(t *testing.T) {
+    Convey("MiniRepo Search", t, func(c C) {
+        teamID := tools.RandomInt64(0)
+        contacts := []*msg.ContactUser{
+            {ID: 1, FirstName: "علي", LastName: "كريمي"},
+            {ID: 2, FirstName: "José", LastName: "Álvarez"},
+            {ID: 3, FirstName: "مُحَمَّد", LastName: "رضایی"},
+            {ID: 4, FirstName: "Reza", LastName: "Ahmadi", Username: "ali_reza"},
+            {ID: 5, FirstName: "Alireza", LastName: "Ahmadi"},
+        }
+        users := make([]*msg.User, 0, len(contacts))
+        for _, cu := range contacts {
+            users = append(users, &msg.User{ID: cu.ID, FirstName: cu.FirstName, LastName: cu.LastName, Username: cu.Username})
+        }
+        err := minirepo.Users.SaveAllContacts(teamID, &msg.ContactsMany{
+            ContactUsers: contacts,
+            Users:        users,
+            Modified:     true,
+        })
+        c.So(err, ShouldBeNil)
+
+        Convey("Normalized Matching", func(c C) {
+            c.So(contactIDs(minirepo.Users.SearchContacts(teamID, "علی", 10)), ShouldResemble, []int64{1})
+            c.So(contactIDs(minirepo.Users.SearchContacts(teamID, "کریمی", 10)), ShouldResemble, []int64{1})
+            c.So(contactIDs(minirepo.Users.SearchContacts(teamID, "JOSE alv", 10)), ShouldResemble, []int64{2})
+            c.So(contactIDs(minirepo.Users.SearchContacts(teamID, "محمد", 10)), ShouldResemble, []int64{3})
+            c.So(contactIDs(minirepo.Users.SearchContacts(teamID, "رضايي", 10)), ShouldResemble, []int64{3})
+            c.So(minirepo.Users.SearchContacts(teamID+1, "jose", 10), ShouldBeEmpty)
+        })
+        Convey("Ranking", func(c C) {
+            // Exact first name is ranked higher than the prefix match and the username match
+            c.So(contactIDs(minirepo.Users.SearchContacts(teamID, "reza", 10)), ShouldResemble, []int64{4})
+            c.So(contactIDs(minirepo.Users.SearchContacts(teamID, "ali", 10)), ShouldResemble, []int64{5, 4})
+            c.So(contactIDs(minirepo.Users.SearchContacts(teamID, "alireza", 10)), ShouldResemble, []int64{5, 4})
+            c.So(contactIDs(minirepo.Users.SearchContacts(teamID, "ahmadi", 1)), ShouldResemble, []int64{4})
+        })
+        Convey("Update Index", func(c C) {
+            c.So(minirepo.Users.SaveUser(&msg.User{ID: 2, FirstName: "Jacob"}), ShouldBeNil)
+            found := minirepo.Users.Search("jose", 10)
+            c.So(found, ShouldBeEmpty)
+            found = minirepo.Users.Search("jac", 10)
+            c.So(found, ShouldHaveLength, 1)
+            c.So(found[0].ID, ShouldEqual, 2)
+
+            minirepo.Users.DeleteContact(teamID, 2)
+            c.So(minirepo.Users.SearchContacts(teamID, "jose", 10), ShouldBeEmpty)
+        })
+        Convey("Groups", func(c C) {
+            err := minirepo.Groups.Save(
+                &msg.Group{TeamID: teamID, ID: 10, Title: "Ronak Team"},
+                &msg.Group{TeamID: teamID, ID: 11, Title: "گروه كتاب"},
+            )
+            c.So(err, ShouldBeNil)
+            groups := minirepo.Groups.Search(teamID, "کتاب", 10)
+            c.So(groups, ShouldHaveLength, 1)
+            c.So(groups[0].ID, ShouldEqual, 11)
+            groups = minirepo.Groups.Search(teamID, "ron", 10)
+            c.So(groups, ShouldHaveLength, 1)
+
+            c.So(minirepo.Groups.Save(&msg.Group{TeamID: teamID, ID: 10, Title: "River"}), ShouldBeNil)
+            c.So(minirepo.Groups.Search(teamID, "ron", 10), ShouldBeEmpty)
+            c.So(minirepo.Groups.Delete(teamID, 11), ShouldBeNil)
+            c.So(minirepo.Groups.Search(teamID, "کتاب", 10), ShouldBeEmpty)
+        })
+    })
+}
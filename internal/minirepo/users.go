@@ -3,7 +3,6 @@ package minirepo
 import (
     "encoding/binary"
     "fmt"
-    "strings"
 
     "github.com/boltdb/bolt"
     "github.com/ronaksoft/river-msg/go/msg"
@@ -56,10 +55,17 @@ func (d *repoUsers) getUser(alloc *tools.Allocator, b *bolt.Bucket, userID int64
 
 func (d *repoUsers) saveContact(alloc *tools.Allocator, tx *bolt.Tx, teamID int64, contact *msg.ContactUser, lastSeen int64) error {
     b := tx.Bucket(bucketContacts)
-    err := b.Put(
-        alloc.Gen(teamID, contact.ID),
-        alloc.Marshal(contact),
-    )
+    key := alloc.Gen(teamID, contact.ID)
+    var oldContact *msg.ContactUser
+    if v := b.Get(key); len(v) > 0 {
+        oldContact = &msg.ContactUser{}
+        _ = oldContact.Unmarshal(v)
+    }
+    err := b.Put(key, alloc.Marshal(contact))
+    if err != nil {
+        return err
+    }
+    err = updateIndex(tx.Bucket(bucketSearchContacts), key, contactFields(oldContact), contactFields(contact))
     if err != nil {
         return err
     }
@@ -77,13 +83,38 @@ func (d *repoUsers) saveContact(alloc *tools.Allocator, tx *bolt.Tx, teamID int6
     return nil
 }
 
+func (d *repoUsers) SaveContact(teamID int64, contact *msg.ContactUser, lastSeen int64) error {
+    alloc := tools.NewAllocator()
+    defer alloc.ReleaseAll()
+
+    return d.db.Update(func(tx *bolt.Tx) error {
+        return d.saveContact(alloc, tx, teamID, contact, lastSeen)
+    })
+}
+
+func (d *repoUsers) DeleteAllContacts(teamID int64) {
+    contacts, err := d.ReadAllContacts(teamID)
+    if err != nil {
+        return
+    }
+    for _, c := range contacts.ContactUsers {
+        d.DeleteContact(teamID, c.ID)
+    }
+}
+
 func (d *repoUsers) DeleteContact(teamID int64, userID int64) {
     alloc := tools.NewAllocator()
     defer alloc.ReleaseAll()
 
     _ = d.db.Update(func(tx *bolt.Tx) error {
         b := tx.Bucket(bucketContacts)
-        _ = b.Delete(alloc.Gen(teamID, userID))
+        key := alloc.Gen(teamID, userID)
+        if v := b.Get(key); len(v) > 0 {
+            oldContact := &msg.ContactUser{}
+            _ = oldContact.Unmarshal(v)
+            _ = updateIndex(tx.Bucket(bucketSearchContacts), key, contactFields(oldContact), nil)
+        }
+        _ = b.Delete(key)
         _ = d.index.Update(func(tx *buntdb.Tx) error {
             _, err := tx.Delete(fmt.Sprintf("%s.%d.%d", prefixContacts, teamID, userID))
             return err
@@ -99,8 +130,15 @@ func (d *repoUsers) SaveUser(users ...*msg.User) error {
 
     return d.db.Update(func(tx *bolt.Tx) error {
         b1 := tx.Bucket(bucketUsers)
+        b2 := tx.Bucket(bucketSearchUsers)
         for _, user := range users {
-            err := b1.Put(alloc.Gen(user.ID), alloc.Marshal(user))
+            key := alloc.Gen(user.ID)
+            oldUser, _ := d.getUser(alloc, b1, user.ID)
+            err := b1.Put(key, alloc.Marshal(user))
+            if err != nil {
+                return err
+            }
+            err = updateIndex(b2, key, userFields(oldUser), userFields(user))
             if err != nil {
                 return err
             }
@@ -229,52 +267,38 @@ func (d *repoUsers) ReadMany(userIDs ...int64) ([]*msg.User, error) {
     return users, nil
 }
 
+// Search returns the users whose names or username match the phrase, ranked by relevance
 func (d *repoUsers) Search(phrase string, limit int) []*msg.User {
     users := make([]*msg.User, 0, limit)
     _ = d.db.View(func(tx *bolt.Tx) error {
         b := tx.Bucket(bucketUsers)
-        _ = b.ForEach(func(k, v []byte) error {
-            u := &msg.User{}
-            _ = u.Unmarshal(v)
-
-            if strings.Contains(strings.ToLower(u.FirstName), phrase) ||
-                    strings.Contains(strings.ToLower(u.LastName), phrase) ||
-                    strings.Contains(strings.ToLower(u.Username), phrase) {
+        for _, key := range searchIndex(tx.Bucket(bucketSearchUsers), phrase, limit, nil) {
+            if v := b.Get(key); len(v) > 0 {
+                u := &msg.User{}
+                _ = u.Unmarshal(v)
                 users = append(users, u)
-                limit--
             }
-            if limit < 0 {
-                return domain.ErrLimitReached
-            }
-            return nil
-        })
+        }
         return nil
     })
     return users
 }
 
+// SearchContacts returns the contacts of the team whose names or username match the phrase, ranked by relevance
 func (d *repoUsers) SearchContacts(teamID int64, phrase string, limit int) []*msg.ContactUser {
     contacts := make([]*msg.ContactUser, 0, limit)
     _ = d.db.View(func(tx *bolt.Tx) error {
         b := tx.Bucket(bucketContacts)
-        _ = b.ForEach(func(k, v []byte) error {
-            if binary.BigEndian.Uint64(k[1:9]) != uint64(teamID) {
-                return nil
-            }
-
-            c := &msg.ContactUser{}
-            _ = c.Unmarshal(v)
-            if strings.Contains(strings.ToLower(c.FirstName), phrase) ||
-                    strings.Contains(strings.ToLower(c.LastName), phrase) ||
-                    strings.Contains(strings.ToLower(c.Username), phrase) {
+        keys := searchIndex(tx.Bucket(bucketSearchContacts), phrase, limit, func(key []byte) bool {
+            return len(key) >= 9 && binary.BigEndian.Uint64(key[1:9]) == uint64(teamID)
+        })
+        for _, key := range keys {
+            if v := b.Get(key); len(v) > 0 {
+                c := &msg.ContactUser{}
+                _ = c.Unmarshal(v)
                 contacts = append(contacts, c)
-                limit--
             }
-            if limit < 0 {
-                return domain.ErrLimitReached
-            }
-            return nil
-        })
+        }
         return nil
     })
     return contacts
@@ -54,3 +54,96 @@ func (r *repoAccount) GetPrivacy(key msg.PrivacyKey) (*msg.AccountPrivacyRules,
     }
     return accountPrivacyRules, nil
 }
+
+func getAuthorizationsKey() []byte {
+    return tools.StrToByte(fmt.Sprintf("%s.AUTHORIZATIONS", prefixAccount))
+}
+
+func getAuthorizations(txn *badger.Txn) (*msg.AccountAuthorizations, error) {
+    auths := &msg.AccountAuthorizations{}
+    item, err := txn.Get(getAuthorizationsKey())
+    if err != nil {
+        return nil, err
+    }
+    err = item.Value(func(val []byte) error {
+        return auths.Unmarshal(val)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return auths, nil
+}
+
+func saveAuthorizations(txn *badger.Txn, auths *msg.AccountAuthorizations) error {
+    bytes, _ := auths.Marshal()
+    return txn.SetEntry(badger.NewEntry(getAuthorizationsKey(), bytes))
+}
+
+// SaveAuthorizations replaces the cached list of the active sessions of the account
+func (r *repoAccount) SaveAuthorizations(auths *msg.AccountAuthorizations) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        return saveAuthorizations(txn, auths)
+    })
+}
+
+// GetAuthorizations returns the cached list of the active sessions, it returns badger.ErrKeyNotFound if the list
+// has not been cached yet.
+func (r *repoAccount) GetAuthorizations() (*msg.AccountAuthorizations, error) {
+    var auths *msg.AccountAuthorizations
+    err := badgerView(func(txn *badger.Txn) (err error) {
+        auths, err = getAuthorizations(txn)
+        return
+    })
+    return auths, err
+}
+
+// DeleteAuthorization removes the session from the cached list and returns it, if it was in the list.
+func (r *repoAccount) DeleteAuthorization(authID int64) (*msg.AccountAuthorization, error) {
+    var deleted *msg.AccountAuthorization
+    err := badgerUpdate(func(txn *badger.Txn) error {
+        deleted = nil
+        auths, err := getAuthorizations(txn)
+        switch err {
+        case nil:
+        case badger.ErrKeyNotFound:
+            return nil
+        default:
+            return err
+        }
+        list := auths.Authorizations[:0]
+        for _, auth := range auths.Authorizations {
+            if auth.AuthID == authID {
+                deleted = auth
+                continue
+            }
+            list = append(list, auth)
+        }
+        if deleted == nil {
+            return nil
+        }
+        auths.Authorizations = list
+        return saveAuthorizations(txn, auths)
+    })
+    return deleted, err
+}
+
+// AddAuthorization adds the session to the cached list, if the list has been cached
+func (r *repoAccount) AddAuthorization(auth *msg.AccountAuthorization) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        auths, err := getAuthorizations(txn)
+        switch err {
+        case nil:
+        case badger.ErrKeyNotFound:
+            return nil
+        default:
+            return err
+        }
+        for _, a := range auths.Authorizations {
+            if a.AuthID == auth.AuthID {
+                return nil
+            }
+        }
+        auths.Authorizations = append(auths.Authorizations, auth)
+        return saveAuthorizations(txn, auths)
+    })
+}
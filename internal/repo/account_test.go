@@ -0,0 +1,47 @@
+package repo_test
+
+import (
+    "testing"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func TestAuthorizations(t *testing.T) {
+    Convey("Authorizations", t, func(c C) {
+        err := repo.Account.SaveAuthorizations(&msg.AccountAuthorizations{
+            Authorizations: []*msg.AccountAuthorization{
+                {AuthID: 1, Model: "Desktop"},
+                {AuthID: 2, Model: "Phone"},
+            },
+        })
+        c.So(err, ShouldBeNil)
+
+        auth, err := repo.Account.DeleteAuthorization(2)
+        c.So(err, ShouldBeNil)
+        c.So(auth, ShouldNotBeNil)
+        c.So(auth.Model, ShouldEqual, "Phone")
+        auths, err := repo.Account.GetAuthorizations()
+        c.So(err, ShouldBeNil)
+        c.So(auths.Authorizations, ShouldHaveLength, 1)
+
+        auth, err = repo.Account.DeleteAuthorization(3)
+        c.So(err, ShouldBeNil)
+        c.So(auth, ShouldBeNil)
+
+        c.So(repo.Account.AddAuthorization(&msg.AccountAuthorization{AuthID: 2, Model: "Phone"}), ShouldBeNil)
+        auths, err = repo.Account.GetAuthorizations()
+        c.So(err, ShouldBeNil)
+        c.So(auths.Authorizations, ShouldHaveLength, 2)
+    })
+}
@@ -0,0 +1,99 @@
+package repo
+
+import (
+    "encoding/json"
+
+    "github.com/dgraph-io/badger/v2"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/z"
+    "github.com/ronaksoft/rony/pools"
+    "github.com/ronaksoft/rony/tools"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+const (
+    prefixBotStates = "BOT_STATES"
+)
+
+// BotState is the state of the conversation of a bot in a chat. It is used by the bot runtime to keep the step of
+// multi-message conversations between the restarts.
+type BotState struct {
+    TeamID    int64             `json:"TeamID"`
+    PeerID    int64             `json:"PeerID"`
+    PeerType  int32             `json:"PeerType"`
+    Step      string            `json:"Step"`
+    Values    map[string]string `json:"Values"`
+    UpdatedOn int64             `json:"UpdatedOn"`
+}
+
+type repoBotStates struct {
+    *repository
+}
+
+func getBotStateKey(teamID, peerID int64, peerType int32) []byte {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(prefixBotStates)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, teamID)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, peerID)
+    sb.WriteRune('.')
+    z.AppendStrInt32(sb, peerType)
+    id := tools.StrToByte(sb.String())
+    pools.ReleaseStringsBuilder(sb)
+    return id
+}
+
+// Get returns the state of the chat, if there is no state an empty one is returned
+func (r *repoBotStates) Get(teamID, peerID int64, peerType int32) (*BotState, error) {
+    bs := &BotState{
+        TeamID:   teamID,
+        PeerID:   peerID,
+        PeerType: peerType,
+    }
+    err := badgerView(func(txn *badger.Txn) error {
+        item, err := txn.Get(getBotStateKey(teamID, peerID, peerType))
+        switch err {
+        case nil:
+        case badger.ErrKeyNotFound:
+            return nil
+        default:
+            return err
+        }
+        return item.Value(func(val []byte) error {
+            return json.Unmarshal(val, bs)
+        })
+    })
+    if err != nil {
+        return nil, err
+    }
+    if bs.Values == nil {
+        bs.Values = make(map[string]string)
+    }
+    return bs, nil
+}
+
+func (r *repoBotStates) Save(bs *BotState) error {
+    bs.UpdatedOn = domain.Now().Unix()
+    b, err := json.Marshal(bs)
+    if err != nil {
+        return err
+    }
+    return badgerUpdate(func(txn *badger.Txn) error {
+        return txn.SetEntry(badger.NewEntry(getBotStateKey(bs.TeamID, bs.PeerID, bs.PeerType), b))
+    })
+}
+
+func (r *repoBotStates) Delete(teamID, peerID int64, peerType int32) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        return txn.Delete(getBotStateKey(teamID, peerID, peerType))
+    })
+}
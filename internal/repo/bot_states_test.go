@@ -0,0 +1,51 @@
+package repo_test
+
+import (
+    "testing"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/rony/tools"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func TestBotStates(t *testing.T) {
+    Convey("Bot States", t, func(c C) {
+        teamID := tools.RandomInt64(0)
+        peerID := tools.RandomInt64(0)
+        peerType := int32(msg.PeerType_PeerUser)
+
+        bs, err := repo.BotStates.Get(teamID, peerID, peerType)
+        c.So(err, ShouldBeNil)
+        c.So(bs.Step, ShouldBeEmpty)
+        c.So(bs.Values, ShouldBeEmpty)
+
+        bs.Step = "ask_name"
+        bs.Values["lang"] = "en"
+        c.So(repo.BotStates.Save(bs), ShouldBeNil)
+
+        bs, err = repo.BotStates.Get(teamID, peerID, peerType)
+        c.So(err, ShouldBeNil)
+        c.So(bs.Step, ShouldEqual, "ask_name")
+        c.So(bs.Values["lang"], ShouldEqual, "en")
+        c.So(bs.UpdatedOn, ShouldBeGreaterThan, 0)
+
+        bs, err = repo.BotStates.Get(teamID, peerID, int32(msg.PeerType_PeerGroup))
+        c.So(err, ShouldBeNil)
+        c.So(bs.Step, ShouldBeEmpty)
+
+        c.So(repo.BotStates.Delete(teamID, peerID, peerType), ShouldBeNil)
+        bs, err = repo.BotStates.Get(teamID, peerID, peerType)
+        c.So(err, ShouldBeNil)
+        c.So(bs.Step, ShouldBeEmpty)
+    })
+}
@@ -0,0 +1,161 @@
+package repo
+
+import (
+    "github.com/dgraph-io/badger/v2"
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/z"
+    "github.com/ronaksoft/rony"
+    "github.com/ronaksoft/rony/pools"
+    "github.com/ronaksoft/rony/tools"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+const (
+    prefixCalendarEvents  = "CAL_EVT"
+    prefixCalendarResults = "CAL_RES"
+)
+
+// repoCalendar keeps the events and the results of CalendarGetEvents. river-msg does not define the result of
+// CalendarGetEvents, hence the results are kept as they are received from the server, indexed by the team and the
+// time range which they are fetched for.
+type repoCalendar struct {
+    *repository
+}
+
+func getCalendarEventKey(eventID int64) []byte {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(prefixCalendarEvents)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, eventID)
+    id := tools.StrToByte(sb.String())
+    pools.ReleaseStringsBuilder(sb)
+    return id
+}
+
+func getCalendarResultKey(teamID int64, filter int32, from, to int64) []byte {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(prefixCalendarResults)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, teamID)
+    sb.WriteRune('.')
+    z.AppendStrInt32(sb, filter)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, from)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, to)
+    id := tools.StrToByte(sb.String())
+    pools.ReleaseStringsBuilder(sb)
+    return id
+}
+
+func saveCalendarEvent(txn *badger.Txn, event *msg.CalendarEvent) error {
+    eventBytes, _ := event.Marshal()
+    return txn.SetEntry(badger.NewEntry(getCalendarEventKey(event.ID), eventBytes))
+}
+
+func getCalendarEvent(txn *badger.Txn, eventID int64) (*msg.CalendarEvent, error) {
+    event := &msg.CalendarEvent{}
+    item, err := txn.Get(getCalendarEventKey(eventID))
+    if err != nil {
+        return nil, err
+    }
+    err = item.Value(func(val []byte) error {
+        return event.Unmarshal(val)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return event, nil
+}
+
+// SaveResult saves the result of CalendarGetEvents for the time range
+func (r *repoCalendar) SaveResult(teamID int64, filter int32, from, to int64, res *rony.MessageEnvelope) error {
+    resBytes, err := (&rony.MessageEnvelope{
+        Constructor: res.Constructor,
+        Message:     res.Message,
+    }).Marshal()
+    if err != nil {
+        return err
+    }
+    return badgerUpdate(func(txn *badger.Txn) error {
+        return txn.SetEntry(badger.NewEntry(getCalendarResultKey(teamID, filter, from, to), resBytes))
+    })
+}
+
+// GetResult returns the saved result of CalendarGetEvents for the time range. The result could not be read, hence
+// only the exact time range which has been fetched is answered.
+func (r *repoCalendar) GetResult(teamID int64, filter int32, from, to int64) (*rony.MessageEnvelope, error) {
+    res := &rony.MessageEnvelope{}
+    err := badgerView(func(txn *badger.Txn) error {
+        item, err := txn.Get(getCalendarResultKey(teamID, filter, from, to))
+        if err != nil {
+            return err
+        }
+        return item.Value(func(val []byte) error {
+            return res.Unmarshal(val)
+        })
+    })
+    if err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (r *repoCalendar) GetEvent(eventID int64) (*msg.CalendarEvent, error) {
+    var event *msg.CalendarEvent
+    err := badgerView(func(txn *badger.Txn) (err error) {
+        event, err = getCalendarEvent(txn, eventID)
+        return
+    })
+    return event, err
+}
+
+// SaveEvent saves the event and deletes the saved results. The instances of the event are not known, and for
+// recurring events they could be anywhere, hence the next CalendarGetEvents must be served by the server.
+func (r *repoCalendar) SaveEvent(event *msg.CalendarEvent) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        err := saveCalendarEvent(txn, event)
+        if err != nil {
+            return err
+        }
+        return deleteCalendarResults(txn)
+    })
+}
+
+// DeleteEvent deletes the event and the saved results which could have its instances
+func (r *repoCalendar) DeleteEvent(eventID int64) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        err := txn.Delete(getCalendarEventKey(eventID))
+        if err != nil {
+            return err
+        }
+        return deleteCalendarResults(txn)
+    })
+}
+
+func deleteCalendarResults(txn *badger.Txn) error {
+    opts := badger.DefaultIteratorOptions
+    opts.Prefix = tools.StrToByte(prefixCalendarResults + ".")
+    opts.PrefetchValues = false
+    it := txn.NewIterator(opts)
+    var keys [][]byte
+    for it.Rewind(); it.ValidForPrefix(opts.Prefix); it.Next() {
+        keys = append(keys, it.Item().KeyCopy(nil))
+    }
+    it.Close()
+    for _, key := range keys {
+        err := txn.Delete(key)
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
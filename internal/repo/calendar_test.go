@@ -0,0 +1,64 @@
+package repo_test
+
+import (
+    "testing"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/rony"
+    "github.com/ronaksoft/rony/tools"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func TestCalendar(t *testing.T) {
+    Convey("Calendar", t, func(c C) {
+        teamID := tools.RandomInt64(0)
+        eventID := tools.RandomInt64(0)
+        res := &rony.MessageEnvelope{
+            RequestID:   tools.RandomUint64(0),
+            Constructor: tools.RandomInt64(0),
+            Message:     []byte{1, 2, 3},
+        }
+        c.So(repo.Calendar.SaveResult(teamID, 0, 1000, 2000, res), ShouldBeNil)
+
+        Convey("Saved Results", func(c C) {
+            x, err := repo.Calendar.GetResult(teamID, 0, 1000, 2000)
+            c.So(err, ShouldBeNil)
+            c.So(x.Constructor, ShouldEqual, res.Constructor)
+            c.So(x.Message, ShouldResemble, res.Message)
+            // the request of the result is not saved
+            c.So(x.RequestID, ShouldBeZeroValue)
+
+            _, err = repo.Calendar.GetResult(teamID, 0, 1000, 2500)
+            c.So(err, ShouldNotBeNil)
+            _, err = repo.Calendar.GetResult(teamID, 1, 1000, 2000)
+            c.So(err, ShouldNotBeNil)
+            _, err = repo.Calendar.GetResult(teamID+1, 0, 1000, 2000)
+            c.So(err, ShouldNotBeNil)
+        })
+        Convey("Event Updates", func(c C) {
+            c.So(repo.Calendar.SaveEvent(&msg.CalendarEvent{ID: eventID, Name: "Weekly"}), ShouldBeNil)
+            _, err := repo.Calendar.GetResult(teamID, 0, 1000, 2000)
+            c.So(err, ShouldNotBeNil)
+            event, err := repo.Calendar.GetEvent(eventID)
+            c.So(err, ShouldBeNil)
+            c.So(event.Name, ShouldEqual, "Weekly")
+
+            c.So(repo.Calendar.SaveResult(teamID, 0, 1000, 2000, res), ShouldBeNil)
+            c.So(repo.Calendar.DeleteEvent(eventID), ShouldBeNil)
+            _, err = repo.Calendar.GetResult(teamID, 0, 1000, 2000)
+            c.So(err, ShouldNotBeNil)
+            _, err = repo.Calendar.GetEvent(eventID)
+            c.So(err, ShouldNotBeNil)
+        })
+    })
+}
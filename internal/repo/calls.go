@@ -0,0 +1,277 @@
+package repo
+
+import (
+    "encoding/json"
+    "sort"
+
+    "github.com/dgraph-io/badger/v2"
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/z"
+    "github.com/ronaksoft/rony/pools"
+    "github.com/ronaksoft/rony/tools"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+const (
+    prefixCalls = "CALLS"
+)
+
+// CallRecord is the history of a call. It is filled by the call module while the call goes on, and merged with
+// the records of PhoneGetHistory.
+type CallRecord struct {
+    CallID       int64             `json:"CallID"`
+    TeamID       int64             `json:"TeamID"`
+    PeerID       int64             `json:"PeerID"`
+    PeerType     int32             `json:"PeerType"`
+    Participants []int64           `json:"Participants"`
+    Incoming     bool              `json:"Incoming"`
+    Group        bool              `json:"Group"`
+    StartedOn    int64             `json:"StartedOn"`
+    ConnectedOn  int64             `json:"ConnectedOn"`
+    EndedOn      int64             `json:"EndedOn"`
+    EndReason    msg.DiscardReason `json:"EndReason"`
+    Status       int32             `json:"Status"`
+    Rate         int32             `json:"Rate"`
+}
+
+// ToPhoneCallRecord converts the record to the type which is returned by PhoneGetHistory
+func (cr *CallRecord) ToPhoneCallRecord(userID int64) *msg.PhoneCallRecord {
+    return &msg.PhoneCallRecord{
+        UserID:    userID,
+        TeamID:    cr.TeamID,
+        CallID:    cr.CallID,
+        CreatedOn: cr.StartedOn,
+        EndedOn:   cr.EndedOn,
+        Incoming:  cr.Incoming,
+        PeerID:    cr.PeerID,
+        PeerType:  cr.PeerType,
+        Status:    cr.Status,
+    }
+}
+
+type repoCalls struct {
+    *repository
+}
+
+func getCallPrefix(teamID int64) []byte {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(prefixCalls)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, teamID)
+    id := tools.StrToByte(sb.String())
+    pools.ReleaseStringsBuilder(sb)
+    return id
+}
+
+func getCallKey(teamID, callID int64) []byte {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(prefixCalls)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, teamID)
+    z.AppendStrInt64(sb, callID)
+    id := tools.StrToByte(sb.String())
+    pools.ReleaseStringsBuilder(sb)
+    return id
+}
+
+func getCall(txn *badger.Txn, teamID, callID int64) (*CallRecord, error) {
+    cr := &CallRecord{}
+    item, err := txn.Get(getCallKey(teamID, callID))
+    if err != nil {
+        return nil, err
+    }
+    err = item.Value(func(val []byte) error {
+        return json.Unmarshal(val, cr)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return cr, nil
+}
+
+func saveCall(txn *badger.Txn, cr *CallRecord) error {
+    b, err := json.Marshal(cr)
+    if err != nil {
+        return err
+    }
+    return txn.SetEntry(badger.NewEntry(getCallKey(cr.TeamID, cr.CallID), b))
+}
+
+// updateCall calls fn with the record of the call and saves it, if the record does not exist it does nothing.
+func updateCall(teamID, callID int64, fn func(cr *CallRecord)) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        cr, err := getCall(txn, teamID, callID)
+        switch err {
+        case nil:
+        case badger.ErrKeyNotFound:
+            return nil
+        default:
+            return err
+        }
+        fn(cr)
+        return saveCall(txn, cr)
+    })
+}
+
+func appendCallParticipants(participants []int64, userIDs ...int64) []int64 {
+    for _, userID := range userIDs {
+        found := false
+        for _, id := range participants {
+            if id == userID {
+                found = true
+                break
+            }
+        }
+        if !found {
+            participants = append(participants, userID)
+        }
+    }
+    return participants
+}
+
+// Start creates the record of the call, if the call has been already recorded it only adds the participants.
+func (r *repoCalls) Start(cr *CallRecord) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        current, err := getCall(txn, cr.TeamID, cr.CallID)
+        switch err {
+        case nil:
+            current.Participants = appendCallParticipants(current.Participants, cr.Participants...)
+            return saveCall(txn, current)
+        case badger.ErrKeyNotFound:
+            return saveCall(txn, cr)
+        default:
+            return err
+        }
+    })
+}
+
+func (r *repoCalls) AddParticipants(teamID, callID int64, userIDs ...int64) error {
+    return updateCall(teamID, callID, func(cr *CallRecord) {
+        cr.Participants = appendCallParticipants(cr.Participants, userIDs...)
+    })
+}
+
+// SetConnected sets the time which the call has been connected for the first time
+func (r *repoCalls) SetConnected(teamID, callID, connectedOn int64) error {
+    return updateCall(teamID, callID, func(cr *CallRecord) {
+        if cr.ConnectedOn == 0 {
+            cr.ConnectedOn = connectedOn
+        }
+    })
+}
+
+// SetEnded sets the end time and the reason of the call, the first end wins
+func (r *repoCalls) SetEnded(teamID, callID, endedOn int64, reason msg.DiscardReason) error {
+    return updateCall(teamID, callID, func(cr *CallRecord) {
+        if cr.EndedOn == 0 {
+            cr.EndedOn = endedOn
+            cr.EndReason = reason
+        }
+    })
+}
+
+func (r *repoCalls) SetRate(teamID, callID int64, rate int32) error {
+    return updateCall(teamID, callID, func(cr *CallRecord) {
+        cr.Rate = rate
+    })
+}
+
+// SaveHistory merges the records of PhoneGetHistory with our records. The server is the source of truth for the
+// fields it has, the local only fields (e.g. ConnectedOn, Participants) are kept.
+func (r *repoCalls) SaveHistory(records ...*msg.PhoneCallRecord) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        for _, pcr := range records {
+            cr, err := getCall(txn, pcr.TeamID, pcr.CallID)
+            switch err {
+            case nil:
+            case badger.ErrKeyNotFound:
+                cr = &CallRecord{
+                    CallID: pcr.CallID,
+                    TeamID: pcr.TeamID,
+                }
+            default:
+                return err
+            }
+            cr.PeerID = pcr.PeerID
+            cr.PeerType = pcr.PeerType
+            cr.Group = pcr.PeerType == int32(msg.PeerType_PeerGroup)
+            cr.Incoming = pcr.Incoming
+            cr.Status = pcr.Status
+            if pcr.CreatedOn != 0 {
+                cr.StartedOn = pcr.CreatedOn
+            }
+            if pcr.EndedOn != 0 {
+                cr.EndedOn = pcr.EndedOn
+            }
+            err = saveCall(txn, cr)
+            if err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+}
+
+func (r *repoCalls) Get(teamID, callID int64) (*CallRecord, error) {
+    var cr *CallRecord
+    err := badgerView(func(txn *badger.Txn) (err error) {
+        cr, err = getCall(txn, teamID, callID)
+        return
+    })
+    return cr, err
+}
+
+// List returns the calls of the team, the newest first. If after is set, only the calls which have been started
+// before the call with ID of after, are returned.
+func (r *repoCalls) List(teamID, after int64, limit int32) ([]*CallRecord, error) {
+    records := make([]*CallRecord, 0, limit)
+    err := badgerView(func(txn *badger.Txn) error {
+        opts := badger.DefaultIteratorOptions
+        opts.Prefix = getCallPrefix(teamID)
+        it := txn.NewIterator(opts)
+        defer it.Close()
+        for it.Rewind(); it.ValidForPrefix(opts.Prefix); it.Next() {
+            cr := &CallRecord{}
+            err := it.Item().Value(func(val []byte) error {
+                return json.Unmarshal(val, cr)
+            })
+            if err != nil {
+                return err
+            }
+            records = append(records, cr)
+        }
+        return nil
+    })
+    if err != nil {
+        return nil, err
+    }
+
+    sort.Slice(records, func(i, j int) bool {
+        if records[i].StartedOn == records[j].StartedOn {
+            return records[i].CallID > records[j].CallID
+        }
+        return records[i].StartedOn > records[j].StartedOn
+    })
+    if after != 0 {
+        idx := 0
+        for idx < len(records) && records[idx].CallID != after {
+            idx++
+        }
+        if idx < len(records) {
+            idx++
+        }
+        records = records[idx:]
+    }
+    if limit > 0 && int32(len(records)) > limit {
+        records = records[:limit]
+    }
+    return records, nil
+}
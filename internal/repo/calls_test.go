@@ -0,0 +1,78 @@
+package repo_test
+
+import (
+    "testing"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/rony/tools"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func TestCalls(t *testing.T) {
+    Convey("Calls", t, func(c C) {
+        teamID := tools.RandomInt64(0)
+        peerID := tools.RandomInt64(0)
+        for i := int64(1); i <= 3; i++ {
+            err := repo.Calls.Start(&repo.CallRecord{
+                CallID:       i,
+                TeamID:       teamID,
+                PeerID:       peerID,
+                PeerType:     int32(msg.PeerType_PeerUser),
+                Participants: []int64{peerID},
+                StartedOn:    i * 100,
+            })
+            c.So(err, ShouldBeNil)
+        }
+
+        Convey("State Transitions", func(c C) {
+            c.So(repo.Calls.SetConnected(teamID, 1, 110), ShouldBeNil)
+            c.So(repo.Calls.SetConnected(teamID, 1, 120), ShouldBeNil)
+            c.So(repo.Calls.SetEnded(teamID, 1, 150, msg.DiscardReason_DiscardReasonHangup), ShouldBeNil)
+            c.So(repo.Calls.SetEnded(teamID, 1, 160, msg.DiscardReason_DiscardReasonDisconnect), ShouldBeNil)
+            c.So(repo.Calls.AddParticipants(teamID, 1, peerID, 10), ShouldBeNil)
+            c.So(repo.Calls.SetRate(teamID, 1, 4), ShouldBeNil)
+
+            cr, err := repo.Calls.Get(teamID, 1)
+            c.So(err, ShouldBeNil)
+            c.So(cr.ConnectedOn, ShouldEqual, 110)
+            c.So(cr.EndedOn, ShouldEqual, 150)
+            c.So(cr.EndReason, ShouldEqual, msg.DiscardReason_DiscardReasonHangup)
+            c.So(cr.Participants, ShouldHaveLength, 2)
+            c.So(cr.Rate, ShouldEqual, 4)
+        })
+        Convey("History", func(c C) {
+            err := repo.Calls.SaveHistory(
+                &msg.PhoneCallRecord{TeamID: teamID, CallID: 2, CreatedOn: 200, EndedOn: 250, Incoming: true, PeerID: peerID},
+                &msg.PhoneCallRecord{TeamID: teamID, CallID: 4, CreatedOn: 400, PeerID: peerID, PeerType: int32(msg.PeerType_PeerGroup)},
+            )
+            c.So(err, ShouldBeNil)
+            cr, err := repo.Calls.Get(teamID, 2)
+            c.So(err, ShouldBeNil)
+            c.So(cr.Incoming, ShouldBeTrue)
+            c.So(cr.EndedOn, ShouldEqual, 250)
+            c.So(cr.Participants, ShouldHaveLength, 1)
+
+            records, err := repo.Calls.List(teamID, 0, 2)
+            c.So(err, ShouldBeNil)
+            c.So(records, ShouldHaveLength, 2)
+            c.So(records[0].CallID, ShouldEqual, 4)
+            c.So(records[0].Group, ShouldBeTrue)
+            c.So(records[1].CallID, ShouldEqual, 3)
+
+            records, err = repo.Calls.List(teamID, 3, 10)
+            c.So(err, ShouldBeNil)
+            c.So(records, ShouldHaveLength, 2)
+            c.So(records[0].CallID, ShouldEqual, 2)
+        })
+    })
+}
@@ -0,0 +1,294 @@
+package repo
+
+import (
+    "encoding/binary"
+    "math"
+
+    "github.com/dgraph-io/badger/v2"
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/z"
+    "github.com/ronaksoft/rony/pools"
+    "github.com/ronaksoft/rony/tools"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+const (
+    prefixCommunityMessages = "COMM_MSG"
+    prefixCommunityPending  = "COMM_PND"
+    prefixCommunityRead     = "COMM_READ"
+    prefixCommunityOffset   = "COMM_OFFSET"
+)
+
+type repoCommunity struct {
+    *repository
+}
+
+// getCommunityMessagePrefix returns the prefix of the messages of the conversation, keys are sorted by the
+// GlobalMsgID of the messages.
+func getCommunityMessagePrefix(teamID, peerID int64) []byte {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(prefixCommunityMessages)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, teamID)
+    z.AppendStrInt64(sb, peerID)
+    id := tools.StrToByte(sb.String())
+    pools.ReleaseStringsBuilder(sb)
+    return id
+}
+
+func getCommunityMessageKey(teamID, peerID int64, globalMsgID uint64) []byte {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(prefixCommunityMessages)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, teamID)
+    z.AppendStrInt64(sb, peerID)
+    z.AppendStrUInt64(sb, globalMsgID)
+    id := tools.StrToByte(sb.String())
+    pools.ReleaseStringsBuilder(sb)
+    return id
+}
+
+func getCommunityPendingPrefix(teamID int64) []byte {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(prefixCommunityPending)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, teamID)
+    id := tools.StrToByte(sb.String())
+    pools.ReleaseStringsBuilder(sb)
+    return id
+}
+
+func getCommunityPendingKey(teamID, senderMsgID int64) []byte {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(prefixCommunityPending)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, teamID)
+    z.AppendStrInt64(sb, senderMsgID)
+    id := tools.StrToByte(sb.String())
+    pools.ReleaseStringsBuilder(sb)
+    return id
+}
+
+func getCommunityReadKey(teamID, peerID int64) []byte {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(prefixCommunityRead)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, teamID)
+    z.AppendStrInt64(sb, peerID)
+    id := tools.StrToByte(sb.String())
+    pools.ReleaseStringsBuilder(sb)
+    return id
+}
+
+func getCommunityOffsetKey(teamID int64) []byte {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(prefixCommunityOffset)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, teamID)
+    id := tools.StrToByte(sb.String())
+    pools.ReleaseStringsBuilder(sb)
+    return id
+}
+
+func getInt64(txn *badger.Txn, key []byte) (int64, error) {
+    item, err := txn.Get(key)
+    switch err {
+    case nil:
+    case badger.ErrKeyNotFound:
+        return 0, nil
+    default:
+        return 0, err
+    }
+    var v int64
+    err = item.Value(func(val []byte) error {
+        v = int64(binary.BigEndian.Uint64(val))
+        return nil
+    })
+    return v, err
+}
+
+func setInt64(txn *badger.Txn, key []byte, v int64) error {
+    b := make([]byte, 8)
+    binary.BigEndian.PutUint64(b, uint64(v))
+    return txn.SetEntry(badger.NewEntry(key, b))
+}
+
+// CommunityPeer returns the other side of the conversation
+func CommunityPeer(userID, senderID, receiverID int64) int64 {
+    if senderID == userID {
+        return receiverID
+    }
+    return senderID
+}
+
+// SaveMessage saves the message in the conversation and if it has been sent by us, it removes the matching
+// pending message. It returns true if a pending message has been removed.
+func (r *repoCommunity) SaveMessage(userID int64, m *msg.UpdateCommunityMessage) (bool, error) {
+    found := false
+    err := badgerUpdate(func(txn *badger.Txn) error {
+        found = false
+        peerID := CommunityPeer(userID, m.SenderID, m.ReceiverID)
+        messageBytes, _ := m.Marshal()
+        err := txn.SetEntry(badger.NewEntry(getCommunityMessageKey(m.TeamID, peerID, m.GlobalMsgID), messageBytes))
+        if err != nil {
+            return err
+        }
+        if m.SenderID != userID || m.SenderMsgID == 0 {
+            return nil
+        }
+
+        pendingKey := getCommunityPendingKey(m.TeamID, m.SenderMsgID)
+        _, err = txn.Get(pendingKey)
+        switch err {
+        case nil:
+            found = true
+            return txn.Delete(pendingKey)
+        case badger.ErrKeyNotFound:
+            return nil
+        default:
+            return err
+        }
+    })
+    return found, err
+}
+
+// GetMessages returns the messages of the conversation which their GlobalMsgID is less than maxID, newest first.
+// If maxID is zero, it returns the latest messages.
+func (r *repoCommunity) GetMessages(teamID, peerID int64, maxID uint64, limit int) ([]*msg.UpdateCommunityMessage, error) {
+    messages := make([]*msg.UpdateCommunityMessage, 0, limit)
+    err := badgerView(func(txn *badger.Txn) error {
+        opts := badger.DefaultIteratorOptions
+        opts.Prefix = getCommunityMessagePrefix(teamID, peerID)
+        opts.Reverse = true
+        it := txn.NewIterator(opts)
+        defer it.Close()
+        seekKey := getCommunityMessageKey(teamID, peerID, maxID-1)
+        if maxID == 0 {
+            seekKey = getCommunityMessageKey(teamID, peerID, math.MaxUint64)
+        }
+        for it.Seek(seekKey); it.ValidForPrefix(opts.Prefix); it.Next() {
+            if limit--; limit < 0 {
+                break
+            }
+            m := &msg.UpdateCommunityMessage{}
+            err := it.Item().Value(func(val []byte) error {
+                return m.Unmarshal(val)
+            })
+            if err != nil {
+                return err
+            }
+            messages = append(messages, m)
+        }
+        return nil
+    })
+    return messages, err
+}
+
+// SavePending saves the request which has not been acknowledged by the server yet, it is keyed by its SenderMsgID
+func (r *repoCommunity) SavePending(teamID int64, req *msg.CommunitySendMessage) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        reqBytes, _ := req.Marshal()
+        return txn.SetEntry(badger.NewEntry(getCommunityPendingKey(teamID, req.SenderMsgID), reqBytes))
+    })
+}
+
+func (r *repoCommunity) DeletePending(teamID, senderMsgID int64) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        return txn.Delete(getCommunityPendingKey(teamID, senderMsgID))
+    })
+}
+
+// GetPending returns the pending messages of the team, sorted by their SenderMsgID
+func (r *repoCommunity) GetPending(teamID int64) ([]*msg.CommunitySendMessage, error) {
+    pending := make([]*msg.CommunitySendMessage, 0, 4)
+    err := badgerView(func(txn *badger.Txn) error {
+        opts := badger.DefaultIteratorOptions
+        opts.Prefix = getCommunityPendingPrefix(teamID)
+        it := txn.NewIterator(opts)
+        defer it.Close()
+        for it.Rewind(); it.ValidForPrefix(opts.Prefix); it.Next() {
+            req := &msg.CommunitySendMessage{}
+            err := it.Item().Value(func(val []byte) error {
+                return req.Unmarshal(val)
+            })
+            if err != nil {
+                return err
+            }
+            pending = append(pending, req)
+        }
+        return nil
+    })
+    return pending, err
+}
+
+// UpdateReadOutbox saves the SenderMsgID of the last message which the peer has read. It returns false if
+// senderMsgID is not newer than the saved one.
+func (r *repoCommunity) UpdateReadOutbox(teamID, peerID, senderMsgID int64) (bool, error) {
+    updated := false
+    err := badgerUpdate(func(txn *badger.Txn) error {
+        updated = false
+        key := getCommunityReadKey(teamID, peerID)
+        current, err := getInt64(txn, key)
+        if err != nil {
+            return err
+        }
+        if senderMsgID <= current {
+            return nil
+        }
+        updated = true
+        return setInt64(txn, key, senderMsgID)
+    })
+    return updated, err
+}
+
+func (r *repoCommunity) GetReadOutbox(teamID, peerID int64) (senderMsgID int64, err error) {
+    err = badgerView(func(txn *badger.Txn) error {
+        senderMsgID, err = getInt64(txn, getCommunityReadKey(teamID, peerID))
+        return err
+    })
+    return
+}
+
+// GetOffset returns the OffsetID of the last update which has been received by CommunityGetUpdates
+func (r *repoCommunity) GetOffset(teamID int64) (offsetID int64, err error) {
+    err = badgerView(func(txn *badger.Txn) error {
+        offsetID, err = getInt64(txn, getCommunityOffsetKey(teamID))
+        return err
+    })
+    return
+}
+
+// SetOffset saves the offset if it is newer than the saved one
+func (r *repoCommunity) SetOffset(teamID, offsetID int64) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        key := getCommunityOffsetKey(teamID)
+        current, err := getInt64(txn, key)
+        if err != nil || offsetID <= current {
+            return err
+        }
+        return setInt64(txn, key, offsetID)
+    })
+}
+
+// AdvanceOffset saves the offset only if it directly follows the saved one, hence the offset never skips the
+// updates which have not been received yet.
+func (r *repoCommunity) AdvanceOffset(teamID, offsetID int64) (advanced bool, err error) {
+    err = badgerUpdate(func(txn *badger.Txn) error {
+        key := getCommunityOffsetKey(teamID)
+        current, err := getInt64(txn, key)
+        if err != nil || offsetID != current+1 {
+            return err
+        }
+        advanced = true
+        return setInt64(txn, key, offsetID)
+    })
+    return
+}
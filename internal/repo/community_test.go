@@ -0,0 +1,107 @@
+package repo_test
+
+import (
+    "testing"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/rony/tools"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func TestCommunity(t *testing.T) {
+    Convey("Community", t, func(c C) {
+        teamID := tools.RandomInt64(0)
+        userID := tools.RandomInt64(0)
+        peerID := tools.RandomInt64(0)
+
+        Convey("Pending Messages", func(c C) {
+            err := repo.Community.SavePending(teamID, &msg.CommunitySendMessage{SenderMsgID: 10, Body: "Hi"})
+            c.So(err, ShouldBeNil)
+            err = repo.Community.SavePending(teamID, &msg.CommunitySendMessage{SenderMsgID: 11, Body: "Bye"})
+            c.So(err, ShouldBeNil)
+            pending, err := repo.Community.GetPending(teamID)
+            c.So(err, ShouldBeNil)
+            c.So(pending, ShouldHaveLength, 2)
+
+            found, err := repo.Community.SaveMessage(userID, &msg.UpdateCommunityMessage{
+                TeamID: teamID, SenderID: userID, ReceiverID: peerID, SenderMsgID: 10, GlobalMsgID: 1, Body: "Hi",
+            })
+            c.So(err, ShouldBeNil)
+            c.So(found, ShouldBeTrue)
+            pending, err = repo.Community.GetPending(teamID)
+            c.So(err, ShouldBeNil)
+            c.So(pending, ShouldHaveLength, 1)
+            c.So(pending[0].SenderMsgID, ShouldEqual, 11)
+
+            // Messages of the peer never match our pending messages
+            found, err = repo.Community.SaveMessage(userID, &msg.UpdateCommunityMessage{
+                TeamID: teamID, SenderID: peerID, ReceiverID: userID, SenderMsgID: 11, GlobalMsgID: 2,
+            })
+            c.So(err, ShouldBeNil)
+            c.So(found, ShouldBeFalse)
+        })
+        Convey("Messages", func(c C) {
+            for i := uint64(1); i <= 10; i++ {
+                senderID, receiverID := userID, peerID
+                if i%2 == 0 {
+                    senderID, receiverID = peerID, userID
+                }
+                _, err := repo.Community.SaveMessage(userID, &msg.UpdateCommunityMessage{
+                    TeamID: teamID, SenderID: senderID, ReceiverID: receiverID, GlobalMsgID: i,
+                })
+                c.So(err, ShouldBeNil)
+            }
+            messages, err := repo.Community.GetMessages(teamID, peerID, 0, 3)
+            c.So(err, ShouldBeNil)
+            c.So(messages, ShouldHaveLength, 3)
+            c.So(messages[0].GlobalMsgID, ShouldEqual, 10)
+            c.So(messages[2].GlobalMsgID, ShouldEqual, 8)
+
+            messages, err = repo.Community.GetMessages(teamID, peerID, 3, 10)
+            c.So(err, ShouldBeNil)
+            c.So(messages, ShouldHaveLength, 2)
+            c.So(messages[0].GlobalMsgID, ShouldEqual, 2)
+
+            messages, err = repo.Community.GetMessages(teamID, userID, 0, 10)
+            c.So(err, ShouldBeNil)
+            c.So(messages, ShouldBeEmpty)
+        })
+        Convey("Read Outbox And Offset", func(c C) {
+            updated, err := repo.Community.UpdateReadOutbox(teamID, peerID, 5)
+            c.So(err, ShouldBeNil)
+            c.So(updated, ShouldBeTrue)
+            updated, err = repo.Community.UpdateReadOutbox(teamID, peerID, 4)
+            c.So(err, ShouldBeNil)
+            c.So(updated, ShouldBeFalse)
+            readID, err := repo.Community.GetReadOutbox(teamID, peerID)
+            c.So(err, ShouldBeNil)
+            c.So(readID, ShouldEqual, 5)
+
+            c.So(repo.Community.SetOffset(teamID, 100), ShouldBeNil)
+            c.So(repo.Community.SetOffset(teamID, 50), ShouldBeNil)
+            offsetID, err := repo.Community.GetOffset(teamID)
+            c.So(err, ShouldBeNil)
+            c.So(offsetID, ShouldEqual, 100)
+
+            advanced, err := repo.Community.AdvanceOffset(teamID, 102)
+            c.So(err, ShouldBeNil)
+            c.So(advanced, ShouldBeFalse)
+            advanced, err = repo.Community.AdvanceOffset(teamID, 101)
+            c.So(err, ShouldBeNil)
+            c.So(advanced, ShouldBeTrue)
+            offsetID, err = repo.Community.GetOffset(teamID)
+            c.So(err, ShouldBeNil)
+            c.So(offsetID, ShouldEqual, 101)
+        })
+    })
+}
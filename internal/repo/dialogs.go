@@ -2,7 +2,9 @@ package repo
 
 import (
     "context"
+    "encoding/binary"
     "fmt"
+    "sort"
     "strings"
     "sync/atomic"
 
@@ -18,6 +20,7 @@ import (
 const (
     prefixDialogs       = "DLG"
     prefixPinnedDialogs = "PDLG"
+    prefixPinnedOrder   = "PDLGO"
     indexDialogs        = prefixDialogs
 )
 
@@ -59,6 +62,86 @@ func getPinnedDialogKey(teamID int64, peerID int64, peerType int32) []byte {
     return id
 }
 
+func getPinnedDialogPrefix(teamID int64) []byte {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(prefixPinnedDialogs)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, teamID)
+    id := tools.StrToByte(sb.String())
+    pools.ReleaseStringsBuilder(sb)
+    return id
+}
+
+func getPinnedOrderKey(teamID int64) []byte {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(prefixPinnedOrder)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, teamID)
+    id := tools.StrToByte(sb.String())
+    pools.ReleaseStringsBuilder(sb)
+    return id
+}
+
+// pinnedPeer returns the peer part of the pinned dialog key
+func pinnedPeer(peerID int64, peerType int32) string {
+    b := make([]byte, 12)
+    binary.BigEndian.PutUint64(b, uint64(peerID))
+    binary.BigEndian.PutUint32(b[8:], uint32(peerType))
+    return string(b)
+}
+
+// getPinnedOrder returns the order of the pinned dialogs of the team, the first peer is on top
+func getPinnedOrder(txn *badger.Txn, teamID int64) ([]*msg.Peer, error) {
+    item, err := txn.Get(getPinnedOrderKey(teamID))
+    switch err {
+    case nil:
+    case badger.ErrKeyNotFound:
+        return nil, nil
+    default:
+        return nil, err
+    }
+    var peers []*msg.Peer
+    err = item.Value(func(val []byte) error {
+        for len(val) >= 12 {
+            peers = append(peers, &msg.Peer{
+                ID:   int64(binary.BigEndian.Uint64(val)),
+                Type: int32(binary.BigEndian.Uint32(val[8:])),
+            })
+            val = val[12:]
+        }
+        return nil
+    })
+    return peers, err
+}
+
+func savePinnedOrder(txn *badger.Txn, teamID int64, peers []*msg.Peer) error {
+    b := make([]byte, 12*len(peers))
+    for idx, p := range peers {
+        binary.BigEndian.PutUint64(b[idx*12:], uint64(p.ID))
+        binary.BigEndian.PutUint32(b[idx*12+8:], uint32(p.Type))
+    }
+    return txn.SetEntry(badger.NewEntry(getPinnedOrderKey(teamID), b))
+}
+
+// updatePinnedOrder puts the newly pinned dialog on top and removes the unpinned dialog from the order
+func updatePinnedOrder(txn *badger.Txn, teamID, peerID int64, peerType int32, pinned bool) error {
+    peers, err := getPinnedOrder(txn, teamID)
+    if err != nil {
+        return err
+    }
+    newPeers := make([]*msg.Peer, 0, len(peers)+1)
+    if pinned {
+        newPeers = append(newPeers, &msg.Peer{ID: peerID, Type: peerType})
+    }
+    for _, p := range peers {
+        if p.ID == peerID && p.Type == peerType {
+            continue
+        }
+        newPeers = append(newPeers, p)
+    }
+    return savePinnedOrder(txn, teamID, newPeers)
+}
+
 func getDialogPeerFromIndexKey(key string) (int64, *msg.Peer) {
     parts := strings.Split(key, ".")
     if len(parts) != 4 {
@@ -228,11 +311,140 @@ func (r *repoDialogs) UpdatePinned(in *msg.UpdateDialogPinned) error {
         if err != nil {
             return err
         }
+        if dialog.Pinned != in.Pinned {
+            err = updatePinnedOrder(txn, in.TeamID, in.Peer.ID, in.Peer.Type, in.Pinned)
+            if err != nil {
+                return err
+            }
+        }
         dialog.Pinned = in.Pinned
         return saveDialog(txn, dialog)
     })
 }
 
+// ReorderPinned replaces the order of the pinned dialogs of the team, the dialogs in the list are pinned if they
+// are not already.
+func (r *repoDialogs) ReorderPinned(teamID int64, peers []*msg.Peer) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        return reorderPinned(txn, teamID, peers)
+    })
+}
+
+// ReplacePinned replaces the pinned dialogs of the team by the given list, the dialogs which are not in the list are
+// unpinned. It is used to roll back ReorderPinned and to save the pinned dialogs received from the server.
+func (r *repoDialogs) ReplacePinned(teamID int64, peers []*msg.Peer) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        keep := make(map[string]struct{}, len(peers))
+        for _, p := range peers {
+            keep[pinnedPeer(p.ID, p.Type)] = struct{}{}
+        }
+
+        // keys are PDLG.<teamID><peerID><peerType>
+        var unpinned []*msg.Peer
+        opts := badger.DefaultIteratorOptions
+        opts.Prefix = getPinnedDialogPrefix(teamID)
+        opts.PrefetchValues = false
+        it := txn.NewIterator(opts)
+        for it.Rewind(); it.ValidForPrefix(opts.Prefix); it.Next() {
+            key := it.Item().Key()[len(opts.Prefix):]
+            if len(key) != 12 {
+                continue
+            }
+            if _, ok := keep[string(key)]; !ok {
+                unpinned = append(unpinned, &msg.Peer{
+                    ID:   int64(binary.BigEndian.Uint64(key)),
+                    Type: int32(binary.BigEndian.Uint32(key[8:])),
+                })
+            }
+        }
+        it.Close()
+
+        for _, p := range unpinned {
+            dialog, err := getDialog(txn, teamID, p.ID, p.Type)
+            switch err {
+            case nil:
+            case badger.ErrKeyNotFound:
+                continue
+            default:
+                return err
+            }
+            dialog.Pinned = false
+            err = saveDialog(txn, dialog)
+            if err != nil {
+                return err
+            }
+        }
+        return reorderPinned(txn, teamID, peers)
+    })
+}
+
+func reorderPinned(txn *badger.Txn, teamID int64, peers []*msg.Peer) error {
+    for _, p := range peers {
+        dialog, err := getDialog(txn, teamID, p.ID, p.Type)
+        switch err {
+        case nil:
+        case badger.ErrKeyNotFound:
+            continue
+        default:
+            return err
+        }
+        if !dialog.Pinned {
+            dialog.Pinned = true
+            err = saveDialog(txn, dialog)
+            if err != nil {
+                return err
+            }
+        }
+    }
+    return savePinnedOrder(txn, teamID, peers)
+}
+
+// ApplyPinnedReorder applies the order received from the server. UpdateDialogPinnedReorder does not have the
+// team, so it is applied to the given team (i.e. the current team) if any of the peers is pinned there, otherwise
+// it is applied to the personal dialogs (team 0) if any of the peers is pinned there. If none of the peers is
+// pinned in either of them, it is applied to the given team.
+func (r *repoDialogs) ApplyPinnedReorder(teamID int64, peers []*msg.Peer) error {
+    if len(peers) == 0 {
+        return nil
+    }
+    return badgerUpdate(func(txn *badger.Txn) error {
+        if teamID != 0 {
+            pinned, err := hasPinned(txn, teamID, peers)
+            if err != nil {
+                return err
+            }
+            if !pinned {
+                pinned, err = hasPinned(txn, 0, peers)
+                if err != nil {
+                    return err
+                }
+                if pinned {
+                    teamID = 0
+                }
+            }
+        }
+        return reorderPinned(txn, teamID, peers)
+    })
+}
+
+// hasPinned returns true if any of the peers is pinned in the team
+func hasPinned(txn *badger.Txn, teamID int64, peers []*msg.Peer) (bool, error) {
+    pinnedPeers, err := getPinnedOrder(txn, teamID)
+    if err != nil {
+        return false, err
+    }
+    pinned := make(map[string]struct{}, len(pinnedPeers))
+    for _, p := range pinnedPeers {
+        pinned[pinnedPeer(p.ID, p.Type)] = struct{}{}
+    }
+    for _, p := range peers {
+        if _, ok := pinned[pinnedPeer(p.ID, p.Type)]; ok {
+            return true, nil
+        }
+    }
+    return false, nil
+}
+
 func (r *repoDialogs) UpdateCallStarted(in *msg.UpdatePhoneCallStarted) error {
     return badgerUpdate(func(txn *badger.Txn) error {
         dialog, err := getDialog(txn, in.TeamID, in.Peer.ID, in.Peer.Type)
@@ -301,6 +513,65 @@ func (r *repoDialogs) List(teamID int64, offset, limit int32) ([]*msg.Dialog, er
     return dialogs, nil
 }
 
+// ListUnblocked works like List but skips the dialogs of the blocked users, so the pages are not cut short
+// by the hidden dialogs.
+func (r *repoDialogs) ListUnblocked(teamID int64, offset, limit int32) ([]*msg.Dialog, error) {
+    dialogs := make([]*msg.Dialog, 0, limit)
+    err := badgerView(func(txn *badger.Txn) error {
+        return r.bunt.View(func(tx *buntdb.Tx) error {
+            return tx.Descend(indexDialogs, func(key, value string) bool {
+                tID, peer := getDialogPeerFromIndexKey(key)
+                if tID != teamID || peer == nil {
+                    return true
+                }
+                if peer.Type == int32(msg.PeerType_PeerUser) && isBlocked(txn, peer.ID) {
+                    return true
+                }
+                if offset--; offset >= 0 {
+                    return true
+                }
+                if limit--; limit < 0 {
+                    return false
+                }
+                dialog, err := getDialog(txn, teamID, peer.ID, peer.Type)
+                if err == nil && dialog != nil {
+                    dialogs = append(dialogs, dialog)
+                }
+                return true
+            })
+        })
+    })
+    if err != nil {
+        return nil, err
+    }
+    return dialogs, nil
+}
+
+// CountUnblockedDialogs returns the number of the dialogs of the team, excluding the dialogs of the blocked users
+func (r *repoDialogs) CountUnblockedDialogs(teamID int64) int32 {
+    cnt := r.CountDialogs(teamID)
+    _ = badgerView(func(txn *badger.Txn) error {
+        opts := badger.DefaultIteratorOptions
+        opts.Prefix = tools.StrToByte(fmt.Sprintf("%s.", prefixBlockedUsers))
+        it := txn.NewIterator(opts)
+        defer it.Close()
+        for it.Rewind(); it.ValidForPrefix(opts.Prefix); it.Next() {
+            bc := &msg.BlockedContact{}
+            err := it.Item().Value(func(val []byte) error {
+                return bc.Unmarshal(val)
+            })
+            if err != nil {
+                continue
+            }
+            if _, err = txn.Get(getDialogKey(teamID, bc.UserID, int32(msg.PeerType_PeerUser))); err == nil {
+                cnt--
+            }
+        }
+        return nil
+    })
+    return cnt
+}
+
 func (r *repoDialogs) CountDialogs(teamID int64) int32 {
     var cnt int32
     st := r.badger.NewStream()
@@ -313,27 +584,48 @@ func (r *repoDialogs) CountDialogs(teamID int64) int32 {
     return cnt
 }
 
-func (r *repoDialogs) GetPinnedDialogs() []*msg.Dialog {
+// GetPinnedDialogs returns the pinned dialogs of the team in their pinned order. Pinned dialogs which are not in
+// the order yet, are returned on top.
+func (r *repoDialogs) GetPinnedDialogs(teamID int64) []*msg.Dialog {
     dialogs := make([]*msg.Dialog, 0, 7)
     _ = badgerView(func(txn *badger.Txn) error {
         opts := badger.DefaultIteratorOptions
-        opts.Prefix = tools.StrToByte(prefixDialogs)
-        opts.Reverse = true
+        opts.Prefix = getPinnedDialogPrefix(teamID)
+        opts.PrefetchValues = false
         it := txn.NewIterator(opts)
         for it.Rewind(); it.ValidForPrefix(opts.Prefix); it.Next() {
-            dialog := &msg.Dialog{}
-            _ = it.Item().Value(func(val []byte) error {
-                err := dialog.Unmarshal(val)
-                if err != nil {
-                    return err
-                }
-                if dialog.Pinned {
-                    dialogs = append(dialogs, dialog)
-                }
-                return nil
-            })
+            // keys are PDLG.<teamID><peerID><peerType>
+            key := it.Item().Key()[len(opts.Prefix):]
+            if len(key) != 12 {
+                continue
+            }
+            dialog, err := getDialog(txn, teamID, int64(binary.BigEndian.Uint64(key)), int32(binary.BigEndian.Uint32(key[8:])))
+            if err == nil && dialog.Pinned {
+                dialogs = append(dialogs, dialog)
+            }
         }
         it.Close()
+
+        peers, err := getPinnedOrder(txn, teamID)
+        if err != nil {
+            return err
+        }
+        order := make(map[string]int, len(peers))
+        for idx, p := range peers {
+            order[pinnedPeer(p.ID, p.Type)] = idx + 1
+        }
+        sort.SliceStable(dialogs, func(i, j int) bool {
+            oi := order[pinnedPeer(dialogs[i].PeerID, dialogs[i].PeerType)]
+            oj := order[pinnedPeer(dialogs[j].PeerID, dialogs[j].PeerType)]
+            if oi == 0 || oj == 0 {
+                // dialogs which are not in the order, come first and sorted by their top message
+                if oi == oj {
+                    return dialogs[i].TopMessageID > dialogs[j].TopMessageID
+                }
+                return oi == 0
+            }
+            return oi < oj
+        })
         return nil
     })
 
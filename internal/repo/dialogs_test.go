@@ -0,0 +1,113 @@
+package repo_test
+
+import (
+    "testing"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/rony/tools"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func TestPinnedDialogs(t *testing.T) {
+    Convey("Pinned Dialogs", t, func(c C) {
+        teamID := tools.RandomInt64(0)
+        peers := make([]*msg.Peer, 0, 4)
+        for i := 0; i < 4; i++ {
+            p := &msg.Peer{ID: tools.RandomInt64(0), Type: int32(msg.PeerType_PeerUser)}
+            peers = append(peers, p)
+            err := repo.Dialogs.Save(&msg.Dialog{TeamID: teamID, PeerID: p.ID, PeerType: p.Type})
+            c.So(err, ShouldBeNil)
+        }
+
+        Convey("Pin And Unpin", func(c C) {
+            for _, p := range peers[:3] {
+                err := repo.Dialogs.UpdatePinned(&msg.UpdateDialogPinned{TeamID: teamID, Peer: p, Pinned: true})
+                c.So(err, ShouldBeNil)
+            }
+            dialogs := repo.Dialogs.GetPinnedDialogs(teamID)
+            c.So(dialogs, ShouldHaveLength, 3)
+            c.So(dialogs[0].PeerID, ShouldEqual, peers[2].ID)
+            c.So(dialogs[2].PeerID, ShouldEqual, peers[0].ID)
+
+            err := repo.Dialogs.UpdatePinned(&msg.UpdateDialogPinned{TeamID: teamID, Peer: peers[1], Pinned: false})
+            c.So(err, ShouldBeNil)
+            dialogs = repo.Dialogs.GetPinnedDialogs(teamID)
+            c.So(dialogs, ShouldHaveLength, 2)
+            c.So(dialogs[0].PeerID, ShouldEqual, peers[2].ID)
+            c.So(dialogs[1].PeerID, ShouldEqual, peers[0].ID)
+            c.So(repo.Dialogs.GetPinnedDialogs(teamID+1), ShouldBeEmpty)
+        })
+        Convey("Reorder", func(c C) {
+            err := repo.Dialogs.ReorderPinned(teamID, []*msg.Peer{peers[3], peers[1], peers[0]})
+            c.So(err, ShouldBeNil)
+            dialogs := repo.Dialogs.GetPinnedDialogs(teamID)
+            c.So(dialogs, ShouldHaveLength, 3)
+            c.So(dialogs[0].PeerID, ShouldEqual, peers[3].ID)
+            c.So(dialogs[1].PeerID, ShouldEqual, peers[1].ID)
+            c.So(dialogs[2].PeerID, ShouldEqual, peers[0].ID)
+
+            // The update does not have the team, it must be applied to the current team which has the peers pinned
+            err = repo.Dialogs.ApplyPinnedReorder(teamID, []*msg.Peer{peers[0], peers[3], peers[1]})
+            c.So(err, ShouldBeNil)
+            dialogs = repo.Dialogs.GetPinnedDialogs(teamID)
+            c.So(dialogs, ShouldHaveLength, 3)
+            c.So(dialogs[0].PeerID, ShouldEqual, peers[0].ID)
+            c.So(dialogs[1].PeerID, ShouldEqual, peers[3].ID)
+            c.So(dialogs[2].PeerID, ShouldEqual, peers[1].ID)
+        })
+        Convey("Restore", func(c C) {
+            err := repo.Dialogs.ReorderPinned(teamID, []*msg.Peer{peers[1], peers[0]})
+            c.So(err, ShouldBeNil)
+            err = repo.Dialogs.ReorderPinned(teamID, []*msg.Peer{peers[2], peers[0], peers[1]})
+            c.So(err, ShouldBeNil)
+
+            // the server rejected the new order, peers[2] must be unpinned again
+            err = repo.Dialogs.ReplacePinned(teamID, []*msg.Peer{peers[1], peers[0]})
+            c.So(err, ShouldBeNil)
+            dialogs := repo.Dialogs.GetPinnedDialogs(teamID)
+            c.So(dialogs, ShouldHaveLength, 2)
+            c.So(dialogs[0].PeerID, ShouldEqual, peers[1].ID)
+            c.So(dialogs[1].PeerID, ShouldEqual, peers[0].ID)
+            d, err := repo.Dialogs.Get(teamID, peers[2].ID, peers[2].Type)
+            c.So(err, ShouldBeNil)
+            c.So(d.Pinned, ShouldBeFalse)
+        })
+        Convey("Reorder Of Two Teams", func(c C) {
+            // peers[0] and peers[1] are pinned in the personal dialogs too
+            for _, p := range peers[:2] {
+                err := repo.Dialogs.Save(&msg.Dialog{PeerID: p.ID, PeerType: p.Type})
+                c.So(err, ShouldBeNil)
+            }
+            err := repo.Dialogs.ReorderPinned(0, []*msg.Peer{peers[0], peers[1]})
+            c.So(err, ShouldBeNil)
+            err = repo.Dialogs.ReorderPinned(teamID, []*msg.Peer{peers[0], peers[1]})
+            c.So(err, ShouldBeNil)
+
+            // both teams have the peers pinned, the current team is preferred
+            err = repo.Dialogs.ApplyPinnedReorder(teamID, []*msg.Peer{peers[1], peers[0]})
+            c.So(err, ShouldBeNil)
+            dialogs := repo.Dialogs.GetPinnedDialogs(teamID)
+            c.So(dialogs, ShouldHaveLength, 2)
+            c.So(dialogs[0].PeerID, ShouldEqual, peers[1].ID)
+            dialogs = repo.Dialogs.GetPinnedDialogs(0)
+            c.So(dialogs[0].PeerID, ShouldEqual, peers[0].ID)
+
+            // the current team has none of the peers pinned, so the personal dialogs are reordered
+            err = repo.Dialogs.ApplyPinnedReorder(teamID+1, []*msg.Peer{peers[1], peers[0]})
+            c.So(err, ShouldBeNil)
+            dialogs = repo.Dialogs.GetPinnedDialogs(0)
+            c.So(dialogs[0].PeerID, ShouldEqual, peers[1].ID)
+            c.So(repo.Dialogs.GetPinnedDialogs(teamID+1), ShouldBeEmpty)
+        })
+    })
+}
@@ -2,6 +2,8 @@ package repo
 
 import (
     "context"
+    "encoding/binary"
+    "encoding/json"
     "fmt"
     "mime"
     "os"
@@ -27,10 +29,32 @@ import (
 */
 
 const (
-    prefixFiles         = "FILES"
-    prefixFilesRequests = "FILES_REQ"
+    prefixFiles            = "FILES"
+    prefixFilesRequests    = "FILES_REQ"
+    prefixFilesPaused      = "FILES_PAUSED"
+    prefixFilesIntegrity   = "FILES_INTEGRITY"
+    prefixFilesPartSums    = "FILES_PSUM"
+    prefixFilesAuto        = "FILES_AUTO"
+    prefixFilesUploadParts = "FILES_UPARTS"
 )
 
+type FileIntegrityStatus int32
+
+const (
+    FileIntegrityUnknown FileIntegrityStatus = iota
+    FileIntegrityVerified
+    FileIntegrityCorrupted
+)
+
+// FileIntegrity holds the known size and sha256 of a file and the result of the last verification of
+// the downloaded file against them.
+type FileIntegrity struct {
+    Size       int64               `json:"Size"`
+    Sha256     []byte              `json:"Sha256"`
+    Status     FileIntegrityStatus `json:"Status"`
+    VerifiedOn int64               `json:"VerifiedOn"`
+}
+
 type repoFiles struct {
     *repository
 }
@@ -39,6 +63,33 @@ func getFileKey(clusterID int32, fileID int64, accessHash uint64) []byte {
     return tools.StrToByte(fmt.Sprintf("%s.%012d.%021d.%021d", prefixFiles, clusterID, fileID, accessHash))
 }
 
+func getFileIntegrityKey(clusterID int32, fileID int64, accessHash uint64) []byte {
+    return tools.StrToByte(fmt.Sprintf("%s.%012d.%021d.%021d", prefixFilesIntegrity, clusterID, fileID, accessHash))
+}
+
+func getFileIntegrity(txn *badger.Txn, clusterID int32, fileID int64, accessHash uint64) (*FileIntegrity, error) {
+    fi := &FileIntegrity{}
+    item, err := txn.Get(getFileIntegrityKey(clusterID, fileID, accessHash))
+    if err != nil {
+        return nil, err
+    }
+    err = item.Value(func(val []byte) error {
+        return json.Unmarshal(val, fi)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return fi, nil
+}
+
+func saveFileIntegrity(txn *badger.Txn, clusterID int32, fileID int64, accessHash uint64, fi *FileIntegrity) error {
+    fiBytes, _ := json.Marshal(fi)
+    return txn.SetEntry(badger.NewEntry(
+        getFileIntegrityKey(clusterID, fileID, accessHash),
+        fiBytes,
+    ))
+}
+
 func getFile(txn *badger.Txn, clusterID int32, fileID int64, accessHash uint64) (*msg.ClientFile, error) {
     file := &msg.ClientFile{}
     item, err := txn.Get(getFileKey(clusterID, fileID, accessHash))
@@ -425,6 +476,49 @@ func (r *repoFiles) Delete(clusterID int32, fileID int64, accessHash uint64) err
     })
 }
 
+// GetIntegrity returns the integrity info of the file, if nothing has been saved for the file yet an empty
+// FileIntegrity will be returned.
+func (r *repoFiles) GetIntegrity(clusterID int32, fileID int64, accessHash uint64) *FileIntegrity {
+    var fi *FileIntegrity
+    _ = badgerView(func(txn *badger.Txn) (err error) {
+        fi, err = getFileIntegrity(txn, clusterID, fileID, accessHash)
+        return
+    })
+    if fi == nil {
+        fi = &FileIntegrity{}
+    }
+    return fi
+}
+
+// SaveIntegrityResult saves the result of the verification of the file, the known sha256 is kept untouched.
+func (r *repoFiles) SaveIntegrityResult(clusterID int32, fileID int64, accessHash uint64, size int64, status FileIntegrityStatus) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        fi, err := getFileIntegrity(txn, clusterID, fileID, accessHash)
+        if err != nil {
+            fi = &FileIntegrity{}
+        }
+        fi.Size = size
+        fi.Status = status
+        fi.VerifiedOn = domain.Now().Unix()
+        return saveFileIntegrity(txn, clusterID, fileID, accessHash, fi)
+    })
+}
+
+// SaveSha256 saves the known sha256 of the file, which will be used to verify the file after download.
+func (r *repoFiles) SaveSha256(clusterID int32, fileID int64, accessHash uint64, sha256 []byte) error {
+    if len(sha256) == 0 {
+        return nil
+    }
+    return badgerUpdate(func(txn *badger.Txn) error {
+        fi, err := getFileIntegrity(txn, clusterID, fileID, accessHash)
+        if err != nil {
+            fi = &FileIntegrity{}
+        }
+        fi.Sha256 = sha256
+        return saveFileIntegrity(txn, clusterID, fileID, accessHash, fi)
+    })
+}
+
 func (r *repoFiles) GetCachedMedia(teamID int64) *msg.ClientCachedMediaInfo {
     userMediaInfo := make(map[int64]map[msg.ClientMediaType]int64, 128)
     groupMediaInfo := make(map[int64]map[msg.ClientMediaType]int64, 128)
@@ -703,10 +797,196 @@ func (r *repoFiles) SaveFileRequest(reqID string, req *msg.ClientFileRequest, ov
 
 func (r *repoFiles) DeleteFileRequest(reqID string) error {
     return badgerUpdate(func(txn *badger.Txn) error {
-        return txn.Delete(
+        err := txn.Delete(
             tools.StrToByte(fmt.Sprintf("%s.%s", prefixFilesRequests, reqID)),
         )
+        if err != nil {
+            return err
+        }
+        err = txn.Delete(
+            tools.StrToByte(fmt.Sprintf("%s.%s", prefixFilesPaused, reqID)),
+        )
+        if err != nil {
+            return err
+        }
+        err = txn.Delete(
+            tools.StrToByte(fmt.Sprintf("%s.%s", prefixFilesAuto, reqID)),
+        )
+        if err != nil {
+            return err
+        }
+        err = txn.Delete(
+            tools.StrToByte(fmt.Sprintf("%s.%s", prefixFilesUploadParts, reqID)),
+        )
+        if err != nil {
+            return err
+        }
+        return deletePartSums(txn, reqID)
+    })
+}
+
+// SaveUploadParts saves the sizes of the parts which have been cut from the file of the upload request, in
+// the order of their part index, hence the uploaded parts are kept when the request is prepared again.
+func (r *repoFiles) SaveUploadParts(reqID string, sizes []int32) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        b := make([]byte, 4*len(sizes))
+        for idx, size := range sizes {
+            binary.BigEndian.PutUint32(b[4*idx:], uint32(size))
+        }
+        return txn.Set(
+            tools.StrToByte(fmt.Sprintf("%s.%s", prefixFilesUploadParts, reqID)),
+            b,
+        )
+    })
+}
+
+// GetUploadParts returns the sizes of the parts which have been saved by SaveUploadParts.
+func (r *repoFiles) GetUploadParts(reqID string) []int32 {
+    var sizes []int32
+    _ = badgerView(func(txn *badger.Txn) error {
+        item, err := txn.Get(tools.StrToByte(fmt.Sprintf("%s.%s", prefixFilesUploadParts, reqID)))
+        if err != nil {
+            return err
+        }
+        return item.Value(func(val []byte) error {
+            for idx := 0; idx+4 <= len(val); idx += 4 {
+                sizes = append(sizes, int32(binary.BigEndian.Uint32(val[idx:])))
+            }
+            return nil
+        })
+    })
+    return sizes
+}
+
+func getPartSumPrefix(reqID string) []byte {
+    return tools.StrToByte(fmt.Sprintf("%s.%s.", prefixFilesPartSums, reqID))
+}
+
+func getPartSumKey(reqID string, partIndex int32) []byte {
+    return tools.StrToByte(fmt.Sprintf("%s.%s.%010d", prefixFilesPartSums, reqID, partIndex))
+}
+
+func deletePartSums(txn *badger.Txn, reqID string) error {
+    opts := badger.DefaultIteratorOptions
+    opts.Prefix = getPartSumPrefix(reqID)
+    opts.PrefetchValues = false
+    it := txn.NewIterator(opts)
+    var keys [][]byte
+    for it.Rewind(); it.Valid(); it.Next() {
+        keys = append(keys, it.Item().KeyCopy(nil))
+    }
+    it.Close()
+    for _, key := range keys {
+        err := txn.Delete(key)
+        if err != nil {
+            return err
+        }
+    }
+    return nil
+}
+
+// SavePartSum saves the checksum of the part of the download request, as it has been written to the disk.
+// It is used to find the parts which have been damaged on the disk, not the ones corrupted on the network.
+func (r *repoFiles) SavePartSum(reqID string, partIndex int32, sum uint32) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        b := make([]byte, 4)
+        binary.BigEndian.PutUint32(b, sum)
+        return txn.Set(getPartSumKey(reqID, partIndex), b)
+    })
+}
+
+// GetPartSums returns the saved checksums of the parts of the download request, keyed by the part index.
+func (r *repoFiles) GetPartSums(reqID string) map[int32]uint32 {
+    sums := make(map[int32]uint32)
+    _ = badgerView(func(txn *badger.Txn) error {
+        prefix := getPartSumPrefix(reqID)
+        opts := badger.DefaultIteratorOptions
+        opts.Prefix = prefix
+        it := txn.NewIterator(opts)
+        defer it.Close()
+        for it.Rewind(); it.Valid(); it.Next() {
+            var partIndex int32
+            _, err := fmt.Sscanf(string(it.Item().Key()[len(prefix):]), "%d", &partIndex)
+            if err != nil {
+                continue
+            }
+            _ = it.Item().Value(func(val []byte) error {
+                if len(val) == 4 {
+                    sums[partIndex] = binary.BigEndian.Uint32(val)
+                }
+                return nil
+            })
+        }
+        return nil
+    })
+    return sums
+}
+
+// PauseFileRequest marks the file request as paused and keeps its priority, which will be used when
+// the request is resumed.
+func (r *repoFiles) PauseFileRequest(reqID string, priority uint8) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        return txn.Set(
+            tools.StrToByte(fmt.Sprintf("%s.%s", prefixFilesPaused, reqID)),
+            []byte{priority},
+        )
+    })
+}
+
+func (r *repoFiles) UnpauseFileRequest(reqID string) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        return txn.Delete(
+            tools.StrToByte(fmt.Sprintf("%s.%s", prefixFilesPaused, reqID)),
+        )
+    })
+}
+
+// GetPausedFileRequest returns the priority of the file request and true if the request is paused
+func (r *repoFiles) GetPausedFileRequest(reqID string) (priority uint8, paused bool) {
+    _ = badgerView(func(txn *badger.Txn) error {
+        item, err := txn.Get(
+            tools.StrToByte(fmt.Sprintf("%s.%s", prefixFilesPaused, reqID)),
+        )
+        if err != nil {
+            return err
+        }
+        paused = true
+        return item.Value(func(val []byte) error {
+            if len(val) > 0 {
+                priority = val[0]
+            }
+            return nil
+        })
+    })
+    return
+}
+
+// MarkAutoFileRequest marks the file request as an auto download, which has not been requested by the user.
+func (r *repoFiles) MarkAutoFileRequest(reqID string) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        return txn.Set(
+            tools.StrToByte(fmt.Sprintf("%s.%s", prefixFilesAuto, reqID)),
+            []byte{1},
+        )
+    })
+}
+
+func (r *repoFiles) UnmarkAutoFileRequest(reqID string) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        return txn.Delete(
+            tools.StrToByte(fmt.Sprintf("%s.%s", prefixFilesAuto, reqID)),
+        )
+    })
+}
+
+func (r *repoFiles) IsAutoFileRequest(reqID string) bool {
+    err := badgerView(func(txn *badger.Txn) error {
+        _, err := txn.Get(
+            tools.StrToByte(fmt.Sprintf("%s.%s", prefixFilesAuto, reqID)),
+        )
+        return err
     })
+    return err == nil
 }
 
 func (r *repoFiles) GetFileRequest(reqID string) (*msg.ClientFileRequest, error) {
@@ -0,0 +1,42 @@
+package repo_test
+
+import (
+    "testing"
+
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func TestFileIntegrity(t *testing.T) {
+    Convey("Testing File Integrity", t, func(c C) {
+        clusterID := int32(domain.RandomInt64(100))
+        fileID := domain.RandomInt63()
+        accessHash := domain.RandomUint64()
+        Convey("Unknown File", func(c C) {
+            fi := repo.Files.GetIntegrity(clusterID, fileID, accessHash)
+            c.So(fi.Status, ShouldEqual, repo.FileIntegrityUnknown)
+            c.So(fi.Sha256, ShouldBeEmpty)
+        })
+        Convey("Save Result Keeps Sha256", func(c C) {
+            sha256 := []byte("0123456789abcdef0123456789abcdef")
+            err := repo.Files.SaveSha256(clusterID, fileID, accessHash, sha256)
+            c.So(err, ShouldBeNil)
+            err = repo.Files.SaveIntegrityResult(clusterID, fileID, accessHash, 1024, repo.FileIntegrityCorrupted)
+            c.So(err, ShouldBeNil)
+            fi := repo.Files.GetIntegrity(clusterID, fileID, accessHash)
+            c.So(fi.Status, ShouldEqual, repo.FileIntegrityCorrupted)
+            c.So(fi.Size, ShouldEqual, 1024)
+            c.So(fi.Sha256, ShouldResemble, sha256)
+        })
+    })
+}
@@ -20,6 +20,7 @@ const (
     prefixGroupsFull         = "GRP_F"
     prefixGroupsParticipants = "GRP_P"
     prefixGroupsPhotoGallery = "GRP_PHG"
+    prefixGroupsMembership   = "GRP_PM"
 )
 
 type repoGroups struct {
@@ -87,6 +88,18 @@ func getGroupParticipantKey(groupID, memberID int64) []byte {
     return id
 }
 
+func getGroupMembershipKey(groupID, userID int64) []byte {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(prefixGroupsMembership)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, groupID)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, userID)
+    id := tools.StrToByte(sb.String())
+    pools.ReleaseStringsBuilder(sb)
+    return id
+}
+
 func getGroupPhotoGalleryKey(groupID, photoID int64) []byte {
     sb := pools.AcquireStringsBuilder()
     sb.WriteString(prefixGroupsPhotoGallery)
@@ -247,13 +260,25 @@ func (r *repoGroups) GetFull(groupID int64) (groupFull *msg.GroupFull, err error
     return
 }
 
+// AddParticipant adds the participant to the cached GroupFull, if the participant already exists it is replaced.
 func (r *repoGroups) AddParticipant(groupID int64, p *msg.GroupParticipant) error {
     return badgerUpdate(func(txn *badger.Txn) error {
         groupFull, err := getGroupFullByKey(txn, getGroupFullKey(groupID))
         if err != nil {
             return err
         }
-        groupFull.Participants = append(groupFull.Participants, p)
+
+        found := false
+        for idx := range groupFull.Participants {
+            if groupFull.Participants[idx].UserID == p.UserID {
+                groupFull.Participants[idx] = p
+                found = true
+                break
+            }
+        }
+        if !found {
+            groupFull.Participants = append(groupFull.Participants, p)
+        }
         groupFull.Group.Participants = int32(len(groupFull.Participants))
 
         err = saveGroupFull(txn, groupFull)
@@ -265,24 +290,25 @@ func (r *repoGroups) AddParticipant(groupID int64, p *msg.GroupParticipant) erro
     })
 }
 
-func (r *repoGroups) RemoveParticipant(groupID int64, UserIDs ...int64) error {
+// RemoveParticipant removes the participants from the cached GroupFull
+func (r *repoGroups) RemoveParticipant(groupID int64, userIDs ...int64) error {
     return badgerUpdate(func(txn *badger.Txn) error {
         groupFull, err := getGroupFullByKey(txn, getGroupFullKey(groupID))
         if err != nil {
             return err
         }
-        pm := make(map[int64]*msg.GroupParticipant, len(groupFull.Participants))
-        for _, p := range groupFull.Participants {
-            pm[p.UserID] = p
-        }
-        for _, userID := range UserIDs {
-            delete(pm, userID)
-        }
 
-        groupFull.Participants = groupFull.Participants[:0]
-        for _, p := range pm {
-            groupFull.Participants = append(groupFull.Participants, p)
+        removed := make(map[int64]struct{}, len(userIDs))
+        for _, userID := range userIDs {
+            removed[userID] = struct{}{}
+        }
+        participants := groupFull.Participants[:0]
+        for _, p := range groupFull.Participants {
+            if _, ok := removed[p.UserID]; !ok {
+                participants = append(participants, p)
+            }
         }
+        groupFull.Participants = participants
         groupFull.Group.Participants = int32(len(groupFull.Participants))
         err = saveGroupFull(txn, groupFull)
         if err != nil {
@@ -293,6 +319,72 @@ func (r *repoGroups) RemoveParticipant(groupID int64, UserIDs ...int64) error {
     })
 }
 
+// UpdateParticipantsCount changes the number of the participants of the group when the user joins or leaves it.
+// It is used when the GroupFull is not cached, hence the participants could not be patched. The last membership
+// of the user is kept, so the replayed updates do not change the number twice.
+func (r *repoGroups) UpdateParticipantsCount(groupID, userID int64, member bool) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        group, err := getGroupByKey(txn, getGroupKey(groupID))
+        if err != nil {
+            return err
+        }
+
+        membershipKey := getGroupMembershipKey(groupID, userID)
+        item, err := txn.Get(membershipKey)
+        switch err {
+        case nil:
+            wasMember := false
+            _ = item.Value(func(val []byte) error {
+                wasMember = len(val) > 0 && val[0] == 1
+                return nil
+            })
+            if wasMember == member {
+                return nil
+            }
+        case badger.ErrKeyNotFound:
+        default:
+            return err
+        }
+
+        var v byte
+        if member {
+            v = 1
+            group.Participants++
+        } else if group.Participants > 0 {
+            group.Participants--
+        }
+        err = txn.SetEntry(badger.NewEntry(membershipKey, []byte{v}))
+        if err != nil {
+            return err
+        }
+        return saveGroup(txn, group)
+    })
+}
+
+// SetParticipation sets or clears the NonParticipant flag of the group, the flag is set when the current user is
+// not a member of the group anymore.
+func (r *repoGroups) SetParticipation(groupID int64, participant bool) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        group, err := getGroupByKey(txn, getGroupKey(groupID))
+        if err != nil {
+            return err
+        }
+
+        flags := group.Flags[:0]
+        for _, f := range group.Flags {
+            if f != msg.GroupFlags_GroupFlagsNonParticipant {
+                flags = append(flags, f)
+            }
+        }
+        if !participant {
+            flags = append(flags, msg.GroupFlags_GroupFlagsNonParticipant)
+        }
+        group.Flags = flags
+
+        return saveGroup(txn, group)
+    })
+}
+
 func (r *repoGroups) Delete(groupID int64) error {
     return badgerUpdate(func(txn *badger.Txn) error {
         err := txn.Delete(getGroupKey(groupID))
@@ -0,0 +1,152 @@
+package repo
+
+import (
+    "encoding/json"
+    "time"
+
+    "github.com/dgraph-io/badger/v2"
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/z"
+    "github.com/ronaksoft/rony/pools"
+    "github.com/ronaksoft/rony/tools"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+const (
+    prefixGroupsReadStats = "GRP_RS"
+)
+
+// ReadHistoryStats is the cached result of GroupsGetReadHistoryStats. MaxIDs keeps the id of the last message
+// which has been read by each member.
+type ReadHistoryStats struct {
+    GroupID  int64           `json:"GroupID"`
+    MaxIDs   map[int64]int64 `json:"MaxIDs"`
+    CachedOn int64           `json:"CachedOn"`
+}
+
+func getGroupReadStatsKey(groupID int64) []byte {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(prefixGroupsReadStats)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, groupID)
+    id := tools.StrToByte(sb.String())
+    pools.ReleaseStringsBuilder(sb)
+    return id
+}
+
+func (r *repoGroups) SaveReadHistoryStats(groupID int64, stats ...*msg.ReadHistoryStat) error {
+    rs := &ReadHistoryStats{
+        GroupID:  groupID,
+        MaxIDs:   make(map[int64]int64, len(stats)),
+        CachedOn: domain.Now().Unix(),
+    }
+    for _, s := range stats {
+        rs.MaxIDs[s.UserID] = s.MessageID
+    }
+    b, err := json.Marshal(rs)
+    if err != nil {
+        return err
+    }
+    return badgerUpdate(func(txn *badger.Txn) error {
+        return txn.SetEntry(badger.NewEntry(getGroupReadStatsKey(groupID), b))
+    })
+}
+
+// GetReadHistoryStats returns the cached stats of the group, if the stats are not cached or they are older than
+// maxAge, domain.ErrNotFound is returned.
+func (r *repoGroups) GetReadHistoryStats(groupID int64, maxAge time.Duration) ([]*msg.ReadHistoryStat, error) {
+    rs := &ReadHistoryStats{}
+    err := badgerView(func(txn *badger.Txn) error {
+        item, err := txn.Get(getGroupReadStatsKey(groupID))
+        if err != nil {
+            return err
+        }
+        return item.Value(func(val []byte) error {
+            return json.Unmarshal(val, rs)
+        })
+    })
+    switch err {
+    case nil:
+    case badger.ErrKeyNotFound:
+        return nil, domain.ErrNotFound
+    default:
+        return nil, err
+    }
+    if domain.Now().Sub(time.Unix(rs.CachedOn, 0)) > maxAge {
+        return nil, domain.ErrNotFound
+    }
+
+    stats := make([]*msg.ReadHistoryStat, 0, len(rs.MaxIDs))
+    for userID, maxID := range rs.MaxIDs {
+        stats = append(stats, &msg.ReadHistoryStat{
+            UserID:    userID,
+            MessageID: maxID,
+        })
+    }
+    return stats, nil
+}
+
+// ReadBy returns the members who have read the message, ok is false if the stats are not cached or are stale.
+func (r *repoGroups) ReadBy(groupID, messageID int64, maxAge time.Duration) (userIDs []int64, ok bool) {
+    stats, err := r.GetReadHistoryStats(groupID, maxAge)
+    if err != nil {
+        return nil, false
+    }
+    userIDs = make([]int64, 0, len(stats))
+    for _, s := range stats {
+        if s.MessageID >= messageID {
+            userIDs = append(userIDs, s.UserID)
+        }
+    }
+    return userIDs, true
+}
+
+// UpdateReadHistoryStat moves the read max id of the member forward, if the stats of the group are cached. The
+// cache time is not changed, since the stats of the other members are not refreshed.
+func (r *repoGroups) UpdateReadHistoryStat(groupID, userID, maxID int64) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        item, err := txn.Get(getGroupReadStatsKey(groupID))
+        switch err {
+        case nil:
+        case badger.ErrKeyNotFound:
+            return nil
+        default:
+            return err
+        }
+        rs := &ReadHistoryStats{}
+        err = item.Value(func(val []byte) error {
+            return json.Unmarshal(val, rs)
+        })
+        if err != nil {
+            return err
+        }
+        if rs.MaxIDs[userID] >= maxID {
+            return nil
+        }
+        if rs.MaxIDs == nil {
+            rs.MaxIDs = make(map[int64]int64)
+        }
+        rs.MaxIDs[userID] = maxID
+        b, err := json.Marshal(rs)
+        if err != nil {
+            return err
+        }
+        return txn.SetEntry(badger.NewEntry(getGroupReadStatsKey(groupID), b))
+    })
+}
+
+// InvalidateReadHistoryStats removes the cached stats, hence the next GroupsGetReadHistoryStats goes to the server
+func (r *repoGroups) InvalidateReadHistoryStats(groupID int64) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        return txn.Delete(getGroupReadStatsKey(groupID))
+    })
+}
@@ -0,0 +1,73 @@
+package repo_test
+
+import (
+    "sort"
+    "testing"
+    "time"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/rony/tools"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func TestGroupReadHistoryStats(t *testing.T) {
+    Convey("Group Read History Stats", t, func(c C) {
+        groupID := tools.RandomInt64(0)
+        _, err := repo.Groups.GetReadHistoryStats(groupID, time.Minute)
+        c.So(err, ShouldEqual, domain.ErrNotFound)
+
+        err = repo.Groups.SaveReadHistoryStats(groupID,
+            &msg.ReadHistoryStat{UserID: 1, MessageID: 100},
+            &msg.ReadHistoryStat{UserID: 2, MessageID: 110},
+            &msg.ReadHistoryStat{UserID: 3, MessageID: 90},
+        )
+        c.So(err, ShouldBeNil)
+
+        stats, err := repo.Groups.GetReadHistoryStats(groupID, time.Minute)
+        c.So(err, ShouldBeNil)
+        c.So(stats, ShouldHaveLength, 3)
+
+        userIDs, ok := repo.Groups.ReadBy(groupID, 100, time.Minute)
+        c.So(ok, ShouldBeTrue)
+        sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+        c.So(userIDs, ShouldResemble, []int64{1, 2})
+
+        // stale stats are not returned
+        domain.TimeDelta = 2 * time.Minute
+        _, ok = repo.Groups.ReadBy(groupID, 100, time.Minute)
+        domain.TimeDelta = 0
+        c.So(ok, ShouldBeFalse)
+
+        // the read outbox updates move the member forward, older ones are ignored
+        c.So(repo.Groups.UpdateReadHistoryStat(groupID, 3, 120), ShouldBeNil)
+        c.So(repo.Groups.UpdateReadHistoryStat(groupID, 2, 105), ShouldBeNil)
+        c.So(repo.Groups.UpdateReadHistoryStat(groupID, 4, 100), ShouldBeNil)
+        userIDs, ok = repo.Groups.ReadBy(groupID, 110, time.Minute)
+        c.So(ok, ShouldBeTrue)
+        sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+        c.So(userIDs, ShouldResemble, []int64{2, 3})
+        userIDs, _ = repo.Groups.ReadBy(groupID, 100, time.Minute)
+        c.So(userIDs, ShouldHaveLength, 4)
+
+        // nothing is cached for the groups which their stats are not received
+        otherGroupID := tools.RandomInt64(0)
+        c.So(repo.Groups.UpdateReadHistoryStat(otherGroupID, 1, 100), ShouldBeNil)
+        _, ok = repo.Groups.ReadBy(otherGroupID, 100, time.Minute)
+        c.So(ok, ShouldBeFalse)
+
+        c.So(repo.Groups.InvalidateReadHistoryStats(groupID), ShouldBeNil)
+        _, err = repo.Groups.GetReadHistoryStats(groupID, time.Minute)
+        c.So(err, ShouldEqual, domain.ErrNotFound)
+    })
+}
@@ -0,0 +1,96 @@
+package repo_test
+
+import (
+    "testing"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/rony/tools"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func TestGroupParticipants(t *testing.T) {
+    Convey("Group Participants", t, func(c C) {
+        groupID := tools.RandomInt64(0)
+        group := &msg.Group{ID: groupID, Title: "Group", Participants: 3}
+
+        Convey("Without GroupFull", func(c C) {
+            c.So(repo.Groups.Save(group), ShouldBeNil)
+            c.So(repo.Groups.AddParticipant(groupID, &msg.GroupParticipant{UserID: 4}), ShouldNotBeNil)
+            // the replayed updates do not change the number twice
+            c.So(repo.Groups.UpdateParticipantsCount(groupID, 4, true), ShouldBeNil)
+            c.So(repo.Groups.UpdateParticipantsCount(groupID, 4, true), ShouldBeNil)
+            g, err := repo.Groups.Get(groupID)
+            c.So(err, ShouldBeNil)
+            c.So(g.Participants, ShouldEqual, 4)
+
+            c.So(repo.Groups.UpdateParticipantsCount(groupID, 4, false), ShouldBeNil)
+            c.So(repo.Groups.UpdateParticipantsCount(groupID, 4, false), ShouldBeNil)
+            c.So(repo.Groups.UpdateParticipantsCount(groupID, 2, false), ShouldBeNil)
+            c.So(repo.Groups.UpdateParticipantsCount(groupID, 2, false), ShouldBeNil)
+            g, err = repo.Groups.Get(groupID)
+            c.So(err, ShouldBeNil)
+            c.So(g.Participants, ShouldEqual, 2)
+
+            // the user could join again
+            c.So(repo.Groups.UpdateParticipantsCount(groupID, 4, true), ShouldBeNil)
+            c.So(repo.Groups.UpdateParticipantsCount(groupID, 1, false), ShouldBeNil)
+            c.So(repo.Groups.UpdateParticipantsCount(groupID, 3, false), ShouldBeNil)
+            c.So(repo.Groups.UpdateParticipantsCount(groupID, 5, false), ShouldBeNil)
+            g, err = repo.Groups.Get(groupID)
+            c.So(err, ShouldBeNil)
+            c.So(g.Participants, ShouldEqual, 0)
+        })
+        Convey("With GroupFull", func(c C) {
+            c.So(repo.Groups.SaveFull(&msg.GroupFull{
+                Group: group,
+                Participants: []*msg.GroupParticipant{
+                    {UserID: 1}, {UserID: 2}, {UserID: 3},
+                },
+            }), ShouldBeNil)
+            c.So(repo.Groups.Save(group), ShouldBeNil)
+
+            // the same participant could be added by the local handler and by the update
+            c.So(repo.Groups.AddParticipant(groupID, &msg.GroupParticipant{UserID: 4}), ShouldBeNil)
+            c.So(repo.Groups.AddParticipant(groupID, &msg.GroupParticipant{UserID: 4, FirstName: "Ehsan"}), ShouldBeNil)
+            c.So(repo.Groups.RemoveParticipant(groupID, 2), ShouldBeNil)
+            c.So(repo.Groups.RemoveParticipant(groupID, 2), ShouldBeNil)
+
+            gf, err := repo.Groups.GetFull(groupID)
+            c.So(err, ShouldBeNil)
+            c.So(gf.Participants, ShouldHaveLength, 3)
+            for idx, userID := range []int64{1, 3, 4} {
+                c.So(gf.Participants[idx].UserID, ShouldEqual, userID)
+            }
+            c.So(gf.Participants[2].FirstName, ShouldEqual, "Ehsan")
+            g, err := repo.Groups.Get(groupID)
+            c.So(err, ShouldBeNil)
+            c.So(g.Participants, ShouldEqual, 3)
+        })
+        Convey("Participation", func(c C) {
+            group.Flags = []msg.GroupFlags{msg.GroupFlags_GroupFlagsAdminOnly}
+            c.So(repo.Groups.Save(group), ShouldBeNil)
+            c.So(repo.Groups.SetParticipation(groupID, false), ShouldBeNil)
+            c.So(repo.Groups.SetParticipation(groupID, false), ShouldBeNil)
+            g, err := repo.Groups.Get(groupID)
+            c.So(err, ShouldBeNil)
+            c.So(g.Flags, ShouldResemble, []msg.GroupFlags{
+                msg.GroupFlags_GroupFlagsAdminOnly, msg.GroupFlags_GroupFlagsNonParticipant,
+            })
+
+            c.So(repo.Groups.SetParticipation(groupID, true), ShouldBeNil)
+            g, err = repo.Groups.Get(groupID)
+            c.So(err, ShouldBeNil)
+            c.So(g.Flags, ShouldResemble, []msg.GroupFlags{msg.GroupFlags_GroupFlagsAdminOnly})
+        })
+    })
+}
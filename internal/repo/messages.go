@@ -106,6 +106,12 @@ func getMessageByKey(txn *badger.Txn, msgKey []byte) (*msg.UserMessage, error) {
 }
 
 func saveMessage(txn *badger.Txn, message *msg.UserMessage) error {
+    if message.MediaType == msg.MediaType_MediaTypePoll {
+        err := mergeMessagePoll(txn, message)
+        if err != nil {
+            return err
+        }
+    }
     messageBytes, _ := message.Marshal()
     docType := msg.ClientMediaType_ClientMediaNone
 
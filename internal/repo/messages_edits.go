@@ -0,0 +1,275 @@
+package repo
+
+import (
+    "encoding/json"
+
+    "github.com/dgraph-io/badger/v2"
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/z"
+    "github.com/ronaksoft/rony/pools"
+    "github.com/ronaksoft/rony/tools"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+const (
+    prefixMessageEdits        = "MSG_EDIT"
+    prefixMessageEditRequests = "MSG_EDIT_REQ"
+)
+
+// PendingEdit keeps the message as it was confirmed by the server, and the local edits which have not been
+// confirmed yet, in the order they have been sent.
+type PendingEdit struct {
+    Original []byte       `json:"Original"`
+    Edits    []*LocalEdit `json:"Edits"`
+}
+
+// LocalEdit is one edit which is in flight. Message is the local message after the edit, and Content is what the
+// server returns in UpdateMessageEdited when it applies the edit.
+type LocalEdit struct {
+    RequestID uint64 `json:"RequestID"`
+    Content   string `json:"Content"`
+    Message   []byte `json:"Message"`
+}
+
+func getMessageEditKey(msgID int64) []byte {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(prefixMessageEdits)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, msgID)
+    id := tools.StrToByte(sb.String())
+    pools.ReleaseStringsBuilder(sb)
+    return id
+}
+
+func getMessageEditRequestKey(reqID uint64) []byte {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(prefixMessageEditRequests)
+    sb.WriteRune('.')
+    z.AppendStrUInt64(sb, reqID)
+    id := tools.StrToByte(sb.String())
+    pools.ReleaseStringsBuilder(sb)
+    return id
+}
+
+// editContent returns the parts of the message which are changed by MessagesEdit and MessagesEditMedia
+func editContent(um *msg.UserMessage) string {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(um.Body)
+    if um.MediaType == msg.MediaType_MediaTypeDocument {
+        doc := &msg.MediaDocument{}
+        if err := doc.Unmarshal(um.Media); err == nil {
+            sb.WriteRune(0)
+            sb.WriteString(doc.Caption)
+        }
+    }
+    content := sb.String()
+    pools.ReleaseStringsBuilder(sb)
+    return content
+}
+
+func getPendingEdit(txn *badger.Txn, msgID int64) (*PendingEdit, error) {
+    pe := &PendingEdit{}
+    item, err := txn.Get(getMessageEditKey(msgID))
+    if err != nil {
+        return nil, err
+    }
+    err = item.Value(func(val []byte) error {
+        return json.Unmarshal(val, pe)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return pe, nil
+}
+
+// savePendingEdit saves the pending edit, or deletes it if no edit is left
+func savePendingEdit(txn *badger.Txn, msgID int64, pe *PendingEdit) error {
+    if len(pe.Edits) == 0 {
+        return txn.Delete(getMessageEditKey(msgID))
+    }
+    b, err := json.Marshal(pe)
+    if err != nil {
+        return err
+    }
+    return txn.SetEntry(badger.NewEntry(getMessageEditKey(msgID), b))
+}
+
+func saveEditedMessage(txn *badger.Txn, b []byte) (*msg.UserMessage, error) {
+    um := &msg.UserMessage{}
+    err := um.Unmarshal(b)
+    if err != nil {
+        return nil, err
+    }
+    err = saveMessage(txn, um)
+    if err != nil {
+        return nil, err
+    }
+    return um, saveMessageMedia(txn, um)
+}
+
+// ApplyEdit applies fn on the message and marks the edit as pending, until it is confirmed by ConfirmEdit or it is
+// rolled back by RollbackEdit. The request of the edit is kept in the database, hence the edit can be rolled back
+// even if the client is restarted before the server answers.
+func (r *repoMessages) ApplyEdit(messageID int64, requestID uint64, fn func(um *msg.UserMessage)) (*msg.UserMessage, error) {
+    var um *msg.UserMessage
+    err := badgerUpdate(func(txn *badger.Txn) (err error) {
+        um, err = getMessageByID(txn, messageID)
+        if err != nil {
+            return err
+        }
+        pe, err := getPendingEdit(txn, messageID)
+        switch err {
+        case nil:
+        case badger.ErrKeyNotFound:
+            pe = &PendingEdit{}
+            pe.Original, err = um.Marshal()
+            if err != nil {
+                return err
+            }
+        default:
+            return err
+        }
+
+        fn(um)
+        um.EditedOn = domain.Now().Unix()
+        le := &LocalEdit{
+            RequestID: requestID,
+            Content:   editContent(um),
+        }
+        le.Message, err = um.Marshal()
+        if err != nil {
+            return err
+        }
+        pe.Edits = append(pe.Edits, le)
+        err = savePendingEdit(txn, messageID, pe)
+        if err != nil {
+            return err
+        }
+        err = setInt64(txn, getMessageEditRequestKey(requestID), messageID)
+        if err != nil {
+            return err
+        }
+        err = saveMessage(txn, um)
+        if err != nil {
+            return err
+        }
+        return saveMessageMedia(txn, um)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return um, nil
+}
+
+func (r *repoMessages) IsEditPending(messageID int64) bool {
+    err := badgerView(func(txn *badger.Txn) error {
+        _, err := txn.Get(getMessageEditKey(messageID))
+        return err
+    })
+    return err == nil
+}
+
+// ConfirmEdit must be called when the server sends an edit of the message. The edit is matched with our pending
+// edits by its content, the matched edit and the older ones are confirmed. Edits which are not matched are made by
+// the other devices, they become the state which we roll back to. It returns false if some of our edits are still
+// pending, hence the local message is newer than the received one and must be kept.
+func (r *repoMessages) ConfirmEdit(um *msg.UserMessage) (bool, error) {
+    done := true
+    err := badgerUpdate(func(txn *badger.Txn) (err error) {
+        pe, err := getPendingEdit(txn, um.ID)
+        switch err {
+        case nil:
+        case badger.ErrKeyNotFound:
+            return nil
+        default:
+            return err
+        }
+
+        content := editContent(um)
+        for idx, le := range pe.Edits {
+            if le.Content != content {
+                continue
+            }
+            for _, confirmed := range pe.Edits[:idx+1] {
+                err = txn.Delete(getMessageEditRequestKey(confirmed.RequestID))
+                if err != nil {
+                    return err
+                }
+            }
+            pe.Edits = pe.Edits[idx+1:]
+            break
+        }
+        pe.Original, err = um.Marshal()
+        if err != nil {
+            return err
+        }
+        done = len(pe.Edits) == 0
+        return savePendingEdit(txn, um.ID, pe)
+    })
+    return done, err
+}
+
+// RollbackEdit removes the edit of the request which has been rejected by the server. If it is the latest edit,
+// the message is restored to the previous pending edit, or to the last confirmed state if there is none. It
+// returns domain.ErrNotFound if the edit is not pending.
+func (r *repoMessages) RollbackEdit(requestID uint64) (*msg.UserMessage, error) {
+    var um *msg.UserMessage
+    err := badgerUpdate(func(txn *badger.Txn) (err error) {
+        reqKey := getMessageEditRequestKey(requestID)
+        messageID, err := getInt64(txn, reqKey)
+        if err != nil {
+            return err
+        }
+        if messageID == 0 {
+            return domain.ErrNotFound
+        }
+        err = txn.Delete(reqKey)
+        if err != nil {
+            return err
+        }
+        pe, err := getPendingEdit(txn, messageID)
+        switch err {
+        case nil:
+        case badger.ErrKeyNotFound:
+            return domain.ErrNotFound
+        default:
+            return err
+        }
+
+        for idx, le := range pe.Edits {
+            if le.RequestID != requestID {
+                continue
+            }
+            latest := idx == len(pe.Edits)-1
+            pe.Edits = append(pe.Edits[:idx], pe.Edits[idx+1:]...)
+            err = savePendingEdit(txn, messageID, pe)
+            if err != nil {
+                return err
+            }
+            if !latest {
+                um, err = getMessageByID(txn, messageID)
+                return err
+            }
+            if len(pe.Edits) > 0 {
+                um, err = saveEditedMessage(txn, pe.Edits[len(pe.Edits)-1].Message)
+            } else {
+                um, err = saveEditedMessage(txn, pe.Original)
+            }
+            return err
+        }
+        return domain.ErrNotFound
+    })
+    if err != nil {
+        return nil, err
+    }
+    return um, nil
+}
@@ -0,0 +1,163 @@
+package repo_test
+
+import (
+    "testing"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/rony/tools"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func TestMessageEdits(t *testing.T) {
+    Convey("Message Edits", t, func(c C) {
+        m := &msg.UserMessage{
+            TeamID:   0,
+            ID:       tools.RandomInt64(0),
+            PeerID:   tools.RandomInt64(0),
+            PeerType: int32(msg.PeerType_PeerUser),
+            Body:     "original",
+        }
+        err := repo.Messages.Save(m)
+        c.So(err, ShouldBeNil)
+
+        edit := func(body string) uint64 {
+            reqID := uint64(tools.RandomInt64(0))
+            um, err := repo.Messages.ApplyEdit(m.ID, reqID, func(um *msg.UserMessage) {
+                um.Body = body
+            })
+            c.So(err, ShouldBeNil)
+            c.So(um.Body, ShouldEqual, body)
+            c.So(um.EditedOn, ShouldNotBeZeroValue)
+            return reqID
+        }
+        serverEdit := func(body string) *msg.UserMessage {
+            return &msg.UserMessage{
+                TeamID:   m.TeamID,
+                ID:       m.ID,
+                PeerID:   m.PeerID,
+                PeerType: m.PeerType,
+                Body:     body,
+                EditedOn: 100,
+            }
+        }
+        body := func() string {
+            um, err := repo.Messages.Get(m.ID)
+            c.So(err, ShouldBeNil)
+            return um.Body
+        }
+
+        Convey("Confirm", func(c C) {
+            edit("first")
+            edit("second")
+            c.So(repo.Messages.IsEditPending(m.ID), ShouldBeTrue)
+
+            // the first edit is confirmed, but the second one is still pending
+            done, err := repo.Messages.ConfirmEdit(serverEdit("first"))
+            c.So(err, ShouldBeNil)
+            c.So(done, ShouldBeFalse)
+            done, err = repo.Messages.ConfirmEdit(serverEdit("second"))
+            c.So(err, ShouldBeNil)
+            c.So(done, ShouldBeTrue)
+            c.So(repo.Messages.IsEditPending(m.ID), ShouldBeFalse)
+            c.So(body(), ShouldEqual, "second")
+        })
+        Convey("Confirm Skipped Edit", func(c C) {
+            edit("first")
+            edit("second")
+
+            // the server has applied both edits, but we have received only the last one
+            done, err := repo.Messages.ConfirmEdit(serverEdit("second"))
+            c.So(err, ShouldBeNil)
+            c.So(done, ShouldBeTrue)
+            c.So(repo.Messages.IsEditPending(m.ID), ShouldBeFalse)
+        })
+        Convey("Edit From Other Device", func(c C) {
+            reqID := edit("first")
+
+            // the edit of the other device does not confirm our edit, but it is what we roll back to
+            done, err := repo.Messages.ConfirmEdit(serverEdit("other device"))
+            c.So(err, ShouldBeNil)
+            c.So(done, ShouldBeFalse)
+            c.So(repo.Messages.IsEditPending(m.ID), ShouldBeTrue)
+            c.So(body(), ShouldEqual, "first")
+
+            um, err := repo.Messages.RollbackEdit(reqID)
+            c.So(err, ShouldBeNil)
+            c.So(um.Body, ShouldEqual, "other device")
+            c.So(body(), ShouldEqual, "other device")
+            c.So(repo.Messages.IsEditPending(m.ID), ShouldBeFalse)
+        })
+        Convey("Rollback", func(c C) {
+            first := edit("first")
+            second := edit("second")
+
+            // the older edit is rejected, the newer one is still shown
+            um, err := repo.Messages.RollbackEdit(first)
+            c.So(err, ShouldBeNil)
+            c.So(um.Body, ShouldEqual, "second")
+            c.So(repo.Messages.IsEditPending(m.ID), ShouldBeTrue)
+
+            um, err = repo.Messages.RollbackEdit(second)
+            c.So(err, ShouldBeNil)
+            c.So(um.Body, ShouldEqual, "original")
+            c.So(repo.Messages.IsEditPending(m.ID), ShouldBeFalse)
+
+            um, err = repo.Messages.Get(m.ID)
+            c.So(err, ShouldBeNil)
+            c.So(um.Body, ShouldEqual, "original")
+            c.So(um.EditedOn, ShouldBeZeroValue)
+
+            _, err = repo.Messages.RollbackEdit(second)
+            c.So(err, ShouldEqual, domain.ErrNotFound)
+        })
+        Convey("Rollback Latest Edit", func(c C) {
+            edit("first")
+            second := edit("second")
+            um, err := repo.Messages.RollbackEdit(second)
+            c.So(err, ShouldBeNil)
+            c.So(um.Body, ShouldEqual, "first")
+            c.So(repo.Messages.IsEditPending(m.ID), ShouldBeTrue)
+        })
+    })
+}
+
+func TestPendingMessageCaption(t *testing.T) {
+    Convey("Pending Message Caption", t, func(c C) {
+        fileID := tools.RandomInt64(0)
+        pm, err := repo.PendingMessages.SaveClientMessageMedia(
+            0, 0, -fileID, tools.RandomInt64(0), fileID, fileID, 0,
+            &msg.ClientSendMessageMedia{
+                Peer:      &msg.InputPeer{ID: tools.RandomInt64(0), Type: msg.PeerType_PeerUser},
+                MediaType: msg.InputMediaType_InputMediaTypeUploadedDocument,
+                Caption:   "original",
+            }, nil,
+        )
+        c.So(err, ShouldBeNil)
+
+        _, err = repo.PendingMessages.UpdateCaption(pm.ID, "edited", nil)
+        c.So(err, ShouldBeNil)
+        pm, err = repo.PendingMessages.GetByID(pm.ID)
+        c.So(err, ShouldBeNil)
+        csmm := &msg.ClientSendMessageMedia{}
+        c.So(csmm.Unmarshal(pm.Media), ShouldBeNil)
+        c.So(csmm.Caption, ShouldEqual, "edited")
+        c.So(pm.Body, ShouldEqual, "edited")
+
+        // the upload is finished and the media request is queued
+        err = repo.PendingMessages.UpdateClientMessageMedia(pm, 10, pm.MediaType, nil)
+        c.So(err, ShouldBeNil)
+        _, err = repo.PendingMessages.UpdateCaption(pm.ID, "late", nil)
+        c.So(err, ShouldEqual, domain.ErrMessageIsPending)
+    })
+}
@@ -12,13 +12,15 @@ import (
 
 const (
     prefixMessageExtra = "MSG_EX"
+    prefixMessageHoles = "MSG_HOLES"
 )
 
 type MessagesExtraItem struct {
     PeerID   int64  `json:"PeerID"`
     PeerType int32  `json:"PeerType"`
     ScrollID int64  `json:"ScrollID"`
-    Holes    []byte `json:"Holes"`
+    // Holes is deprecated and only read for migration, the holes are stored by their own keys
+    Holes []byte `json:"Holes"`
 }
 
 type repoMessagesExtra struct {
@@ -38,6 +40,19 @@ func (r *repoMessagesExtra) getKey(teamID, peerID int64, peerType int32, cat msg
     return id
 }
 
+func (r *repoMessagesExtra) getHolesKey(teamID, peerID int64, peerType int32, cat msg.MediaCategory) []byte {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(prefixMessageHoles)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, teamID)
+    z.AppendStrInt64(sb, peerID)
+    z.AppendStrInt32(sb, peerType)
+    z.AppendStrInt32(sb, int32(cat))
+    id := tools.StrToByte(sb.String())
+    pools.ReleaseStringsBuilder(sb)
+    return id
+}
+
 func (r *repoMessagesExtra) get(teamID, peerID int64, peerType int32, cat msg.MediaCategory) *MessagesExtraItem {
     message := &MessagesExtraItem{}
     _ = badgerView(func(txn *badger.Txn) error {
@@ -77,20 +92,65 @@ func (r *repoMessagesExtra) GetScrollID(teamID, peerID int64, peerType int32, ca
     return m.ScrollID
 }
 
+// SaveHoles overwrites the holes, UpdateHoles must be used to modify the current holes
 func (r *repoMessagesExtra) SaveHoles(teamID, peerID int64, peerType int32, cat msg.MediaCategory, data []byte) {
-    key := r.getKey(teamID, peerID, peerType, cat)
-    m := r.get(teamID, peerID, peerType, cat)
-    if m == nil {
-        return
-    }
-    m.Holes = data
-    r.save(key, m)
+    _ = badgerUpdate(func(txn *badger.Txn) error {
+        return txn.SetEntry(badger.NewEntry(r.getHolesKey(teamID, peerID, peerType, cat), data))
+    })
+}
+
+// UpdateHoles reads the holes and writes the output of fn in one transaction. Hence concurrent updates do not
+// overwrite each other. fn might be called more than once if the transaction conflicts with another one.
+func (r *repoMessagesExtra) UpdateHoles(
+        teamID, peerID int64, peerType int32, cat msg.MediaCategory, fn func(holes []byte) ([]byte, error),
+) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        holes, err := r.getHoles(txn, teamID, peerID, peerType, cat)
+        if err != nil {
+            return err
+        }
+        holes, err = fn(holes)
+        if err != nil {
+            return err
+        }
+        return txn.SetEntry(badger.NewEntry(r.getHolesKey(teamID, peerID, peerType, cat), holes))
+    })
 }
 
 func (r *repoMessagesExtra) GetHoles(teamID, peerID int64, peerType int32, cat msg.MediaCategory) []byte {
-    m := r.get(teamID, peerID, peerType, cat)
-    if m == nil {
-        return nil
+    var holes []byte
+    _ = badgerView(func(txn *badger.Txn) (err error) {
+        holes, err = r.getHoles(txn, teamID, peerID, peerType, cat)
+        return
+    })
+    return holes
+}
+
+func (r *repoMessagesExtra) getHoles(txn *badger.Txn, teamID, peerID int64, peerType int32, cat msg.MediaCategory) ([]byte, error) {
+    item, err := txn.Get(r.getHolesKey(teamID, peerID, peerType, cat))
+    switch err {
+    case nil:
+        return item.ValueCopy(nil)
+    case badger.ErrKeyNotFound:
+    default:
+        return nil, err
+    }
+
+    // Holes of the older versions are stored in MessagesExtraItem
+    item, err = txn.Get(r.getKey(teamID, peerID, peerType, cat))
+    switch err {
+    case nil:
+    case badger.ErrKeyNotFound:
+        return nil, nil
+    default:
+        return nil, err
+    }
+    m := &MessagesExtraItem{}
+    err = item.Value(func(val []byte) error {
+        return json.Unmarshal(val, m)
+    })
+    if err != nil {
+        return nil, nil
     }
-    return m.Holes
+    return m.Holes, nil
 }
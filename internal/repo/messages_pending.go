@@ -214,6 +214,48 @@ func (r *repoMessagesPending) UpdateClientMessageMedia(
 
 }
 
+// UpdateCaption edits the caption of a media message which is being uploaded. The caption is sent to the server
+// by postUploadProcess when the upload is finished. If the message is not waiting for its upload, it returns
+// domain.ErrMessageIsPending, since its request has been already queued.
+func (r *repoMessagesPending) UpdateCaption(
+        msgID int64, caption string, entities []*msg.MessageEntity,
+) (*msg.ClientPendingMessage, error) {
+    var pm *msg.ClientPendingMessage
+    err := badgerUpdate(func(txn *badger.Txn) (err error) {
+        pm, err = getPendingMessageByID(txn, msgID)
+        if err != nil {
+            return err
+        }
+        if pm.MediaType != msg.InputMediaType_InputMediaTypeUploadedDocument {
+            return domain.ErrMessageIsPending
+        }
+        csmm := new(msg.ClientSendMessageMedia)
+        err = csmm.Unmarshal(pm.Media)
+        if err != nil {
+            return err
+        }
+        if csmm.FileTotalParts != 0 {
+            return domain.ErrMessageIsPending
+        }
+        csmm.Caption = caption
+        csmm.Entities = entities
+        pm.Media, _ = csmm.Marshal()
+        pm.Body = caption
+        pm.Entities = entities
+
+        bytes, _ := pm.Marshal()
+        err = txn.SetEntry(badger.NewEntry(getPendingMessageKey(pm.ID), bytes))
+        if err != nil {
+            return err
+        }
+        return txn.SetEntry(badger.NewEntry(getPendingMessageRandomKey(pm.RequestID), bytes))
+    })
+    if err != nil {
+        return nil, err
+    }
+    return pm, nil
+}
+
 func (r *repoMessagesPending) SaveMessageMedia(
         teamID int64, teamAccess uint64, msgID int64, senderID int64, msgMedia *msg.MessagesSendMedia,
 ) (*msg.ClientPendingMessage, error) {
@@ -461,6 +503,7 @@ const (
     _ClientSendMessageContactType      = -2
     _ClientSendMessageGeoLocationType  = -3
     _ClientSendMessageInputMessageType = -4
+    _ClientSendMessagePollType         = -5
 )
 
 func (r *repoMessagesPending) ToUserMessage(m *msg.ClientPendingMessage) *msg.UserMessage {
@@ -482,6 +525,16 @@ func (r *repoMessagesPending) ToUserMessage(m *msg.ClientPendingMessage) *msg.Us
         v.MessageType = _ClientSendMessageGeoLocationType
     case msg.InputMediaType_InputMediaTypeMessageDocument:
         v.MessageType = _ClientSendMessageInputMessageType
+    case msg.InputMediaType_InputMediaTypePoll:
+        // InputMediaType and MediaType values of the poll are not equal, also the poll is wrapped
+        v.MessageType = _ClientSendMessagePollType
+        v.MediaType = msg.MediaType_MediaTypePoll
+        x := &msg.InputMediaPoll{}
+        _ = x.Unmarshal(m.Media)
+        if x.Poll != nil {
+            v.Media, _ = x.Poll.Marshal()
+        }
+        return v
     }
     v.MediaType = msg.MediaType(m.MediaType)
     v.Media = m.Media
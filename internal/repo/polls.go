@@ -0,0 +1,198 @@
+package repo
+
+import (
+    "encoding/binary"
+
+    "github.com/dgraph-io/badger/v2"
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/z"
+    "github.com/ronaksoft/rony/pools"
+    "github.com/ronaksoft/rony/tools"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+const (
+    prefixPolls        = "POLL"
+    prefixPollMessages = "POLL_MSG"
+)
+
+type repoPolls struct {
+    *repository
+}
+
+func getPollKey(pollID int64) []byte {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(prefixPolls)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, pollID)
+    id := tools.StrToByte(sb.String())
+    pools.ReleaseStringsBuilder(sb)
+    return id
+}
+
+func getPollMessageKey(pollID int64) []byte {
+    sb := pools.AcquireStringsBuilder()
+    sb.WriteString(prefixPollMessages)
+    sb.WriteRune('.')
+    z.AppendStrInt64(sb, pollID)
+    id := tools.StrToByte(sb.String())
+    pools.ReleaseStringsBuilder(sb)
+    return id
+}
+
+// getPoll returns the cached state of the poll. The state is stored as UpdateMessagePoll since it holds both
+// the poll and its results.
+func getPoll(txn *badger.Txn, pollID int64) (*msg.UpdateMessagePoll, error) {
+    p := &msg.UpdateMessagePoll{}
+    item, err := txn.Get(getPollKey(pollID))
+    if err != nil {
+        return nil, err
+    }
+    err = item.Value(func(val []byte) error {
+        return p.Unmarshal(val)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return p, nil
+}
+
+func savePoll(txn *badger.Txn, p *msg.UpdateMessagePoll) error {
+    pollBytes, _ := p.Marshal()
+    return txn.SetEntry(badger.NewEntry(getPollKey(p.PollID), pollBytes))
+}
+
+// mergePoll merges the newer state of the poll into the older one. A closed poll never opens again, so we do
+// not lose the closed flag if the updates arrive out of order.
+func mergePoll(old, new *msg.MediaPoll) *msg.MediaPoll {
+    switch {
+    case old == nil:
+        return new
+    case new == nil:
+        return old
+    }
+    new.Closed = old.Closed || new.Closed
+    return new
+}
+
+// mergeMessagePoll is called before saving a poll message, it links the poll to the message and merges the
+// cached state of the poll, which might be received before the message itself.
+func mergeMessagePoll(txn *badger.Txn, message *msg.UserMessage) error {
+    poll := &msg.MediaPoll{}
+    if err := poll.Unmarshal(message.Media); err != nil || poll.ID == 0 {
+        return nil
+    }
+
+    p, err := getPoll(txn, poll.ID)
+    switch err {
+    case nil:
+        poll = mergePoll(poll, p.Poll)
+        message.Media, _ = poll.Marshal()
+    case badger.ErrKeyNotFound:
+    default:
+        return err
+    }
+
+    // Pending messages have negative ids, they are linked when the real message is saved
+    if message.ID <= 0 {
+        return nil
+    }
+    b := make([]byte, 8)
+    binary.BigEndian.PutUint64(b, uint64(message.ID))
+    return txn.SetEntry(badger.NewEntry(getPollMessageKey(poll.ID), b))
+}
+
+// Apply merges the update into the cached state of the poll and into the media of the message which holds
+// the poll. If the message has not been received yet, the state is merged when it is saved.
+// It returns the updated message or nil if there is no message for the poll.
+func (r *repoPolls) Apply(u *msg.UpdateMessagePoll) (*msg.UserMessage, error) {
+    var um *msg.UserMessage
+    err := badgerUpdate(func(txn *badger.Txn) error {
+        um = nil
+        p, err := getPoll(txn, u.PollID)
+        switch err {
+        case nil:
+        case badger.ErrKeyNotFound:
+            p = &msg.UpdateMessagePoll{PollID: u.PollID}
+        default:
+            return err
+        }
+        p.Poll = mergePoll(p.Poll, u.Poll)
+        if u.Results != nil {
+            p.Results = u.Results
+        }
+        err = savePoll(txn, p)
+        if err != nil {
+            return err
+        }
+
+        item, err := txn.Get(getPollMessageKey(u.PollID))
+        switch err {
+        case nil:
+        case badger.ErrKeyNotFound:
+            return nil
+        default:
+            return err
+        }
+        var msgID int64
+        err = item.Value(func(val []byte) error {
+            msgID = int64(binary.BigEndian.Uint64(val))
+            return nil
+        })
+        if err != nil {
+            return err
+        }
+        um, err = getMessageByID(txn, msgID)
+        switch err {
+        case nil:
+        case badger.ErrKeyNotFound:
+            return nil
+        default:
+            return err
+        }
+
+        // saveMessage merges the cached poll into the media
+        return saveMessage(txn, um)
+    })
+    if err != nil {
+        return nil, err
+    }
+    return um, nil
+}
+
+// Get returns the cached state of the poll, the returned UpdateMessagePoll has the poll and its latest results.
+func (r *repoPolls) Get(pollID int64) (*msg.UpdateMessagePoll, error) {
+    var p *msg.UpdateMessagePoll
+    err := badgerView(func(txn *badger.Txn) (err error) {
+        p, err = getPoll(txn, pollID)
+        return
+    })
+    if err != nil {
+        return nil, err
+    }
+    return p, nil
+}
+
+// GetMessageID returns the id of the message which holds the poll
+func (r *repoPolls) GetMessageID(pollID int64) (int64, error) {
+    var msgID int64
+    err := badgerView(func(txn *badger.Txn) error {
+        item, err := txn.Get(getPollMessageKey(pollID))
+        if err != nil {
+            return err
+        }
+        return item.Value(func(val []byte) error {
+            msgID = int64(binary.BigEndian.Uint64(val))
+            return nil
+        })
+    })
+    return msgID, err
+}
@@ -0,0 +1,109 @@
+package repo_test
+
+import (
+    "testing"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/rony/tools"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func createPollMessage(msgID, peerID int64, poll *msg.MediaPoll) *msg.UserMessage {
+    media, _ := poll.Marshal()
+    return &msg.UserMessage{
+        ID:        msgID,
+        PeerID:    peerID,
+        PeerType:  int32(msg.PeerType_PeerUser),
+        SenderID:  peerID,
+        MediaType: msg.MediaType_MediaTypePoll,
+        Media:     media,
+    }
+}
+
+func getMessagePoll(c C, msgID int64) *msg.MediaPoll {
+    um, err := repo.Messages.Get(msgID)
+    c.So(err, ShouldBeNil)
+    poll := &msg.MediaPoll{}
+    c.So(poll.Unmarshal(um.Media), ShouldBeNil)
+    return poll
+}
+
+func TestPolls(t *testing.T) {
+    Convey("Polls", t, func(c C) {
+        peerID := tools.RandomInt64(0)
+        answers := []*msg.PollAnswer{{Text: "Yes", Option: []byte{1}}, {Text: "No", Option: []byte{2}}}
+        results := &msg.PollResults{
+            TotalVoters: 3,
+            Results: []*msg.PollAnswerVoters{
+                {Option: []byte{1}, Voters: 2, Chosen: true},
+                {Option: []byte{2}, Voters: 1},
+            },
+        }
+        Convey("Update Before Message", func(c C) {
+            pollID := tools.RandomInt64(0)
+            msgID := tools.RandomInt64(0)
+            um, err := repo.Polls.Apply(&msg.UpdateMessagePoll{
+                PollID:  pollID,
+                Poll:    &msg.MediaPoll{ID: pollID, Question: "Q?", Answers: answers, Closed: true},
+                Results: results,
+            })
+            c.So(err, ShouldBeNil)
+            c.So(um, ShouldBeNil)
+
+            err = repo.Messages.Save(createPollMessage(msgID, peerID, &msg.MediaPoll{ID: pollID, Question: "Q?", Answers: answers}))
+            c.So(err, ShouldBeNil)
+            c.So(getMessagePoll(c, msgID).Closed, ShouldBeTrue)
+
+            p, err := repo.Polls.Get(pollID)
+            c.So(err, ShouldBeNil)
+            c.So(p.Results.TotalVoters, ShouldEqual, 3)
+            linkedID, err := repo.Polls.GetMessageID(pollID)
+            c.So(err, ShouldBeNil)
+            c.So(linkedID, ShouldEqual, msgID)
+        })
+        Convey("Update After Message", func(c C) {
+            pollID := tools.RandomInt64(0)
+            msgID := tools.RandomInt64(0)
+            err := repo.Messages.Save(createPollMessage(msgID, peerID, &msg.MediaPoll{ID: pollID, Question: "Q?", Answers: answers}))
+            c.So(err, ShouldBeNil)
+            c.So(getMessagePoll(c, msgID).Closed, ShouldBeFalse)
+
+            um, err := repo.Polls.Apply(&msg.UpdateMessagePoll{PollID: pollID, Results: results})
+            c.So(err, ShouldBeNil)
+            c.So(um, ShouldNotBeNil)
+            c.So(um.ID, ShouldEqual, msgID)
+            p, err := repo.Polls.Get(pollID)
+            c.So(err, ShouldBeNil)
+            c.So(p.Poll, ShouldBeNil)
+            c.So(p.Results.Results, ShouldHaveLength, 2)
+
+            _, err = repo.Polls.Apply(&msg.UpdateMessagePoll{
+                PollID: pollID,
+                Poll:   &msg.MediaPoll{ID: pollID, Question: "Q?", Answers: answers, Closed: true},
+            })
+            c.So(err, ShouldBeNil)
+            c.So(getMessagePoll(c, msgID).Closed, ShouldBeTrue)
+
+            // A stale update must not reopen the poll
+            _, err = repo.Polls.Apply(&msg.UpdateMessagePoll{
+                PollID: pollID,
+                Poll:   &msg.MediaPoll{ID: pollID, Question: "Q?", Answers: answers},
+            })
+            c.So(err, ShouldBeNil)
+            c.So(getMessagePoll(c, msgID).Closed, ShouldBeTrue)
+            p, err = repo.Polls.Get(pollID)
+            c.So(err, ShouldBeNil)
+            c.So(p.Results.TotalVoters, ShouldEqual, 3)
+        })
+    })
+}
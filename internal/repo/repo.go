@@ -45,6 +45,11 @@ var (
     Teams           *repoTeams
     Reactions       *repoReactions
     Notifications   *repoNotifications
+    Polls           *repoPolls
+    Calendar        *repoCalendar
+    Community       *repoCommunity
+    Calls           *repoCalls
+    BotStates       *repoBotStates
 )
 
 // Context container of repo
@@ -98,6 +103,11 @@ func Init(dbPath string, lowMemory bool) error {
         Teams = &repoTeams{repository: r}
         Reactions = &repoReactions{repository: r}
         Notifications = &repoNotifications{repository: r}
+        Polls = &repoPolls{repository: r}
+        Calendar = &repoCalendar{repository: r}
+        Community = &repoCommunity{repository: r}
+        Calls = &repoCalls{repository: r}
+        BotStates = &repoBotStates{repository: r}
         singleton.Unlock()
     }
     return nil
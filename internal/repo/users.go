@@ -3,6 +3,7 @@ package repo
 import (
     "encoding/binary"
     "fmt"
+    "sort"
     "strings"
     "time"
 
@@ -21,6 +22,7 @@ const (
     prefixContacts          = "CONTACTS"
     prefixPhoneContacts     = "PH_CONTACTS"
     prefixUsersPhotoGallery = "USERS_PHG"
+    prefixBlockedUsers      = "BLOCKED"
 )
 
 type repoUsers struct {
@@ -94,6 +96,15 @@ func getUserPhotoGalleryPrefix(userID int64) []byte {
     return tools.StrToByte(fmt.Sprintf("%s.%021d.", prefixUsersPhotoGallery, userID))
 }
 
+func getBlockedUserKey(userID int64) []byte {
+    return tools.StrToByte(fmt.Sprintf("%s.%021d", prefixBlockedUsers, userID))
+}
+
+func saveBlocked(txn *badger.Txn, blockedContact *msg.BlockedContact) error {
+    b, _ := blockedContact.Marshal()
+    return txn.SetEntry(badger.NewEntry(getBlockedUserKey(blockedContact.UserID), b))
+}
+
 func saveUser(txn *badger.Txn, user *msg.User) error {
     userKey := getUserKey(user.ID)
     if user.Photo == nil {
@@ -282,8 +293,19 @@ func (r *repoUsers) Save(users ...*msg.User) error {
     })
 }
 
+// UpdateBlocked sets the blocked flag of the user and adds/removes the user to/from the blocked list
 func (r *repoUsers) UpdateBlocked(peerID int64, blocked bool) error {
     return badgerUpdate(func(txn *badger.Txn) error {
+        var err error
+        if blocked {
+            err = saveBlocked(txn, &msg.BlockedContact{UserID: peerID, Date: domain.Now().Unix()})
+        } else {
+            err = txn.Delete(getBlockedUserKey(peerID))
+        }
+        if err != nil {
+            return err
+        }
+
         user, err := getUserByKey(txn, getUserKey(peerID))
         switch err {
         case nil:
@@ -297,6 +319,86 @@ func (r *repoUsers) UpdateBlocked(peerID int64, blocked bool) error {
     })
 }
 
+// SaveBlocked adds the blocked contacts, received from the server, to the blocked list. If replace is set, the
+// blocked contacts which are not in the list are removed.
+func (r *repoUsers) SaveBlocked(replace bool, blockedContacts ...*msg.BlockedContact) error {
+    return badgerUpdate(func(txn *badger.Txn) error {
+        if replace {
+            opts := badger.DefaultIteratorOptions
+            opts.Prefix = tools.StrToByte(fmt.Sprintf("%s.", prefixBlockedUsers))
+            opts.PrefetchValues = false
+            it := txn.NewIterator(opts)
+            var keys [][]byte
+            for it.Rewind(); it.ValidForPrefix(opts.Prefix); it.Next() {
+                keys = append(keys, it.Item().KeyCopy(nil))
+            }
+            it.Close()
+            for _, key := range keys {
+                err := txn.Delete(key)
+                if err != nil {
+                    return err
+                }
+            }
+        }
+        for _, bc := range blockedContacts {
+            err := saveBlocked(txn, bc)
+            if err != nil {
+                return err
+            }
+        }
+        return nil
+    })
+}
+
+// GetBlocked returns the blocked contacts, the most recently blocked first, and the total number of them
+func (r *repoUsers) GetBlocked(offset, limit int32) ([]*msg.BlockedContact, int32) {
+    blockedContacts := make([]*msg.BlockedContact, 0, 16)
+    _ = badgerView(func(txn *badger.Txn) error {
+        opts := badger.DefaultIteratorOptions
+        opts.Prefix = tools.StrToByte(fmt.Sprintf("%s.", prefixBlockedUsers))
+        it := txn.NewIterator(opts)
+        defer it.Close()
+        for it.Rewind(); it.ValidForPrefix(opts.Prefix); it.Next() {
+            bc := &msg.BlockedContact{}
+            err := it.Item().Value(func(val []byte) error {
+                return bc.Unmarshal(val)
+            })
+            if err != nil {
+                return err
+            }
+            blockedContacts = append(blockedContacts, bc)
+        }
+        return nil
+    })
+    sort.SliceStable(blockedContacts, func(i, j int) bool {
+        return blockedContacts[i].Date > blockedContacts[j].Date
+    })
+
+    total := int32(len(blockedContacts))
+    if offset > total {
+        offset = total
+    }
+    blockedContacts = blockedContacts[offset:]
+    if limit > 0 && int32(len(blockedContacts)) > limit {
+        blockedContacts = blockedContacts[:limit]
+    }
+    return blockedContacts, total
+}
+
+func isBlocked(txn *badger.Txn, userID int64) bool {
+    _, err := txn.Get(getBlockedUserKey(userID))
+    return err == nil
+}
+
+func (r *repoUsers) IsBlocked(userID int64) bool {
+    blocked := false
+    _ = badgerView(func(txn *badger.Txn) error {
+        blocked = isBlocked(txn, userID)
+        return nil
+    })
+    return blocked
+}
+
 func (r *repoUsers) GetAccessHash(userID int64) (accessHash uint64, err error) {
     err = badgerView(func(txn *badger.Txn) error {
         user, err := getUserByKey(txn, getUserKey(userID))
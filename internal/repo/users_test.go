@@ -0,0 +1,95 @@
+package repo_test
+
+import (
+    "testing"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/rony/tools"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func TestBlockedUsers(t *testing.T) {
+    Convey("Blocked Users", t, func(c C) {
+        userID := tools.RandomInt64(0)
+        err := repo.Users.Save(&msg.User{ID: userID, FirstName: "Blocked"})
+        c.So(err, ShouldBeNil)
+
+        Convey("Block And Unblock", func(c C) {
+            c.So(repo.Users.UpdateBlocked(userID, true), ShouldBeNil)
+            c.So(repo.Users.IsBlocked(userID), ShouldBeTrue)
+            user, err := repo.Users.Get(userID)
+            c.So(err, ShouldBeNil)
+            c.So(user.Blocked, ShouldBeTrue)
+
+            c.So(repo.Users.UpdateBlocked(userID, false), ShouldBeNil)
+            c.So(repo.Users.IsBlocked(userID), ShouldBeFalse)
+            user, err = repo.Users.Get(userID)
+            c.So(err, ShouldBeNil)
+            c.So(user.Blocked, ShouldBeFalse)
+        })
+        Convey("Save And Get", func(c C) {
+            c.So(repo.Users.UpdateBlocked(userID, true), ShouldBeNil)
+            err := repo.Users.SaveBlocked(true,
+                &msg.BlockedContact{UserID: 1, Date: 100},
+                &msg.BlockedContact{UserID: 2, Date: 300},
+                &msg.BlockedContact{UserID: 3, Date: 200},
+            )
+            c.So(err, ShouldBeNil)
+            c.So(repo.Users.IsBlocked(userID), ShouldBeFalse)
+
+            blocked, total := repo.Users.GetBlocked(0, 2)
+            c.So(total, ShouldEqual, 3)
+            c.So(blocked, ShouldHaveLength, 2)
+            c.So(blocked[0].UserID, ShouldEqual, 2)
+            c.So(blocked[1].UserID, ShouldEqual, 3)
+
+            blocked, _ = repo.Users.GetBlocked(2, 2)
+            c.So(blocked, ShouldHaveLength, 1)
+            c.So(blocked[0].UserID, ShouldEqual, 1)
+            blocked, _ = repo.Users.GetBlocked(5, 2)
+            c.So(blocked, ShouldBeEmpty)
+        })
+        Convey("Dialogs Are Hidden", func(c C) {
+            teamID := tools.RandomInt64(0)
+            peers := []int64{tools.RandomInt64(0), tools.RandomInt64(0), userID, tools.RandomInt64(0)}
+            for idx, peerID := range peers {
+                err := repo.Dialogs.SaveNew(&msg.Dialog{
+                    TeamID:       teamID,
+                    PeerID:       peerID,
+                    PeerType:     int32(msg.PeerType_PeerUser),
+                    TopMessageID: int64(idx + 1),
+                }, int64(100+idx))
+                c.So(err, ShouldBeNil)
+            }
+            c.So(repo.Users.UpdateBlocked(userID, true), ShouldBeNil)
+            c.So(repo.Dialogs.CountDialogs(teamID), ShouldEqual, 4)
+            c.So(repo.Dialogs.CountUnblockedDialogs(teamID), ShouldEqual, 3)
+
+            // the page is filled by the dialog after the blocked one
+            dialogs, err := repo.Dialogs.ListUnblocked(teamID, 0, 2)
+            c.So(err, ShouldBeNil)
+            c.So(dialogs, ShouldHaveLength, 2)
+            c.So(dialogs[0].PeerID, ShouldEqual, peers[3])
+            c.So(dialogs[1].PeerID, ShouldEqual, peers[1])
+            dialogs, err = repo.Dialogs.ListUnblocked(teamID, 2, 2)
+            c.So(err, ShouldBeNil)
+            c.So(dialogs, ShouldHaveLength, 1)
+            c.So(dialogs[0].PeerID, ShouldEqual, peers[0])
+
+            c.So(repo.Users.UpdateBlocked(userID, false), ShouldBeNil)
+            c.So(repo.Dialogs.CountUnblockedDialogs(teamID), ShouldEqual, 4)
+            dialogs, _ = repo.Dialogs.ListUnblocked(teamID, 0, 2)
+            c.So(dialogs[1].PeerID, ShouldEqual, userID)
+        })
+    })
+}
@@ -18,6 +18,8 @@ const (
 	StatusCompleted  Status = 2 // RequestStatusCompleted already file is downloaded/uploaded
 	StatusCanceled   Status = 4 // RequestStatusCanceled canceled by user
 	StatusError      Status = 5 // RequestStatusError encountered error
+	StatusPaused     Status = 6 // RequestStatusPaused paused by user
+	StatusCorrupted  Status = 7 // RequestStatusCorrupted downloaded file did not pass the integrity check
 )
 
 func (rs Status) ToString() string {
@@ -32,6 +34,10 @@ func (rs Status) ToString() string {
 		return "Canceled"
 	case StatusError:
 		return "Error"
+	case StatusPaused:
+		return "Paused"
+	case StatusCorrupted:
+		return "Corrupted"
 	}
 	return ""
 }
@@ -0,0 +1,189 @@
+package fakeserver
+
+import (
+    "crypto/rand"
+    "crypto/rsa"
+    "encoding/binary"
+    "sync"
+
+    "github.com/monnand/dhkx"
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/rony/tools"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+const (
+    rsaFingerPrint = 0x01
+    dhFingerPrint  = 0x02
+)
+
+// smallPrimes are used to build the PQ of InitConnect, they are small enough to be factored fast by the
+// clients in tests.
+var smallPrimes = []uint64{1000003, 1000033, 1000037, 1000039, 1000081, 1000099}
+
+type serverKeys struct {
+    rsaKey  *rsa.PrivateKey
+    dhGroup *dhkx.DHGroup
+
+    mtx    sync.Mutex
+    nonces map[uint64]uint64 // serverNonce => pq
+}
+
+func newServerKeys() (*serverKeys, error) {
+    rsaKey, err := rsa.GenerateKey(rand.Reader, 1024)
+    if err != nil {
+        return nil, err
+    }
+    dhGroup, err := dhkx.GetGroup(14)
+    if err != nil {
+        return nil, err
+    }
+    return &serverKeys{
+        rsaKey:  rsaKey,
+        dhGroup: dhGroup,
+        nonces:  make(map[uint64]uint64),
+    }, nil
+}
+
+func (s *Server) getAuthKey(authID int64) []byte {
+    s.authLock.RLock()
+    defer s.authLock.RUnlock()
+    return s.authKeys[authID]
+}
+
+// CreateAuthKey registers a random AuthKey, it could be used to set up authorized clients without
+// running the handshake.
+func (s *Server) CreateAuthKey() (int64, []byte) {
+    authKey := make([]byte, 256)
+    _, _ = rand.Read(authKey)
+    return s.registerAuthKey(authKey), authKey
+}
+
+func (s *Server) registerAuthKey(authKey []byte) int64 {
+    var authKeyHash [32]byte
+    tools.MustSha256(authKey, authKeyHash[:0])
+    authID := int64(binary.LittleEndian.Uint64(authKeyHash[24:32]))
+    s.authLock.Lock()
+    s.authKeys[authID] = authKey
+    s.authLock.Unlock()
+    return authID
+}
+
+func (s *Server) systemGetServerKeys(ctx *Context) {
+    ctx.Reply(msg.C_SystemKeys, &msg.SystemKeys{
+        RSAPublicKeys: []*msg.RSAPublicKey{
+            {
+                FingerPrint: rsaFingerPrint,
+                N:           s.keys.rsaKey.N.String(),
+                E:           uint32(s.keys.rsaKey.E),
+            },
+        },
+        DHGroups: []*msg.DHGroup{
+            {
+                FingerPrint: dhFingerPrint,
+                Prime:       s.keys.dhGroup.P().Text(16),
+                Gen:         int32(s.keys.dhGroup.G().Int64()),
+            },
+        },
+    })
+}
+
+func (s *Server) initConnect(ctx *Context) {
+    req := &msg.InitConnect{}
+    if err := req.Unmarshal(ctx.Request().Message); err != nil {
+        ctx.Error(msg.ErrCodeInvalid, msg.ErrItemRequest)
+        return
+    }
+
+    p := smallPrimes[tools.RandomInt64(int64(len(smallPrimes)))]
+    q := p
+    for q == p {
+        q = smallPrimes[tools.RandomInt64(int64(len(smallPrimes)))]
+    }
+    serverNonce := uint64(tools.RandomInt64(0))
+    s.keys.mtx.Lock()
+    s.keys.nonces[serverNonce] = p * q
+    s.keys.mtx.Unlock()
+
+    ctx.Reply(msg.C_InitResponse, &msg.InitResponse{
+        ClientNonce:          req.ClientNonce,
+        ServerNonce:          serverNonce,
+        RSAPubKeyFingerPrint: rsaFingerPrint,
+        DHGroupFingerPrint:   dhFingerPrint,
+        PQ:                   p * q,
+        ServerTimestamp:      tools.TimeUnix(),
+    })
+}
+
+func (s *Server) initCompleteAuth(ctx *Context) {
+    req := &msg.InitCompleteAuth{}
+    if err := req.Unmarshal(ctx.Request().Message); err != nil {
+        ctx.Error(msg.ErrCodeInvalid, msg.ErrItemRequest)
+        return
+    }
+
+    s.keys.mtx.Lock()
+    pq, ok := s.keys.nonces[req.ServerNonce]
+    delete(s.keys.nonces, req.ServerNonce)
+    s.keys.mtx.Unlock()
+    if !ok || req.P*req.Q != pq {
+        ctx.Reply(msg.C_InitAuthCompleted, &msg.InitAuthCompleted{
+            ClientNonce: req.ClientNonce,
+            ServerNonce: req.ServerNonce,
+            Status:      msg.InitAuthCompleted_FAIL,
+        })
+        return
+    }
+
+    decrypted, err := rsa.DecryptPKCS1v15(rand.Reader, s.keys.rsaKey, req.EncryptedPayload)
+    if err != nil {
+        ctx.Error(msg.ErrCodeInvalid, msg.ErrItemRequest)
+        return
+    }
+    internal := &msg.InitCompleteAuthInternal{}
+    if err = internal.Unmarshal(decrypted); err != nil {
+        ctx.Error(msg.ErrCodeInvalid, msg.ErrItemRequest)
+        return
+    }
+
+    serverDhKey, err := s.keys.dhGroup.GeneratePrivateKey(rand.Reader)
+    if err != nil {
+        ctx.Error(msg.ErrCodeInternal, msg.ErrItemServer)
+        return
+    }
+    sharedKey, err := s.keys.dhGroup.ComputeKey(dhkx.NewPublicKey(req.ClientDHPubKey), serverDhKey)
+    if err != nil {
+        ctx.Error(msg.ErrCodeInvalid, msg.ErrItemRequest)
+        return
+    }
+    authKey := make([]byte, 256)
+    copy(authKey, sharedKey.Bytes())
+    s.registerAuthKey(authKey)
+
+    var (
+        authKeyHash [32]byte
+        secret      []byte
+        secretHash  [32]byte
+    )
+    tools.MustSha256(authKey, authKeyHash[:0])
+    secret = append(secret, internal.SecretNonce...)
+    secret = append(secret, byte(msg.InitAuthCompleted_OK))
+    secret = append(secret, authKeyHash[:8]...)
+    tools.MustSha256(secret, secretHash[:0])
+
+    ctx.Reply(msg.C_InitAuthCompleted, &msg.InitAuthCompleted{
+        ClientNonce:    req.ClientNonce,
+        ServerNonce:    req.ServerNonce,
+        Status:         msg.InitAuthCompleted_OK,
+        ServerDHPubKey: serverDhKey.Bytes(),
+        SecretHash:     binary.LittleEndian.Uint64(secretHash[24:32]),
+    })
+}
@@ -0,0 +1,105 @@
+package fakeserver
+
+import (
+    "github.com/ronaksoft/river-msg/go/msg"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+type fileLocation struct {
+    clusterID  int32
+    fileID     int64
+    accessHash uint64
+}
+
+type upload struct {
+    totalParts int32
+    parts      map[int32][]byte
+}
+
+// AddFile stores the file, hence it could be downloaded by FileGet
+func (s *Server) AddFile(clusterID int32, fileID int64, accessHash uint64, data []byte) {
+    s.filesLock.Lock()
+    s.files[fileLocation{clusterID: clusterID, fileID: fileID, accessHash: accessHash}] = data
+    s.filesLock.Unlock()
+}
+
+// UploadedFile returns the file uploaded by FileSavePart, it returns false if the upload is not complete yet
+func (s *Server) UploadedFile(fileID int64) ([]byte, bool) {
+    s.filesLock.Lock()
+    defer s.filesLock.Unlock()
+    u, ok := s.uploads[fileID]
+    if !ok || u.totalParts == 0 || int32(len(u.parts)) != u.totalParts {
+        return nil, false
+    }
+    var data []byte
+    for partID := int32(1); partID <= u.totalParts; partID++ {
+        p, ok := u.parts[partID]
+        if !ok {
+            return nil, false
+        }
+        data = append(data, p...)
+    }
+    return data, true
+}
+
+func (s *Server) fileGet(ctx *Context) {
+    req := &msg.FileGet{}
+    if err := req.Unmarshal(ctx.Request().Message); err != nil || req.Location == nil {
+        ctx.Error(msg.ErrCodeInvalid, msg.ErrItemRequest)
+        return
+    }
+
+    s.filesLock.Lock()
+    data, ok := s.files[fileLocation{
+        clusterID:  req.Location.ClusterID,
+        fileID:     req.Location.FileID,
+        accessHash: req.Location.AccessHash,
+    }]
+    s.filesLock.Unlock()
+    if !ok {
+        ctx.Error(msg.ErrCodeUnavailable, msg.ErrItemInputFile)
+        return
+    }
+
+    start := int(req.Offset)
+    if start > len(data) {
+        start = len(data)
+    }
+    end := len(data)
+    if req.Limit > 0 && start+int(req.Limit) < end {
+        end = start + int(req.Limit)
+    }
+    ctx.Reply(msg.C_File, &msg.File{
+        Bytes: data[start:end],
+    })
+}
+
+func (s *Server) fileSavePart(ctx *Context) {
+    req := &msg.FileSavePart{}
+    if err := req.Unmarshal(ctx.Request().Message); err != nil || req.PartID <= 0 {
+        ctx.Error(msg.ErrCodeInvalid, msg.ErrItemRequest)
+        return
+    }
+
+    s.filesLock.Lock()
+    u, ok := s.uploads[req.FileID]
+    if !ok {
+        u = &upload{parts: make(map[int32][]byte)}
+        s.uploads[req.FileID] = u
+    }
+    if req.TotalParts > 0 {
+        u.totalParts = req.TotalParts
+    }
+    u.parts[req.PartID] = append([]byte(nil), req.Bytes...)
+    s.filesLock.Unlock()
+
+    ctx.Reply(msg.C_Bool, &msg.Bool{Result: true})
+}
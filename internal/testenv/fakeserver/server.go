@@ -0,0 +1,405 @@
+package fakeserver
+
+import (
+    "io/ioutil"
+    "net"
+    "net/http"
+    "strings"
+    "sync"
+    "time"
+
+    "github.com/gobwas/ws"
+    "github.com/gobwas/ws/wsutil"
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/logs"
+    "github.com/ronaksoft/rony"
+    "github.com/ronaksoft/rony/registry"
+    "github.com/ronaksoft/rony/tools"
+    "go.uber.org/zap"
+    "google.golang.org/protobuf/proto"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+var (
+    logger *logs.Logger
+)
+
+func init() {
+    logger = logs.With("FakeServer")
+}
+
+// HandlerFunc handles a request received by the server. The handler must reply by calling ctx.Reply or
+// ctx.Error, otherwise the client receives nothing (which is useful to test the timeouts).
+type HandlerFunc func(ctx *Context)
+
+// Context is passed to the handlers
+type Context struct {
+    srv     *Server
+    authID  int64
+    req     *rony.MessageEnvelope
+    replies []*rony.MessageEnvelope
+}
+
+func (ctx *Context) Server() *Server {
+    return ctx.srv
+}
+
+// AuthID returns the auth id of the client, it is zero for the plain-text requests
+func (ctx *Context) AuthID() int64 {
+    return ctx.authID
+}
+
+func (ctx *Context) Request() *rony.MessageEnvelope {
+    return ctx.req
+}
+
+func (ctx *Context) Reply(constructor int64, m proto.Message) {
+    env := &rony.MessageEnvelope{}
+    env.Fill(ctx.req.RequestID, constructor, m)
+    ctx.replies = append(ctx.replies, env)
+}
+
+func (ctx *Context) Error(code, item string) {
+    ctx.Reply(rony.C_Error, &rony.Error{Code: code, Items: item})
+}
+
+// Server is an in-process River server to be used in tests. It speaks the same protocol as the real servers
+// (websocket and http, plain-text and encrypted messages), creates AuthKeys, serves the salts and the server
+// time, and answers the other requests by the handlers registered by the test. Updates could be pushed to
+// the connected clients with the update ids controlled by the test.
+type Server struct {
+    listener net.Listener
+    httpSrv  *http.Server
+
+    handlersLock sync.RWMutex
+    handlers     map[int64]HandlerFunc
+
+    connsLock sync.Mutex
+    conns     map[*wsConn]struct{}
+
+    keys     *serverKeys
+    authLock sync.RWMutex
+    authKeys map[int64][]byte
+
+    updatesLock sync.Mutex
+    updateID    int64
+    updates     []*msg.UpdateEnvelope
+
+    filesLock sync.Mutex
+    files     map[fileLocation][]byte
+    uploads   map[int64]*upload
+}
+
+type wsConn struct {
+    mtx    sync.Mutex
+    conn   net.Conn
+    authID int64
+}
+
+func (c *wsConn) write(b []byte) error {
+    c.mtx.Lock()
+    defer c.mtx.Unlock()
+    _ = c.conn.SetWriteDeadline(time.Now().Add(domain.WebsocketWriteTime))
+    return wsutil.WriteServerMessage(c.conn, ws.OpBinary, b)
+}
+
+// New creates and starts a server which listens on a random port of the loopback interface
+func New() (*Server, error) {
+    l, err := net.Listen("tcp4", "127.0.0.1:0")
+    if err != nil {
+        return nil, err
+    }
+    keys, err := newServerKeys()
+    if err != nil {
+        return nil, err
+    }
+    s := &Server{
+        listener: l,
+        handlers: make(map[int64]HandlerFunc),
+        conns:    make(map[*wsConn]struct{}),
+        keys:     keys,
+        authKeys: make(map[int64][]byte),
+        files:    make(map[fileLocation][]byte),
+        uploads:  make(map[int64]*upload),
+    }
+    s.registerDefaultHandlers()
+    s.httpSrv = &http.Server{Handler: s}
+    go func() {
+        _ = s.httpSrv.Serve(l)
+    }()
+    return s, nil
+}
+
+// Addr returns the address which must be used as the SeedHost of the network controller
+func (s *Server) Addr() string {
+    return s.listener.Addr().String()
+}
+
+// Close stops the server and closes all the connections
+func (s *Server) Close() {
+    _ = s.httpSrv.Close()
+    s.connsLock.Lock()
+    for c := range s.conns {
+        _ = c.conn.Close()
+    }
+    s.connsLock.Unlock()
+}
+
+// Handle registers the handler for the constructor, it overrides the previous handler (including the
+// default ones)
+func (s *Server) Handle(constructor int64, h HandlerFunc) {
+    s.handlersLock.Lock()
+    s.handlers[constructor] = h
+    s.handlersLock.Unlock()
+}
+
+// HandleReply registers a handler which always replies with the same message
+func (s *Server) HandleReply(constructor int64, resConstructor int64, res proto.Message) {
+    s.Handle(constructor, func(ctx *Context) {
+        ctx.Reply(resConstructor, res)
+    })
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+    if strings.EqualFold(r.Header.Get("Upgrade"), "websocket") {
+        s.serveWebsocket(w, r)
+        return
+    }
+
+    body, err := ioutil.ReadAll(r.Body)
+    if err != nil {
+        w.WriteHeader(http.StatusBadRequest)
+        return
+    }
+    authID, envelopes, err := s.decode(body)
+    if err != nil || len(envelopes) == 0 {
+        logger.Warn("got error on decoding http request", zap.Error(err))
+        w.WriteHeader(http.StatusBadRequest)
+        return
+    }
+
+    // Http requests have exactly one response
+    replies := s.execute(authID, envelopes[0])
+    if len(replies) == 0 {
+        return
+    }
+    b, err := s.encode(authID, replies[0])
+    if err != nil {
+        w.WriteHeader(http.StatusInternalServerError)
+        return
+    }
+    w.Header().Set("Content-Type", "application/protobuf")
+    _, _ = w.Write(b)
+}
+
+func (s *Server) serveWebsocket(w http.ResponseWriter, r *http.Request) {
+    conn, _, _, err := ws.UpgradeHTTP(r, w)
+    if err != nil {
+        logger.Warn("got error on upgrading websocket", zap.Error(err))
+        return
+    }
+    c := &wsConn{conn: conn}
+    s.connsLock.Lock()
+    s.conns[c] = struct{}{}
+    s.connsLock.Unlock()
+
+    defer func() {
+        s.connsLock.Lock()
+        delete(s.conns, c)
+        s.connsLock.Unlock()
+        _ = conn.Close()
+    }()
+
+    for {
+        // ReadClientData answers the pings itself
+        data, op, err := wsutil.ReadClientData(conn)
+        if err != nil {
+            return
+        }
+        if op != ws.OpBinary {
+            continue
+        }
+        authID, envelopes, err := s.decode(data)
+        if err != nil {
+            logger.Warn("got error on decoding websocket message", zap.Error(err))
+            continue
+        }
+        if authID != 0 {
+            c.mtx.Lock()
+            c.authID = authID
+            c.mtx.Unlock()
+        }
+        for _, env := range envelopes {
+            go func(env *rony.MessageEnvelope) {
+                for _, reply := range s.execute(authID, env) {
+                    b, err := s.encode(authID, reply)
+                    if err != nil {
+                        continue
+                    }
+                    _ = c.write(b)
+                }
+            }(env)
+        }
+    }
+}
+
+func (s *Server) execute(authID int64, req *rony.MessageEnvelope) []*rony.MessageEnvelope {
+    s.handlersLock.RLock()
+    h := s.handlers[req.Constructor]
+    s.handlersLock.RUnlock()
+
+    ctx := &Context{
+        srv:    s,
+        authID: authID,
+        req:    req,
+    }
+    if h == nil {
+        logger.Debug("no handler registered", zap.String("C", registry.ConstructorName(req.Constructor)))
+        ctx.Error(msg.ErrCodeUnavailable, msg.ErrItemApi)
+    } else {
+        h(ctx)
+    }
+    return ctx.replies
+}
+
+// decode extracts the envelopes of the received message, message containers are flattened
+func (s *Server) decode(data []byte) (int64, []*rony.MessageEnvelope, error) {
+    protoMessage := &msg.ProtoMessage{}
+    err := protoMessage.Unmarshal(data)
+    if err != nil {
+        return 0, nil, err
+    }
+
+    env := &rony.MessageEnvelope{}
+    if protoMessage.AuthID == 0 {
+        err = env.Unmarshal(protoMessage.Payload)
+        if err != nil {
+            return 0, nil, err
+        }
+    } else {
+        authKey := s.getAuthKey(protoMessage.AuthID)
+        if authKey == nil {
+            return 0, nil, domain.ErrAuthFailed
+        }
+        decrypted, err := domain.Decrypt(authKey, protoMessage.MessageKey, protoMessage.Payload)
+        if err != nil {
+            return 0, nil, err
+        }
+        encryptedPayload := &msg.ProtoEncryptedPayload{}
+        err = encryptedPayload.Unmarshal(decrypted)
+        if err != nil {
+            return 0, nil, err
+        }
+        env = encryptedPayload.Envelope
+    }
+
+    if env.Constructor != rony.C_MessageContainer {
+        return protoMessage.AuthID, []*rony.MessageEnvelope{env}, nil
+    }
+    container := &rony.MessageContainer{}
+    err = container.Unmarshal(env.Message)
+    if err != nil {
+        return 0, nil, err
+    }
+    return protoMessage.AuthID, container.Envelopes, nil
+}
+
+// encode wraps the envelope to be sent to the client, it encrypts the envelope if authID is not zero
+func (s *Server) encode(authID int64, env *rony.MessageEnvelope) ([]byte, error) {
+    protoMessage := &msg.ProtoMessage{}
+    if authID == 0 {
+        b, err := env.Marshal()
+        if err != nil {
+            return nil, err
+        }
+        protoMessage.Payload = b
+        return protoMessage.Marshal()
+    }
+
+    authKey := s.getAuthKey(authID)
+    if authKey == nil {
+        return nil, domain.ErrAuthFailed
+    }
+    encryptedPayload := &msg.ProtoEncryptedPayload{
+        ServerSalt: 0,
+        MessageID:  uint64(tools.NanoTime()),
+        Envelope:   env,
+    }
+    plain, err := encryptedPayload.Marshal()
+    if err != nil {
+        return nil, err
+    }
+    encrypted, err := domain.Encrypt(authKey, plain)
+    if err != nil {
+        return nil, err
+    }
+    protoMessage.AuthID = authID
+    protoMessage.MessageKey = domain.GenerateMessageKey(authKey, plain)
+    protoMessage.Payload = encrypted
+    return protoMessage.Marshal()
+}
+
+// push sends the envelope to all the websocket connections of the authID, if authID is zero it sends
+// the envelope to all the authorized connections.
+func (s *Server) push(authID int64, env *rony.MessageEnvelope) {
+    s.connsLock.Lock()
+    conns := make([]*wsConn, 0, len(s.conns))
+    for c := range s.conns {
+        conns = append(conns, c)
+    }
+    s.connsLock.Unlock()
+
+    for _, c := range conns {
+        c.mtx.Lock()
+        connAuthID := c.authID
+        c.mtx.Unlock()
+        if connAuthID == 0 || (authID != 0 && connAuthID != authID) {
+            continue
+        }
+        b, err := s.encode(connAuthID, env)
+        if err != nil {
+            continue
+        }
+        _ = c.write(b)
+    }
+}
+
+func (s *Server) registerDefaultHandlers() {
+    s.Handle(msg.C_SystemGetServerKeys, s.systemGetServerKeys)
+    s.Handle(msg.C_InitConnect, s.initConnect)
+    s.Handle(msg.C_InitCompleteAuth, s.initCompleteAuth)
+    s.Handle(msg.C_SystemGetServerTime, func(ctx *Context) {
+        ctx.Reply(msg.C_SystemServerTime, &msg.SystemServerTime{Timestamp: time.Now().Unix()})
+    })
+    s.Handle(msg.C_SystemGetSalts, func(ctx *Context) {
+        res := &msg.SystemSalts{
+            StartsFrom: time.Now().Unix(),
+            Duration:   int64(time.Hour),
+        }
+        for i := 0; i < 48; i++ {
+            res.Salts = append(res.Salts, tools.RandomInt64(0))
+        }
+        ctx.Reply(msg.C_SystemSalts, res)
+    })
+    s.Handle(msg.C_AuthRecall, func(ctx *Context) {
+        ctx.Reply(msg.C_AuthRecalled, &msg.AuthRecalled{
+            Timestamp: time.Now().Unix(),
+            UpdateID:  s.UpdateID(),
+        })
+    })
+    s.Handle(msg.C_UpdateGetState, func(ctx *Context) {
+        ctx.Reply(msg.C_UpdateState, &msg.UpdateState{UpdateID: s.UpdateID()})
+    })
+    s.Handle(msg.C_UpdateGetDifference, s.updateGetDifference)
+    s.Handle(msg.C_FileGet, s.fileGet)
+    s.Handle(msg.C_FileSavePart, s.fileSavePart)
+}
@@ -0,0 +1,243 @@
+package fakeserver_test
+
+import (
+    "context"
+    "crypto/rand"
+    "crypto/rsa"
+    "encoding/binary"
+    "math/big"
+    "testing"
+    "time"
+
+    "github.com/monnand/dhkx"
+    "github.com/ronaksoft/river-msg/go/msg"
+    networkCtrl "github.com/ronaksoft/river-sdk/internal/ctrl_network"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/request"
+    "github.com/ronaksoft/river-sdk/internal/testenv/fakeserver"
+    "github.com/ronaksoft/rony"
+    "github.com/ronaksoft/rony/tools"
+    . "github.com/smartystreets/goconvey/convey"
+    "google.golang.org/protobuf/proto"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func newClient(s *fakeserver.Server) (*networkCtrl.Controller, chan *msg.UpdateContainer) {
+    messageChan := make(chan []*rony.MessageEnvelope, 100)
+    updateChan := make(chan *msg.UpdateContainer, 100)
+    ctrl := networkCtrl.New(networkCtrl.Config{
+        SeedHosts: []string{s.Addr()},
+    })
+    ctrl.OnWebsocketConnect = func() error { return nil }
+    ctrl.OnNetworkStatusChange = func(newStatus domain.NetworkStatus) {}
+    ctrl.MessageChan = messageChan
+    ctrl.UpdateChan = updateChan
+    go func() {
+        for msgs := range messageChan {
+            for _, m := range msgs {
+                reqCB := request.GetCallback(m.RequestID)
+                if reqCB == nil {
+                    continue
+                }
+                select {
+                case reqCB.ResponseChan() <- m:
+                default:
+                }
+            }
+        }
+    }()
+    ctrl.Start()
+    ctrl.Connect()
+    return ctrl, updateChan
+}
+
+func call(ctrl *networkCtrl.Controller, constructor int64, req proto.Message) (res *rony.MessageEnvelope) {
+    ctrl.WebsocketCommand(
+        request.NewCallback(
+            0, 0, domain.NextRequestID(), constructor, req,
+            nil,
+            func(m *rony.MessageEnvelope) {
+                res = m
+            },
+            nil, false, request.SkipFlusher, domain.WebsocketRequestTimeout,
+        ),
+    )
+    return
+}
+
+func TestServer(t *testing.T) {
+    Convey("Fake Server", t, func(c C) {
+        s, err := fakeserver.New()
+        c.So(err, ShouldBeNil)
+        defer s.Close()
+        ctrl, updateChan := newClient(s)
+        defer ctrl.Stop()
+
+        Convey("Plain Request", func(c C) {
+            res := call(ctrl, msg.C_SystemGetServerTime, &msg.SystemGetServerTime{})
+            c.So(res, ShouldNotBeNil)
+            c.So(res.Constructor, ShouldEqual, msg.C_SystemServerTime)
+        })
+        Convey("Create AuthKey", func(c C) {
+            authID, authKey := createAuthKey(c, ctrl)
+            ctrl.SetAuthorization(authID, authKey)
+
+            res := call(ctrl, msg.C_AuthRecall, &msg.AuthRecall{})
+            c.So(res, ShouldNotBeNil)
+            c.So(res.Constructor, ShouldEqual, msg.C_AuthRecalled)
+        })
+        Convey("Custom Handler", func(c C) {
+            ctrl.SetAuthorization(s.CreateAuthKey())
+            s.Handle(msg.C_ContactsGet, func(ctx *fakeserver.Context) {
+                c.So(ctx.AuthID(), ShouldNotEqual, 0)
+                ctx.Reply(msg.C_ContactsMany, &msg.ContactsMany{
+                    Users: []*msg.User{{ID: 100, FirstName: "Ehsan"}},
+                })
+            })
+            res := call(ctrl, msg.C_ContactsGet, &msg.ContactsGet{})
+            c.So(res, ShouldNotBeNil)
+            c.So(res.Constructor, ShouldEqual, msg.C_ContactsMany)
+            x := &msg.ContactsMany{}
+            c.So(x.Unmarshal(res.Message), ShouldBeNil)
+            c.So(x.Users, ShouldHaveLength, 1)
+
+            res = call(ctrl, msg.C_ContactsDelete, &msg.ContactsDelete{})
+            c.So(res, ShouldNotBeNil)
+            c.So(res.Constructor, ShouldEqual, rony.C_Error)
+        })
+        Convey("Push Updates", func(c C) {
+            ctrl.SetAuthorization(s.CreateAuthKey())
+            // We need one request, so the server knows the authID of the connection
+            res := call(ctrl, msg.C_UpdateGetState, &msg.UpdateGetState{})
+            c.So(res, ShouldNotBeNil)
+
+            s.SetUpdateID(10)
+            updateID := s.PushUpdate(msg.C_UpdateUserTyping, &msg.UpdateUserTyping{UserID: 100})
+            c.So(updateID, ShouldEqual, 11)
+            select {
+            case uc := <-updateChan:
+                c.So(uc.Updates, ShouldHaveLength, 1)
+                c.So(uc.Updates[0].UpdateID, ShouldEqual, 11)
+                c.So(uc.Updates[0].Constructor, ShouldEqual, msg.C_UpdateUserTyping)
+            case <-time.After(5 * time.Second):
+                c.So("update not received", ShouldBeEmpty)
+            }
+
+            res = call(ctrl, msg.C_UpdateGetDifference, &msg.UpdateGetDifference{From: 1, Limit: 100})
+            c.So(res.Constructor, ShouldEqual, msg.C_UpdateDifference)
+            x := &msg.UpdateDifference{}
+            c.So(x.Unmarshal(res.Message), ShouldBeNil)
+            c.So(x.Updates, ShouldHaveLength, 1)
+            c.So(x.CurrentUpdateID, ShouldEqual, 11)
+        })
+        Convey("Http Files", func(c C) {
+            ctrl.SetAuthorization(s.CreateAuthKey())
+            data := []byte(tools.RandomID(1024))
+            s.AddFile(1, 1000, 2000, data)
+
+            var res *rony.MessageEnvelope
+            ctrl.HttpCommand(context.Background(), request.NewCallback(
+                0, 0, domain.NextRequestID(), msg.C_FileGet,
+                &msg.FileGet{
+                    Location: &msg.InputFileLocation{ClusterID: 1, FileID: 1000, AccessHash: 2000},
+                    Offset:   100,
+                    Limit:    200,
+                },
+                nil,
+                func(m *rony.MessageEnvelope) {
+                    res = m
+                },
+                nil, false, 0, domain.HttpRequestTimeout,
+            ))
+            c.So(res, ShouldNotBeNil)
+            c.So(res.Constructor, ShouldEqual, msg.C_File)
+            x := &msg.File{}
+            c.So(x.Unmarshal(res.Message), ShouldBeNil)
+            c.So(x.Bytes, ShouldResemble, data[100:300])
+
+            for partID := int32(1); partID <= 2; partID++ {
+                ctrl.HttpCommand(context.Background(), request.NewCallback(
+                    0, 0, domain.NextRequestID(), msg.C_FileSavePart,
+                    &msg.FileSavePart{
+                        FileID:     3000,
+                        PartID:     partID,
+                        TotalParts: 2,
+                        Bytes:      data[(partID-1)*512 : partID*512],
+                    },
+                    nil,
+                    func(m *rony.MessageEnvelope) {
+                        res = m
+                    },
+                    nil, false, 0, domain.HttpRequestTimeout,
+                ))
+                c.So(res.Constructor, ShouldEqual, msg.C_Bool)
+            }
+            uploaded, ok := s.UploadedFile(3000)
+            c.So(ok, ShouldBeTrue)
+            c.So(uploaded, ShouldResemble, data)
+        })
+    })
+}
+
+// createAuthKey runs the same handshake as the clients
+func createAuthKey(c C, ctrl *networkCtrl.Controller) (int64, []byte) {
+    res := call(ctrl, msg.C_SystemGetServerKeys, &msg.SystemGetServerKeys{})
+    c.So(res.Constructor, ShouldEqual, msg.C_SystemKeys)
+    sk := &msg.SystemKeys{}
+    c.So(sk.Unmarshal(res.Message), ShouldBeNil)
+
+    res = call(ctrl, msg.C_InitConnect, &msg.InitConnect{ClientNonce: domain.NextRequestID()})
+    c.So(res.Constructor, ShouldEqual, msg.C_InitResponse)
+    initRes := &msg.InitResponse{}
+    c.So(initRes.Unmarshal(res.Message), ShouldBeNil)
+
+    dhPrime := big.NewInt(0)
+    dhPrime.SetString(sk.DHGroups[0].Prime, 16)
+    dh := dhkx.CreateGroup(dhPrime, big.NewInt(int64(sk.DHGroups[0].Gen)))
+    clientDhKey, _ := dh.GeneratePrivateKey(rand.Reader)
+    req := &msg.InitCompleteAuth{
+        ClientNonce:    initRes.ClientNonce,
+        ServerNonce:    initRes.ServerNonce,
+        ClientDHPubKey: clientDhKey.Bytes(),
+    }
+    p, q := domain.SplitPQ(big.NewInt(int64(initRes.PQ)))
+    req.P, req.Q = p.Uint64(), q.Uint64()
+
+    n := big.NewInt(0)
+    n.SetString(sk.RSAPublicKeys[0].N, 10)
+    internal := &msg.InitCompleteAuthInternal{SecretNonce: []byte(domain.RandomID(16))}
+    b, _ := internal.Marshal()
+    req.EncryptedPayload, _ = rsa.EncryptPKCS1v15(rand.Reader, &rsa.PublicKey{N: n, E: int(sk.RSAPublicKeys[0].E)}, b)
+
+    res = call(ctrl, msg.C_InitCompleteAuth, req)
+    c.So(res.Constructor, ShouldEqual, msg.C_InitAuthCompleted)
+    completed := &msg.InitAuthCompleted{}
+    c.So(completed.Unmarshal(res.Message), ShouldBeNil)
+    c.So(completed.Status, ShouldEqual, msg.InitAuthCompleted_OK)
+
+    sharedKey, err := dh.ComputeKey(dhkx.NewPublicKey(completed.ServerDHPubKey), clientDhKey)
+    c.So(err, ShouldBeNil)
+    authKey := make([]byte, 256)
+    copy(authKey, sharedKey.Bytes())
+    var (
+        authKeyHash [32]byte
+        secret      []byte
+        secretHash  [32]byte
+    )
+    tools.MustSha256(authKey, authKeyHash[:0])
+    secret = append(secret, internal.SecretNonce...)
+    secret = append(secret, byte(msg.InitAuthCompleted_OK))
+    secret = append(secret, authKeyHash[:8]...)
+    tools.MustSha256(secret, secretHash[:0])
+    c.So(completed.SecretHash, ShouldEqual, binary.LittleEndian.Uint64(secretHash[24:32]))
+
+    return int64(binary.LittleEndian.Uint64(authKeyHash[24:32])), authKey
+}
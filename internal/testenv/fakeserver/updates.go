@@ -0,0 +1,107 @@
+package fakeserver
+
+import (
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/rony"
+    "github.com/ronaksoft/rony/tools"
+    "google.golang.org/protobuf/proto"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+const (
+    maxDifferenceLimit = 250
+)
+
+// UpdateID returns the id of the last update
+func (s *Server) UpdateID() int64 {
+    s.updatesLock.Lock()
+    defer s.updatesLock.Unlock()
+    return s.updateID
+}
+
+// SetUpdateID moves the update id forward, the next pushed update gets id+1. Setting an id bigger than the
+// current one creates a gap which is not served by UpdateGetDifference.
+func (s *Server) SetUpdateID(id int64) {
+    s.updatesLock.Lock()
+    s.updateID = id
+    s.updatesLock.Unlock()
+}
+
+// NewUpdate creates an update envelope with the next update id and stores it in the history, so it will be
+// served by UpdateGetDifference too. The update is not sent to the clients.
+func (s *Server) NewUpdate(constructor int64, m proto.Message) *msg.UpdateEnvelope {
+    b, _ := proto.Marshal(m)
+    s.updatesLock.Lock()
+    s.updateID++
+    u := &msg.UpdateEnvelope{
+        Constructor: constructor,
+        Update:      b,
+        UCount:      1,
+        UpdateID:    s.updateID,
+        Timestamp:   tools.TimeUnix(),
+    }
+    s.updates = append(s.updates, u)
+    s.updatesLock.Unlock()
+    return u
+}
+
+// PushUpdate creates a new update and sends it to all the authorized clients
+func (s *Server) PushUpdate(constructor int64, m proto.Message) int64 {
+    u := s.NewUpdate(constructor, m)
+    s.PushUpdateContainer(0, &msg.UpdateContainer{
+        Updates:     []*msg.UpdateEnvelope{u},
+        MinUpdateID: u.UpdateID,
+        MaxUpdateID: u.UpdateID,
+    })
+    return u.UpdateID
+}
+
+// PushUpdateContainer sends the container as is, hence tests could send out of order or duplicate updates.
+// If authID is zero, the container is sent to all the authorized clients.
+func (s *Server) PushUpdateContainer(authID int64, uc *msg.UpdateContainer) {
+    uc.Length = int32(len(uc.Updates))
+    env := &rony.MessageEnvelope{}
+    env.Fill(0, msg.C_UpdateContainer, uc)
+    s.push(authID, env)
+}
+
+func (s *Server) updateGetDifference(ctx *Context) {
+    req := &msg.UpdateGetDifference{}
+    if err := req.Unmarshal(ctx.Request().Message); err != nil {
+        ctx.Error(msg.ErrCodeInvalid, msg.ErrItemRequest)
+        return
+    }
+    if req.Limit <= 0 || req.Limit > maxDifferenceLimit {
+        req.Limit = maxDifferenceLimit
+    }
+
+    s.updatesLock.Lock()
+    res := &msg.UpdateDifference{
+        CurrentUpdateID: s.updateID,
+    }
+    for _, u := range s.updates {
+        if u.UpdateID < req.From {
+            continue
+        }
+        if len(res.Updates) == int(req.Limit) {
+            res.More = true
+            break
+        }
+        res.Updates = append(res.Updates, u)
+    }
+    s.updatesLock.Unlock()
+
+    if len(res.Updates) > 0 {
+        res.MinUpdateID = res.Updates[0].UpdateID
+        res.MaxUpdateID = res.Updates[len(res.Updates)-1].UpdateID
+    }
+    ctx.Reply(msg.C_UpdateDifference, res)
+}
@@ -0,0 +1,261 @@
+package uiexec
+
+import (
+    "context"
+    "sync"
+    "sync/atomic"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/rony/registry"
+    "go.uber.org/zap"
+    "google.golang.org/protobuf/proto"
+    "google.golang.org/protobuf/reflect/protoreflect"
+    "google.golang.org/protobuf/reflect/protoregistry"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+const (
+    defaultSubscriptionBuffer = 64
+)
+
+// OverflowPolicy defines what happens when the buffer of a subscription is full
+type OverflowPolicy int32
+
+const (
+    // OverflowDropNewest drops the update which does not fit in the buffer
+    OverflowDropNewest OverflowPolicy = iota
+    // OverflowDropOldest drops the oldest buffered update to make room for the new one
+    OverflowDropOldest
+    // OverflowClose closes the channel, hence the subscriber knows it has missed some updates and must re-sync
+    OverflowClose
+)
+
+// Filter selects the updates of a subscription. Zero values match everything.
+type Filter struct {
+    Constructors []int64
+    TeamID       int64
+    PeerID       int64
+    PeerType     int32
+}
+
+type subscription struct {
+    f            Filter
+    policy       OverflowPolicy
+    constructors map[int64]struct{}
+    ch           chan *msg.UpdateEnvelope
+    done         chan struct{}
+    overflowed   int32
+}
+
+func (s *subscription) match(ue *msg.UpdateEnvelope, t *updateTarget) bool {
+    if len(s.constructors) > 0 {
+        if _, ok := s.constructors[ue.Constructor]; !ok {
+            return false
+        }
+    }
+    if s.f.TeamID == 0 && s.f.PeerID == 0 && s.f.PeerType == 0 {
+        return true
+    }
+    t.load(ue)
+    if s.f.TeamID != 0 && s.f.TeamID != t.teamID {
+        return false
+    }
+    if s.f.PeerID != 0 && s.f.PeerID != t.peerID {
+        return false
+    }
+    if s.f.PeerType != 0 && s.f.PeerType != t.peerType {
+        return false
+    }
+    return true
+}
+
+// send never blocks, since it is called by the update appliers
+func (s *subscription) send(ue *msg.UpdateEnvelope) {
+    for {
+        select {
+        case s.ch <- ue:
+            return
+        default:
+        }
+        switch s.policy {
+        case OverflowDropOldest:
+            select {
+            case <-s.ch:
+            default:
+            }
+        case OverflowClose:
+            atomic.StoreInt32(&s.overflowed, 1)
+            return
+        default:
+            return
+        }
+    }
+}
+
+var (
+    subsLock sync.RWMutex
+    subs     = map[*subscription]struct{}{}
+)
+
+// Subscribe returns a channel which receives the updates matching the filter. The channel is closed when ctx is
+// done, or when the buffer overflows if the policy is OverflowClose. The received envelopes are shared between
+// the subscribers and must not be modified.
+func Subscribe(ctx context.Context, f Filter, bufferSize int, policy OverflowPolicy) <-chan *msg.UpdateEnvelope {
+    if bufferSize <= 0 {
+        bufferSize = defaultSubscriptionBuffer
+    }
+    s := &subscription{
+        f:            f,
+        policy:       policy,
+        constructors: make(map[int64]struct{}, len(f.Constructors)),
+        ch:           make(chan *msg.UpdateEnvelope, bufferSize),
+        done:         make(chan struct{}),
+    }
+    for _, c := range f.Constructors {
+        s.constructors[c] = struct{}{}
+    }
+
+    subsLock.Lock()
+    subs[s] = struct{}{}
+    subsLock.Unlock()
+
+    go func() {
+        select {
+        case <-ctx.Done():
+            unsubscribe(s)
+        case <-s.done:
+        }
+    }()
+    return s.ch
+}
+
+func unsubscribe(s *subscription) {
+    subsLock.Lock()
+    if _, ok := subs[s]; ok {
+        delete(subs, s)
+        close(s.ch)
+        close(s.done)
+    }
+    subsLock.Unlock()
+}
+
+// publish delivers the updates to the matched subscriptions
+func publish(constructor int64, m proto.Message) {
+    subsLock.RLock()
+    if len(subs) == 0 {
+        subsLock.RUnlock()
+        return
+    }
+
+    var updates []*msg.UpdateEnvelope
+    switch constructor {
+    case msg.C_UpdateContainer:
+        updates = m.(*msg.UpdateContainer).Updates
+    case msg.C_UpdateEnvelope:
+        updates = []*msg.UpdateEnvelope{m.(*msg.UpdateEnvelope)}
+    }
+
+    var overflowed []*subscription
+    for _, ue := range updates {
+        var (
+            t      updateTarget
+            cloned *msg.UpdateEnvelope
+        )
+        for s := range subs {
+            if atomic.LoadInt32(&s.overflowed) == 1 || !s.match(ue, &t) {
+                continue
+            }
+            if cloned == nil {
+                cloned = proto.Clone(ue).(*msg.UpdateEnvelope)
+            }
+            s.send(cloned)
+            if atomic.LoadInt32(&s.overflowed) == 1 {
+                overflowed = append(overflowed, s)
+            }
+        }
+    }
+    subsLock.RUnlock()
+
+    for _, s := range overflowed {
+        logger.Warn("subscription overflowed, we close it", zap.Int("BufferSize", cap(s.ch)))
+        unsubscribe(s)
+    }
+}
+
+// updateTarget is the team and the peer which the update belongs to. It is extracted from the well-known fields
+// of the updates (i.e. TeamID, Peer, PeerID, PeerType and Message), and loaded only if a subscription needs it.
+type updateTarget struct {
+    loaded   bool
+    teamID   int64
+    peerID   int64
+    peerType int32
+}
+
+func (t *updateTarget) load(ue *msg.UpdateEnvelope) {
+    if t.loaded {
+        return
+    }
+    t.loaded = true
+
+    mt, err := protoregistry.GlobalTypes.FindMessageByName(
+        protoreflect.FullName("msg." + registry.ConstructorName(ue.Constructor)),
+    )
+    if err != nil {
+        return
+    }
+    m := mt.New().Interface()
+    if err = proto.Unmarshal(ue.Update, m); err != nil {
+        return
+    }
+    t.extract(m.ProtoReflect())
+}
+
+func (t *updateTarget) extract(m protoreflect.Message) {
+    fields := m.Descriptor().Fields()
+    if fd := fields.ByName("TeamID"); fd != nil && t.teamID == 0 {
+        t.teamID = intValue(fd, m.Get(fd))
+    }
+    if fd := fields.ByName("PeerID"); fd != nil && t.peerID == 0 {
+        t.peerID = intValue(fd, m.Get(fd))
+    }
+    if fd := fields.ByName("PeerType"); fd != nil && t.peerType == 0 {
+        t.peerType = int32(intValue(fd, m.Get(fd)))
+    }
+    if fd := fields.ByName("Peer"); fd != nil && fd.Message() != nil && m.Has(fd) {
+        peer := m.Get(fd).Message()
+        peerFields := peer.Descriptor().Fields()
+        if fd := peerFields.ByName("ID"); fd != nil && t.peerID == 0 {
+            t.peerID = intValue(fd, peer.Get(fd))
+        }
+        if fd := peerFields.ByName("Type"); fd != nil && t.peerType == 0 {
+            t.peerType = int32(intValue(fd, peer.Get(fd)))
+        }
+    }
+    if fd := fields.ByName("Message"); fd != nil && fd.Message() != nil && m.Has(fd) {
+        t.extract(m.Get(fd).Message())
+    }
+}
+
+func intValue(fd protoreflect.FieldDescriptor, v protoreflect.Value) int64 {
+    if fd.Cardinality() == protoreflect.Repeated {
+        return 0
+    }
+    switch fd.Kind() {
+    case protoreflect.EnumKind:
+        return int64(v.Enum())
+    case protoreflect.Int32Kind, protoreflect.Int64Kind, protoreflect.Sint32Kind, protoreflect.Sint64Kind,
+        protoreflect.Sfixed32Kind, protoreflect.Sfixed64Kind:
+        return v.Int()
+    case protoreflect.Uint32Kind, protoreflect.Uint64Kind, protoreflect.Fixed32Kind, protoreflect.Fixed64Kind:
+        return int64(v.Uint())
+    }
+    return 0
+}
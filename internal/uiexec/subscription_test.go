@@ -0,0 +1,115 @@
+package uiexec_test
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/uiexec"
+    . "github.com/smartystreets/goconvey/convey"
+    "google.golang.org/protobuf/proto"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func envelope(constructor int64, m proto.Message, updateID int64) *msg.UpdateEnvelope {
+    b, _ := proto.Marshal(m)
+    return &msg.UpdateEnvelope{
+        Constructor: constructor,
+        Update:      b,
+        UpdateID:    updateID,
+    }
+}
+
+func receive(ch <-chan *msg.UpdateEnvelope) []int64 {
+    var ids []int64
+    for {
+        select {
+        case ue, ok := <-ch:
+            if !ok {
+                return ids
+            }
+            ids = append(ids, ue.UpdateID)
+        case <-time.After(100 * time.Millisecond):
+            return ids
+        }
+    }
+}
+
+func TestSubscribe(t *testing.T) {
+    Convey("Subscribe", t, func(c C) {
+        ctx, cf := context.WithCancel(context.Background())
+        defer cf()
+
+        updates := &msg.UpdateContainer{
+            Updates: []*msg.UpdateEnvelope{
+                envelope(msg.C_UpdateNewMessage, &msg.UpdateNewMessage{
+                    Message: &msg.UserMessage{TeamID: 10, PeerID: 100, PeerType: int32(msg.PeerType_PeerUser)},
+                }, 1),
+                envelope(msg.C_UpdateReadHistoryInbox, &msg.UpdateReadHistoryInbox{
+                    TeamID: 10, Peer: &msg.Peer{ID: 200, Type: int32(msg.PeerType_PeerGroup)},
+                }, 2),
+                envelope(msg.C_UpdateUserTyping, &msg.UpdateUserTyping{
+                    TeamID: 20, PeerID: 100, PeerType: int32(msg.PeerType_PeerUser),
+                }, 3),
+            },
+        }
+
+        Convey("Filter By Constructor", func(c C) {
+            ch := uiexec.Subscribe(ctx, uiexec.Filter{
+                Constructors: []int64{msg.C_UpdateNewMessage, msg.C_UpdateUserTyping},
+            }, 0, uiexec.OverflowDropNewest)
+            uiexec.ExecUpdate(msg.C_UpdateContainer, updates)
+            c.So(receive(ch), ShouldResemble, []int64{1, 3})
+        })
+        Convey("Filter By Team", func(c C) {
+            ch := uiexec.Subscribe(ctx, uiexec.Filter{TeamID: 10}, 0, uiexec.OverflowDropNewest)
+            uiexec.ExecUpdate(msg.C_UpdateContainer, updates)
+            c.So(receive(ch), ShouldResemble, []int64{1, 2})
+        })
+        Convey("Filter By Peer", func(c C) {
+            ch := uiexec.Subscribe(ctx, uiexec.Filter{PeerID: 100, PeerType: int32(msg.PeerType_PeerUser)}, 0, uiexec.OverflowDropNewest)
+            uiexec.ExecUpdate(msg.C_UpdateContainer, updates)
+            c.So(receive(ch), ShouldResemble, []int64{1, 3})
+
+            ch = uiexec.Subscribe(ctx, uiexec.Filter{PeerID: 200}, 0, uiexec.OverflowDropNewest)
+            uiexec.ExecUpdate(msg.C_UpdateEnvelope, updates.Updates[1])
+            c.So(receive(ch), ShouldResemble, []int64{2})
+        })
+        Convey("Filter By Peer Type", func(c C) {
+            ch := uiexec.Subscribe(ctx, uiexec.Filter{PeerType: int32(msg.PeerType_PeerGroup)}, 0, uiexec.OverflowDropNewest)
+            uiexec.ExecUpdate(msg.C_UpdateContainer, updates)
+            c.So(receive(ch), ShouldResemble, []int64{2})
+        })
+        Convey("Overflow", func(c C) {
+            dropNewest := uiexec.Subscribe(ctx, uiexec.Filter{}, 2, uiexec.OverflowDropNewest)
+            dropOldest := uiexec.Subscribe(ctx, uiexec.Filter{}, 2, uiexec.OverflowDropOldest)
+            closeOnOverflow := uiexec.Subscribe(ctx, uiexec.Filter{}, 2, uiexec.OverflowClose)
+            uiexec.ExecUpdate(msg.C_UpdateContainer, updates)
+            c.So(receive(dropNewest), ShouldResemble, []int64{1, 2})
+            c.So(receive(dropOldest), ShouldResemble, []int64{2, 3})
+            c.So(receive(closeOnOverflow), ShouldResemble, []int64{1, 2})
+            _, ok := <-closeOnOverflow
+            c.So(ok, ShouldBeFalse)
+        })
+        Convey("Cancel", func(c C) {
+            ctx, cf := context.WithCancel(context.Background())
+            ch := uiexec.Subscribe(ctx, uiexec.Filter{}, 0, uiexec.OverflowDropNewest)
+            cf()
+            select {
+            case _, ok := <-ch:
+                c.So(ok, ShouldBeFalse)
+            case <-time.After(time.Second):
+                c.So("channel is not closed", ShouldBeEmpty)
+            }
+        })
+    })
+}
@@ -121,6 +121,7 @@ func ExecTimeoutCB(h domain.TimeoutCallback) {
 }
 
 func ExecUpdate(constructor int64, m proto.Message) {
+    publish(constructor, m)
     buf := pools.Buffer.FromProto(m)
     exec(update, constructor, func() {
         updateCB(constructor, *buf.Bytes())
@@ -24,17 +24,23 @@ func New() *account {
     r := &account{}
     r.RegisterHandlers(
         map[int64]request.LocalHandler{
-            msg.C_AccountGetTeams:          r.accountsGetTeams,
-            msg.C_AccountRegisterDevice:    r.accountRegisterDevice,
-            msg.C_AccountRemovePhoto:       r.accountRemovePhoto,
-            msg.C_AccountSetNotifySettings: r.accountSetNotifySettings,
-            msg.C_AccountUnregisterDevice:  r.accountUnregisterDevice,
-            msg.C_AccountUpdateProfile:     r.accountUpdateProfile,
-            msg.C_AccountUpdateUsername:    r.accountUpdateUsername,
+            msg.C_AccountGetAuthorizations:  r.accountGetAuthorizations,
+            msg.C_AccountGetTeams:           r.accountsGetTeams,
+            msg.C_AccountRegisterDevice:     r.accountRegisterDevice,
+            msg.C_AccountRemovePhoto:        r.accountRemovePhoto,
+            msg.C_AccountResetAuthorization: r.accountResetAuthorization,
+            msg.C_AccountSetNotifySettings:  r.accountSetNotifySettings,
+            msg.C_AccountUnregisterDevice:   r.accountUnregisterDevice,
+            msg.C_AccountUpdateProfile:      r.accountUpdateProfile,
+            msg.C_AccountUpdateUsername:     r.accountUpdateUsername,
         },
     )
     r.RegisterUpdateAppliers(map[int64]domain.UpdateApplier{
-        msg.C_UpdateAccountPrivacy: r.updateAccountPrivacy,
+        msg.C_UpdateAccountPrivacy:     r.updateAccountPrivacy,
+        msg.C_UpdateAuthorizationReset: r.updateAuthorizationReset,
+    })
+    r.RegisterMessageAppliers(map[int64]domain.MessageApplier{
+        msg.C_AccountAuthorizations: r.accountAuthorizations,
     })
     return r
 }
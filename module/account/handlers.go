@@ -7,6 +7,7 @@ import (
     "github.com/ronaksoft/river-sdk/internal/domain"
     "github.com/ronaksoft/river-sdk/internal/repo"
     "github.com/ronaksoft/river-sdk/internal/request"
+    "github.com/ronaksoft/rony"
     "go.uber.org/zap"
 )
 
@@ -158,3 +159,51 @@ func (r *account) accountsGetTeams(da request.Callback) {
 
     r.SDK().QueueCtrl().EnqueueCommand(da)
 }
+
+func (r *account) accountGetAuthorizations(da request.Callback) {
+    req := &msg.AccountGetAuthorizations{}
+    if err := da.RequestData(req); err != nil {
+        return
+    }
+
+    // The sessions change on the server frequently, hence we only serve the cached list if we are offline
+    if !r.SDK().NetCtrl().Connected() {
+        auths, err := repo.Account.GetAuthorizations()
+        if err == nil {
+            r.Log().Info("returned data locally, AccountGetAuthorizations",
+                zap.Int("Authorizations", len(auths.Authorizations)),
+            )
+            da.Response(msg.C_AccountAuthorizations, auths)
+            return
+        }
+    }
+
+    // send the request to server
+    r.SDK().QueueCtrl().EnqueueCommand(da)
+}
+
+func (r *account) accountResetAuthorization(da request.Callback) {
+    req := &msg.AccountResetAuthorization{}
+    if err := da.RequestData(req); err != nil {
+        return
+    }
+
+    // remove the session optimistically and add it back if the server rejects the request
+    auth, err := repo.Account.DeleteAuthorization(req.AuthID)
+    r.Log().WarnOnErr("got error on deleting authorization", err, zap.Int64("AuthID", req.AuthID))
+    if auth != nil {
+        da.SetPreComplete(func(m *rony.MessageEnvelope) {
+            if m.Constructor != rony.C_Error {
+                return
+            }
+            r.Log().Warn("got error on AccountResetAuthorization",
+                zap.Int64("AuthID", req.AuthID),
+                zap.Error(domain.ParseServerError(m.Message)),
+            )
+            _ = repo.Account.AddAuthorization(auth)
+        })
+    }
+
+    // send the request to server
+    r.SDK().QueueCtrl().EnqueueCommand(da)
+}
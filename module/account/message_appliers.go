@@ -0,0 +1,31 @@
+package account
+
+import (
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/rony"
+    "go.uber.org/zap"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func (r *account) accountAuthorizations(e *rony.MessageEnvelope) {
+    x := new(msg.AccountAuthorizations)
+    err := x.Unmarshal(e.Message)
+    if err != nil {
+        r.Log().Error("couldn't unmarshal AccountAuthorizations", zap.Error(err))
+        return
+    }
+    r.Log().Debug("applies AccountAuthorizations",
+        zap.Int("Authorizations", len(x.Authorizations)),
+    )
+    err = repo.Account.SaveAuthorizations(x)
+    r.Log().WarnOnErr("got error on saving authorizations", err)
+}
@@ -2,6 +2,7 @@ package account
 
 import (
     "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
     "github.com/ronaksoft/river-sdk/internal/repo"
     "go.uber.org/zap"
 )
@@ -56,3 +57,30 @@ func (r *account) updateAccountPrivacy(u *msg.UpdateEnvelope) ([]*msg.UpdateEnve
     res := []*msg.UpdateEnvelope{u}
     return res, nil
 }
+
+func (r *account) updateAuthorizationReset(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
+    x := new(msg.UpdateAuthorizationReset)
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return nil, err
+    }
+
+    r.Log().Info("applies UpdateAuthorizationReset, our session has been reset",
+        zap.Int64("UpdateID", x.UpdateID),
+    )
+
+    // Our authorization is not valid anymore, so we drop our data and close the session the same as logout.
+    // Logout stops the sync controller which is applying this update, hence it must run in the background.
+    sdk, ok := r.SDK().(interface{ Logout(notifyServer bool, reason int) error })
+    if !ok {
+        r.Log().Warn("SDK does not support logout, could not close the session")
+        return []*msg.UpdateEnvelope{u}, nil
+    }
+    go func() {
+        err := sdk.Logout(false, domain.SessionClosedAuthorizationReset)
+        r.Log().ErrorOnErr("got error on logout after authorization reset", err)
+    }()
+
+    res := []*msg.UpdateEnvelope{u}
+    return res, nil
+}
@@ -1,9 +1,13 @@
 package bot
 
 import (
+    "sync"
+    "time"
+
     "github.com/ronaksoft/river-msg/go/msg"
     "github.com/ronaksoft/river-sdk/internal/domain"
     "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/river-sdk/internal/request"
     "github.com/ronaksoft/river-sdk/module"
     "github.com/ronaksoft/rony"
     "go.uber.org/zap"
@@ -18,12 +22,45 @@ import (
    Copyright Ronak Software Group 2020
 */
 
+const (
+    callbackAnswerTimeout = 10 * time.Second
+    // defaultInlineResultsTTL is used since BotResults does not have a CacheTime field, unlike BotCallbackAnswer,
+    // hence the bot cannot tell us how long its results are valid.
+    defaultInlineResultsTTL = time.Minute
+)
+
 type bot struct {
     module.Base
+
+    mu              sync.Mutex
+    delegate        Delegate
+    pendingAnswers  map[string][]request.Callback
+    answerTimeout   time.Duration
+    callbackAnswers *ttlCache
+    inlineResults   *ttlCache
+    inlineTTL       time.Duration
 }
 
 func New() *bot {
-    r := &bot{}
+    r := &bot{
+        pendingAnswers:  make(map[string][]request.Callback),
+        answerTimeout:   callbackAnswerTimeout,
+        callbackAnswers: newTTLCache(),
+        inlineResults:   newTTLCache(),
+        inlineTTL:       defaultInlineResultsTTL,
+    }
+    r.RegisterHandlers(
+        map[int64]request.LocalHandler{
+            msg.C_BotGetCallbackAnswer: r.botGetCallbackAnswer,
+            msg.C_BotGetInlineResults:  r.botGetInlineResults,
+        },
+    )
+    r.RegisterUpdateAppliers(
+        map[int64]domain.UpdateApplier{
+            msg.C_UpdateBotCallbackQuery: r.updateBotCallbackQuery,
+            msg.C_UpdateBotInlineQuery:   r.updateBotInlineQuery,
+        },
+    )
     r.RegisterMessageAppliers(
         map[int64]domain.MessageApplier{
             msg.C_BotResults: r.botResults,
@@ -35,6 +72,37 @@ func New() *bot {
 func (r *bot) Name() string {
     return module.Bot
 }
+
+// SetDelegate sets the delegate which receives the queries of the users, when the current account is a bot
+func (r *bot) SetDelegate(d Delegate) {
+    r.mu.Lock()
+    r.delegate = d
+    r.mu.Unlock()
+}
+
+// SetInlineResultsTTL sets how long the results of the inline queries are cached. If d is not positive the
+// default is used.
+func (r *bot) SetInlineResultsTTL(d time.Duration) {
+    if d <= 0 {
+        d = defaultInlineResultsTTL
+    }
+    r.mu.Lock()
+    r.inlineTTL = d
+    r.mu.Unlock()
+}
+
+func (r *bot) getInlineResultsTTL() time.Duration {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    return r.inlineTTL
+}
+
+func (r *bot) getDelegate() Delegate {
+    r.mu.Lock()
+    defer r.mu.Unlock()
+    return r.delegate
+}
+
 func (r *bot) botResults(e *rony.MessageEnvelope) {
     br := &msg.BotResults{}
     err := br.Unmarshal(e.Message)
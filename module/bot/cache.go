@@ -0,0 +1,79 @@
+package bot
+
+import (
+    "sync"
+    "time"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+const (
+    // cacheSweepSize is the number of the items which causes the expired items to be removed on Set
+    cacheSweepSize = 256
+)
+
+type cacheItem struct {
+    value    interface{}
+    expireOn time.Time
+}
+
+// ttlCache is an in memory cache which each item has its own expire time
+type ttlCache struct {
+    mu    sync.Mutex
+    items map[string]cacheItem
+    now   func() time.Time
+}
+
+func newTTLCache() *ttlCache {
+    return &ttlCache{
+        items: make(map[string]cacheItem),
+        now:   time.Now,
+    }
+}
+
+func (c *ttlCache) Get(key string) (interface{}, bool) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    item, ok := c.items[key]
+    if !ok {
+        return nil, false
+    }
+    if !c.now().Before(item.expireOn) {
+        delete(c.items, key)
+        return nil, false
+    }
+    return item.value, true
+}
+
+func (c *ttlCache) Set(key string, value interface{}, ttl time.Duration) {
+    if ttl <= 0 {
+        return
+    }
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    now := c.now()
+    if len(c.items) >= cacheSweepSize {
+        for k, item := range c.items {
+            if !now.Before(item.expireOn) {
+                delete(c.items, k)
+            }
+        }
+    }
+    c.items[key] = cacheItem{
+        value:    value,
+        expireOn: now.Add(ttl),
+    }
+}
+
+func (c *ttlCache) Len() int {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    return len(c.items)
+}
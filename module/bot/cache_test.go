@@ -0,0 +1,84 @@
+package bot
+
+import (
+    "fmt"
+    "testing"
+    "time"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func TestTTLCache(t *testing.T) {
+    Convey("TTL Cache", t, func(c C) {
+        now := time.Now()
+        cache := newTTLCache()
+        cache.now = func() time.Time { return now }
+
+        Convey("Expire", func(c C) {
+            cache.Set("k1", 1, time.Second)
+            cache.Set("k2", 2, time.Minute)
+            cache.Set("k3", 3, 0)
+            v, ok := cache.Get("k1")
+            c.So(ok, ShouldBeTrue)
+            c.So(v, ShouldEqual, 1)
+            _, ok = cache.Get("k3")
+            c.So(ok, ShouldBeFalse)
+
+            now = now.Add(time.Second)
+            _, ok = cache.Get("k1")
+            c.So(ok, ShouldBeFalse)
+            v, ok = cache.Get("k2")
+            c.So(ok, ShouldBeTrue)
+            c.So(v, ShouldEqual, 2)
+            c.So(cache.Len(), ShouldEqual, 1)
+        })
+        Convey("Sweep", func(c C) {
+            for i := 0; i < cacheSweepSize; i++ {
+                cache.Set(fmt.Sprintf("k%d", i), i, time.Second)
+            }
+            now = now.Add(time.Second)
+            cache.Set("k", 0, time.Second)
+            c.So(cache.Len(), ShouldEqual, 1)
+        })
+    })
+}
+
+func TestCacheKeys(t *testing.T) {
+    Convey("Cache Keys", t, func(c C) {
+        Convey("Callback Answer", func(c C) {
+            req := &msg.BotGetCallbackAnswer{
+                Peer:      &msg.InputPeer{ID: 10, Type: msg.PeerType_PeerUser},
+                Bot:       &msg.InputUser{UserID: 10},
+                MessageID: 100,
+                Data:      []byte("yes"),
+            }
+            k1 := callbackAnswerKey(0, req)
+            req.Data = []byte("no")
+            c.So(callbackAnswerKey(0, req), ShouldNotEqual, k1)
+            req.Data = []byte("yes")
+            c.So(callbackAnswerKey(0, req), ShouldEqual, k1)
+            c.So(callbackAnswerKey(1, req), ShouldNotEqual, k1)
+        })
+        Convey("Inline Results", func(c C) {
+            req := &msg.BotGetInlineResults{
+                Bot:   &msg.InputUser{UserID: 10},
+                Query: "cat",
+            }
+            k1 := inlineResultsKey(0, req)
+            req.Offset = "10"
+            c.So(inlineResultsKey(0, req), ShouldNotEqual, k1)
+            // the query must not be able to fake the offset
+            c.So(inlineResultsKey(0, &msg.BotGetInlineResults{Bot: req.Bot, Query: "cat\".\"10"}), ShouldNotEqual, inlineResultsKey(0, req))
+        })
+    })
+}
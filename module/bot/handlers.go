@@ -0,0 +1,153 @@
+package bot
+
+import (
+    "fmt"
+    "time"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/request"
+    "github.com/ronaksoft/rony"
+    "github.com/ronaksoft/rony/errors"
+    "github.com/ronaksoft/rony/registry"
+    "go.uber.org/zap"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func callbackAnswerKey(teamID int64, req *msg.BotGetCallbackAnswer) string {
+    return fmt.Sprintf("%d.%d.%d.%d.%d.%x",
+        teamID, req.GetBot().GetUserID(), req.GetPeer().GetID(), req.GetPeer().GetType(), req.MessageID, req.Data,
+    )
+}
+
+func inlineResultsKey(teamID int64, req *msg.BotGetInlineResults) string {
+    return fmt.Sprintf("%d.%d.%q.%q", teamID, req.GetBot().GetUserID(), req.Query, req.Offset)
+}
+
+// botGetCallbackAnswer sends the callback data of the pressed button to the bot. If the same button is pressed
+// again while the answer is on the way, both requests get the same answer. The answers are cached for the time
+// that bot has asked for.
+func (r *bot) botGetCallbackAnswer(da request.Callback) {
+    req := &msg.BotGetCallbackAnswer{}
+    if err := da.RequestData(req); err != nil {
+        return
+    }
+
+    key := callbackAnswerKey(da.TeamID(), req)
+    if v, ok := r.callbackAnswers.Get(key); ok {
+        da.Response(msg.C_BotCallbackAnswer, v.(*msg.BotCallbackAnswer))
+        return
+    }
+
+    // pressing a button later does not make sense, hence we do not queue the request
+    if !r.SDK().NetCtrl().Connected() {
+        da.Response(rony.C_Error, errors.New("00", domain.ErrNoConnection.Error()))
+        return
+    }
+
+    r.mu.Lock()
+    waiters, inFlight := r.pendingAnswers[key]
+    r.pendingAnswers[key] = append(waiters, da)
+    r.mu.Unlock()
+    if inFlight {
+        return
+    }
+
+    go r.SDK().NetCtrl().WebsocketCommand(
+        request.NewCallback(
+            da.TeamID(), da.TeamAccess(), domain.NextRequestID(), msg.C_BotGetCallbackAnswer, req,
+            func() {
+                r.resolveCallbackAnswer(key, nil)
+            },
+            func(m *rony.MessageEnvelope) {
+                r.resolveCallbackAnswer(key, m)
+            },
+            nil, false, request.SkipFlusher, r.answerTimeout,
+        ),
+    )
+}
+
+// resolveCallbackAnswer responds all the requests which are waiting for the answer, m is nil on timeout.
+func (r *bot) resolveCallbackAnswer(key string, m *rony.MessageEnvelope) {
+    r.mu.Lock()
+    waiters := r.pendingAnswers[key]
+    delete(r.pendingAnswers, key)
+    r.mu.Unlock()
+
+    if m == nil {
+        for _, w := range waiters {
+            w.OnTimeout()
+        }
+        return
+    }
+
+    switch m.Constructor {
+    case msg.C_BotCallbackAnswer:
+        x := &msg.BotCallbackAnswer{}
+        err := x.Unmarshal(m.Message)
+        if err != nil {
+            r.Log().Warn("couldn't unmarshal BotCallbackAnswer", zap.Error(err))
+            for _, w := range waiters {
+                w.Response(rony.C_Error, errors.New("00", err.Error()))
+            }
+            return
+        }
+        r.callbackAnswers.Set(key, x, time.Duration(x.CacheTime)*time.Second)
+        for _, w := range waiters {
+            w.Response(msg.C_BotCallbackAnswer, x)
+        }
+    case rony.C_Error:
+        x := &rony.Error{}
+        _ = x.Unmarshal(m.Message)
+        for _, w := range waiters {
+            w.Response(rony.C_Error, x)
+        }
+    default:
+        r.Log().Warn("received unexpected response", zap.String("C", registry.ConstructorName(m.Constructor)))
+        for _, w := range waiters {
+            w.Response(rony.C_Error, errors.New("00", domain.ErrInvalidConstructor.Error()))
+        }
+    }
+}
+
+// botGetInlineResults returns the results from the cache if the same query has been sent recently, otherwise the
+// request is sent to the server and the results are cached. BotResults does not carry a CacheTime, so the results
+// are kept for the TTL which is set by SetInlineResultsTTL.
+func (r *bot) botGetInlineResults(da request.Callback) {
+    req := &msg.BotGetInlineResults{}
+    if err := da.RequestData(req); err != nil {
+        return
+    }
+
+    // the results of the location based queries are not cached
+    if req.Location != nil {
+        r.SDK().QueueCtrl().EnqueueCommand(da)
+        return
+    }
+
+    key := inlineResultsKey(da.TeamID(), req)
+    if v, ok := r.inlineResults.Get(key); ok {
+        da.Response(msg.C_BotResults, v.(*msg.BotResults))
+        return
+    }
+
+    da.SetPreComplete(func(m *rony.MessageEnvelope) {
+        if m.Constructor != msg.C_BotResults {
+            return
+        }
+        x := &msg.BotResults{}
+        if err := x.Unmarshal(m.Message); err != nil {
+            return
+        }
+        r.inlineResults.Set(key, x, r.getInlineResultsTTL())
+    })
+    r.SDK().QueueCtrl().EnqueueCommand(da)
+}
@@ -0,0 +1,180 @@
+package bot
+
+import (
+    "sync/atomic"
+    "testing"
+    "time"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    networkCtrl "github.com/ronaksoft/river-sdk/internal/ctrl_network"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/request"
+    "github.com/ronaksoft/river-sdk/internal/testenv"
+    "github.com/ronaksoft/river-sdk/internal/testenv/fakeserver"
+    "github.com/ronaksoft/river-sdk/module"
+    "github.com/ronaksoft/rony"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+// testSDK provides only the network controller which is used by the handlers under test
+type testSDK struct {
+    module.SDK
+    network *networkCtrl.Controller
+}
+
+func (s *testSDK) NetCtrl() *networkCtrl.Controller {
+    return s.network
+}
+
+func newTestNetwork(s *fakeserver.Server) *networkCtrl.Controller {
+    network := networkCtrl.New(networkCtrl.Config{
+        SeedHosts: []string{s.Addr()},
+    })
+    network.OnWebsocketConnect = func() error { return nil }
+    network.OnNetworkStatusChange = func(newStatus domain.NetworkStatus) {}
+    network.MessageChan = make(chan []*rony.MessageEnvelope, 100)
+    network.UpdateChan = make(chan *msg.UpdateContainer, 100)
+    go func() {
+        for msgs := range network.MessageChan {
+            for _, m := range msgs {
+                reqCB := request.GetCallback(m.RequestID)
+                if reqCB == nil {
+                    continue
+                }
+                select {
+                case reqCB.ResponseChan() <- m:
+                default:
+                }
+            }
+        }
+    }()
+    network.Start()
+    network.Connect()
+    network.SetAuthorization(s.CreateAuthKey())
+    return network
+}
+
+type testAnswer struct {
+    res     *rony.MessageEnvelope
+    timeout bool
+}
+
+// pressButton calls the handler as the UI does and returns the channel which receives the result
+func pressButton(r *bot, req *msg.BotGetCallbackAnswer) chan testAnswer {
+    ch := make(chan testAnswer, 1)
+    r.botGetCallbackAnswer(
+        request.NewCallback(
+            0, 0, domain.NextRequestID(), msg.C_BotGetCallbackAnswer, req,
+            func() {
+                ch <- testAnswer{timeout: true}
+            },
+            func(m *rony.MessageEnvelope) {
+                ch <- testAnswer{res: m}
+            },
+            nil, false, 0, 0,
+        ),
+    )
+    return ch
+}
+
+func waitAnswer(ch chan testAnswer) testAnswer {
+    select {
+    case a := <-ch:
+        return a
+    case <-time.After(5 * time.Second):
+        So("no answer is received", ShouldBeEmpty)
+    }
+    return testAnswer{}
+}
+
+func TestCallbackAnswer(t *testing.T) {
+    testenv.Log().SetLogLevel(2)
+    Convey("Callback Answer", t, func(c C) {
+        s, err := fakeserver.New()
+        c.So(err, ShouldBeNil)
+        defer s.Close()
+
+        var (
+            calls   int32
+            release = make(chan struct{})
+        )
+        s.Handle(msg.C_BotGetCallbackAnswer, func(ctx *fakeserver.Context) {
+            atomic.AddInt32(&calls, 1)
+            req := &msg.BotGetCallbackAnswer{}
+            _ = req.Unmarshal(ctx.Request().Message)
+            if req.MessageID < 0 {
+                // the bot does not answer
+                return
+            }
+            <-release
+            ctx.Reply(msg.C_BotCallbackAnswer, &msg.BotCallbackAnswer{
+                Message:   "Done",
+                CacheTime: 60,
+            })
+        })
+
+        network := newTestNetwork(s)
+        defer network.Stop()
+        for i := 0; i < 50 && !network.Connected(); i++ {
+            time.Sleep(100 * time.Millisecond)
+        }
+        c.So(network.Connected(), ShouldBeTrue)
+
+        r := New()
+        r.Init(&testSDK{network: network}, testenv.Log())
+        newRequest := func(msgID int64) *msg.BotGetCallbackAnswer {
+            return &msg.BotGetCallbackAnswer{
+                Peer:      &msg.InputPeer{ID: 1, Type: msg.PeerType_PeerUser},
+                Bot:       &msg.InputUser{UserID: 2},
+                MessageID: msgID,
+                Data:      []byte{1, 2, 3},
+            }
+        }
+
+        Convey("Concurrent Presses Are Coalesced", func(c C) {
+            ch1 := pressButton(r, newRequest(10))
+            ch2 := pressButton(r, newRequest(10))
+            time.Sleep(200 * time.Millisecond)
+            close(release)
+
+            for _, ch := range []chan testAnswer{ch1, ch2} {
+                a := waitAnswer(ch)
+                c.So(a.timeout, ShouldBeFalse)
+                c.So(a.res.Constructor, ShouldEqual, msg.C_BotCallbackAnswer)
+                x := &msg.BotCallbackAnswer{}
+                c.So(x.Unmarshal(a.res.Message), ShouldBeNil)
+                c.So(x.Message, ShouldEqual, "Done")
+            }
+            c.So(atomic.LoadInt32(&calls), ShouldEqual, 1)
+            c.So(r.pendingAnswers, ShouldBeEmpty)
+
+            // the answer is cached for the time that the bot has asked for
+            a := waitAnswer(pressButton(r, newRequest(10)))
+            c.So(a.res.Constructor, ShouldEqual, msg.C_BotCallbackAnswer)
+            c.So(atomic.LoadInt32(&calls), ShouldEqual, 1)
+        })
+        Convey("Timeout Is Reported To All Waiters", func(c C) {
+            r.answerTimeout = 200 * time.Millisecond
+            ch1 := pressButton(r, newRequest(-10))
+            ch2 := pressButton(r, newRequest(-10))
+            c.So(waitAnswer(ch1).timeout, ShouldBeTrue)
+            c.So(waitAnswer(ch2).timeout, ShouldBeTrue)
+            c.So(atomic.LoadInt32(&calls), ShouldEqual, 1)
+            c.So(r.pendingAnswers, ShouldBeEmpty)
+            c.So(r.callbackAnswers.Len(), ShouldEqual, 0)
+
+            // the timeout is not cached, so pressing the button again sends a new request
+            c.So(waitAnswer(pressButton(r, newRequest(-10))).timeout, ShouldBeTrue)
+            c.So(atomic.LoadInt32(&calls), ShouldEqual, 2)
+        })
+    })
+}
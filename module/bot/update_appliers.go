@@ -0,0 +1,62 @@
+package bot
+
+import (
+    "github.com/ronaksoft/river-msg/go/msg"
+    "go.uber.org/zap"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+// Delegate receives the queries which users send to the bot, it is used when the current account is a bot.
+// The methods are called in their own goroutines, hence they could call the SDK and wait for the responses.
+type Delegate interface {
+    OnCallbackQuery(q *msg.UpdateBotCallbackQuery)
+    OnInlineQuery(q *msg.UpdateBotInlineQuery)
+}
+
+func (r *bot) updateBotCallbackQuery(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
+    x := &msg.UpdateBotCallbackQuery{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return nil, err
+    }
+
+    r.Log().Debug("applies UpdateBotCallbackQuery",
+        zap.Int64("QueryID", x.QueryID),
+        zap.Int64("UserID", x.UserID),
+    )
+
+    if d := r.getDelegate(); d != nil {
+        go d.OnCallbackQuery(x)
+    }
+
+    res := []*msg.UpdateEnvelope{u}
+    return res, nil
+}
+
+func (r *bot) updateBotInlineQuery(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
+    x := &msg.UpdateBotInlineQuery{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return nil, err
+    }
+
+    r.Log().Debug("applies UpdateBotInlineQuery",
+        zap.Int64("QueryID", x.QueryID),
+        zap.Int64("UserID", x.UserID),
+    )
+
+    if d := r.getDelegate(); d != nil {
+        go d.OnInlineQuery(x)
+    }
+
+    res := []*msg.UpdateEnvelope{u}
+    return res, nil
+}
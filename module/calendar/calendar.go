@@ -0,0 +1,47 @@
+package calendar
+
+import (
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/request"
+    "github.com/ronaksoft/river-sdk/module"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+type calendar struct {
+    module.Base
+}
+
+func New() *calendar {
+    r := &calendar{}
+    r.RegisterHandlers(
+        map[int64]request.LocalHandler{
+            msg.C_CalendarGetEvents: r.calendarGetEvents,
+        },
+    )
+    r.RegisterUpdateAppliers(
+        map[int64]domain.UpdateApplier{
+            msg.C_UpdateCalendarEventAdded:   r.updateCalendarEventAdded,
+            msg.C_UpdateCalendarEventEdited:  r.updateCalendarEventEdited,
+            msg.C_UpdateCalendarEventRemoved: r.updateCalendarEventRemoved,
+        },
+    )
+    r.RegisterMessageAppliers(
+        map[int64]domain.MessageApplier{
+            msg.C_CalendarEvent: r.calendarEvent,
+        },
+    )
+    return r
+}
+
+func (r *calendar) Name() string {
+    return module.Calendar
+}
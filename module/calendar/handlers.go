@@ -0,0 +1,69 @@
+package calendar
+
+import (
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/river-sdk/internal/request"
+    "github.com/ronaksoft/rony"
+    "github.com/ronaksoft/rony/errors"
+    "github.com/ronaksoft/rony/registry"
+    "go.uber.org/zap"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func (r *calendar) calendarGetEvents(da request.Callback) {
+    req := &msg.CalendarGetEvents{}
+    if err := da.RequestData(req); err != nil {
+        return
+    }
+    if req.From > req.To {
+        da.Response(rony.C_Error, errors.New("00", "INVALID_RANGE"))
+        return
+    }
+
+    // The range has been fetched before and no event has been changed since then
+    res, err := repo.Calendar.GetResult(da.TeamID(), req.Filter, req.From, req.To)
+    if err == nil {
+        r.Log().Debug("loads calendar events locally",
+            zap.Int64("From", req.From),
+            zap.Int64("To", req.To),
+        )
+        res.RequestID = da.RequestID()
+        res.Header = da.Envelope().Header
+        da.OnComplete(res)
+        return
+    }
+
+    r.Log().Info("detected calendar hole",
+        zap.Int64("TeamID", da.TeamID()),
+        zap.Int64("From", req.From),
+        zap.Int64("To", req.To),
+    )
+    da.SetPreComplete(r.getEventsCB(da.TeamID(), req.Filter, req.From, req.To))
+    r.SDK().QueueCtrl().EnqueueCommand(da)
+}
+
+func (r *calendar) getEventsCB(teamID int64, filter int32, from, to int64) domain.MessageHandler {
+    return func(m *rony.MessageEnvelope) {
+        switch m.Constructor {
+        case rony.C_Error:
+        default:
+            // river-msg does not define the result of CalendarGetEvents, hence it is saved as it is received
+            err := repo.Calendar.SaveResult(teamID, filter, from, to, m)
+            r.Log().WarnOnErr("got error on saving calendar events", err,
+                zap.Int64("From", from),
+                zap.Int64("To", to),
+                zap.String("C", registry.ConstructorName(m.Constructor)),
+            )
+        }
+    }
+}
@@ -0,0 +1,31 @@
+package calendar
+
+import (
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/rony"
+    "go.uber.org/zap"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func (r *calendar) calendarEvent(e *rony.MessageEnvelope) {
+    u := &msg.CalendarEvent{}
+    err := u.Unmarshal(e.Message)
+    if err != nil {
+        r.Log().Error("couldn't unmarshal CalendarEvent", zap.Error(err))
+        return
+    }
+
+    r.Log().Debug("applies CalendarEvent", zap.Int64("EventID", u.ID))
+
+    err = repo.Calendar.SaveEvent(u)
+    r.Log().WarnOnErr("got error on applying CalendarEvent", err)
+}
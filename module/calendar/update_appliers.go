@@ -0,0 +1,79 @@
+package calendar
+
+import (
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "go.uber.org/zap"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func (r *calendar) updateCalendarEventAdded(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
+    x := &msg.UpdateCalendarEventAdded{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return nil, err
+    }
+
+    r.Log().Debug("applies UpdateCalendarEventAdded",
+        zap.Int64("UpdateID", x.UpdateID),
+    )
+
+    if x.Event != nil {
+        // The update does not have the instances, SaveEvent invalidates the cached ranges to fetch them again
+        err = repo.Calendar.SaveEvent(x.Event)
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    return []*msg.UpdateEnvelope{u}, nil
+}
+
+func (r *calendar) updateCalendarEventEdited(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
+    x := &msg.UpdateCalendarEventEdited{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return nil, err
+    }
+
+    r.Log().Debug("applies UpdateCalendarEventEdited",
+        zap.Int64("UpdateID", x.UpdateID),
+    )
+
+    if x.Event != nil {
+        err = repo.Calendar.SaveEvent(x.Event)
+        if err != nil {
+            return nil, err
+        }
+    }
+
+    return []*msg.UpdateEnvelope{u}, nil
+}
+
+func (r *calendar) updateCalendarEventRemoved(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
+    x := &msg.UpdateCalendarEventRemoved{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return nil, err
+    }
+
+    r.Log().Debug("applies UpdateCalendarEventRemoved",
+        zap.Int64("UpdateID", x.UpdateID),
+        zap.Int64("EventID", x.EventID),
+    )
+
+    err = repo.Calendar.DeleteEvent(x.EventID)
+    if err != nil {
+        return nil, err
+    }
+
+    return []*msg.UpdateEnvelope{u}, nil
+}
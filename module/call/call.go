@@ -44,6 +44,9 @@ type call struct {
     activeCallID    int64
     callInfo        map[int64]*Info
     callDuration    map[int64]*Duration
+    historyTeams    map[int64]int64
+    machines        map[int64]*CallMachine
+    endedCallIDs    []int64
     rejectedCallIDs []int64
 
     iceServer []*msg.IceServer
@@ -63,6 +66,9 @@ func New(config *Config) *call {
         activeCallID:    0,
         callInfo:        make(map[int64]*Info),
         callDuration:    make(map[int64]*Duration),
+        historyTeams:    make(map[int64]int64),
+        machines:        make(map[int64]*CallMachine),
+        endedCallIDs:    nil,
         rejectedCallIDs: nil,
         iceServer:       nil,
         userID:          config.UserID,
@@ -106,6 +112,8 @@ func New(config *Config) *call {
             msg.C_ClientCallGroupAddParticipant:     c.groupAddParticipantHandler,
             msg.C_ClientCallGroupRemoveParticipant:  c.groupRemoveParticipantHandler,
             msg.C_ClientCallGroupUpdateAdmin:        c.groupUpdateAdminHandler,
+            msg.C_PhoneGetHistory:                   c.phoneGetHistoryHandler,
+            msg.C_PhoneRateCall:                     c.phoneRateCallHandler,
         },
     )
 
@@ -115,6 +123,10 @@ func New(config *Config) *call {
         msg.C_UpdatePhoneCallEnded:   c.updatePhoneCallEnded,
     })
 
+    c.RegisterMessageAppliers(map[int64]domain.MessageApplier{
+        msg.C_PhoneCallsMany: c.phoneCallsMany,
+    })
+
     return c
 }
 
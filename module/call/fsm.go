@@ -0,0 +1,448 @@
+package call
+
+import (
+    "fmt"
+    "sync"
+
+    "go.uber.org/zap"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+// CallState is the state of a call from the current user's point of view
+type CallState int32
+
+const (
+    CallStateIdle CallState = iota
+    CallStateDialing
+    CallStateRinging
+    CallStateConnecting
+    CallStateConnected
+    CallStateReconnecting
+    CallStateEnded
+)
+
+var callStateNames = map[CallState]string{
+    CallStateIdle:         "Idle",
+    CallStateDialing:      "Dialing",
+    CallStateRinging:      "Ringing",
+    CallStateConnecting:   "Connecting",
+    CallStateConnected:    "Connected",
+    CallStateReconnecting: "Reconnecting",
+    CallStateEnded:        "Ended",
+}
+
+func (s CallState) String() string {
+    if n, ok := callStateNames[s]; ok {
+        return n
+    }
+    return fmt.Sprintf("CallState(%d)", s)
+}
+
+// CallEvent is an input of the call state machine
+type CallEvent int32
+
+const (
+    // CallEventDial is fired when the current user starts or joins a call
+    CallEventDial CallEvent = iota
+    // CallEventRequested is fired on PhoneActionRequested
+    CallEventRequested
+    // CallEventAccept is fired when the current user accepts an incoming call
+    CallEventAccept
+    // CallEventAccepted is fired on PhoneActionAccepted
+    CallEventAccepted
+    // CallEventConnected is fired when a media stream of the call has been connected
+    CallEventConnected
+    // CallEventDisconnected is fired when a connection of the call has been lost
+    CallEventDisconnected
+    // CallEventReject is fired when the current user rejects or hangs up the call
+    CallEventReject
+    // CallEventDiscarded is fired on PhoneActionDiscarded which terminates the call
+    CallEventDiscarded
+    // CallEventTimeout is fired when no one answered the call
+    CallEventTimeout
+    // CallEventDestroy is fired when the resources of the call are released
+    CallEventDestroy
+)
+
+var callEventNames = map[CallEvent]string{
+    CallEventDial:         "Dial",
+    CallEventRequested:    "Requested",
+    CallEventAccept:       "Accept",
+    CallEventAccepted:     "Accepted",
+    CallEventConnected:    "Connected",
+    CallEventDisconnected: "Disconnected",
+    CallEventReject:       "Reject",
+    CallEventDiscarded:    "Discarded",
+    CallEventTimeout:      "Timeout",
+    CallEventDestroy:      "Destroy",
+}
+
+func (e CallEvent) String() string {
+    if n, ok := callEventNames[e]; ok {
+        return n
+    }
+    return fmt.Sprintf("CallEvent(%d)", e)
+}
+
+// callEnd are the events which end the call from any state. Ended absorbs them, since the server and the client
+// both may end the call at the same time, and the call could be rejected before we know anything about it.
+var callEnd = map[CallEvent]CallState{
+    CallEventReject:    CallStateEnded,
+    CallEventDiscarded: CallStateEnded,
+    CallEventTimeout:   CallStateEnded,
+    CallEventDestroy:   CallStateEnded,
+}
+
+// callTransitions declares all the valid transitions of a call. In group calls, Requested and Accepted are received
+// once per participant, hence they do not move the call backward.
+var callTransitions = map[CallState]map[CallEvent]CallState{
+    CallStateIdle: {
+        CallEventDial:      CallStateDialing,
+        CallEventRequested: CallStateRinging,
+    },
+    CallStateDialing: {
+        CallEventRequested: CallStateDialing,
+        CallEventAccepted:  CallStateConnecting,
+        CallEventConnected: CallStateConnected,
+    },
+    CallStateRinging: {
+        CallEventRequested: CallStateRinging,
+        CallEventAccept:    CallStateConnecting,
+    },
+    CallStateConnecting: {
+        CallEventRequested:    CallStateConnecting,
+        CallEventAccepted:     CallStateConnecting,
+        CallEventConnected:    CallStateConnected,
+        CallEventDisconnected: CallStateReconnecting,
+    },
+    CallStateConnected: {
+        CallEventRequested:    CallStateConnected,
+        CallEventAccepted:     CallStateConnected,
+        CallEventConnected:    CallStateConnected,
+        CallEventDisconnected: CallStateReconnecting,
+    },
+    CallStateReconnecting: {
+        CallEventRequested:    CallStateReconnecting,
+        CallEventAccepted:     CallStateReconnecting,
+        CallEventConnected:    CallStateConnected,
+        CallEventDisconnected: CallStateReconnecting,
+    },
+    CallStateEnded: {},
+}
+
+func nextCallState(s CallState, e CallEvent) (CallState, bool) {
+    if next, ok := callTransitions[s][e]; ok {
+        return next, true
+    }
+    if next, ok := callEnd[e]; ok {
+        return next, true
+    }
+    return s, false
+}
+
+// CallMachine holds the state of a call. It is safe for concurrent use.
+type CallMachine struct {
+    mu    sync.Mutex
+    state CallState
+}
+
+func NewCallMachine() *CallMachine {
+    return &CallMachine{
+        state: CallStateIdle,
+    }
+}
+
+func (m *CallMachine) State() CallState {
+    m.mu.Lock()
+    s := m.state
+    m.mu.Unlock()
+    return s
+}
+
+// Fire applies the event and returns the previous state. If the transition has not been declared, the state does
+// not change and ErrInvalidTransition is returned.
+func (m *CallMachine) Fire(e CallEvent) (CallState, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    from := m.state
+    next, ok := nextCallState(from, e)
+    if !ok {
+        return from, fmt.Errorf("%w: %s on %s", ErrInvalidTransition, e, from)
+    }
+    m.state = next
+    return from, nil
+}
+
+// ParticipantState is the state of the connection with one participant of the call
+type ParticipantState int32
+
+const (
+    ParticipantStateInvited ParticipantState = iota
+    ParticipantStateConnecting
+    ParticipantStateConnected
+    ParticipantStateReconnecting
+    ParticipantStateLeft
+)
+
+var participantStateNames = map[ParticipantState]string{
+    ParticipantStateInvited:      "Invited",
+    ParticipantStateConnecting:   "Connecting",
+    ParticipantStateConnected:    "Connected",
+    ParticipantStateReconnecting: "Reconnecting",
+    ParticipantStateLeft:         "Left",
+}
+
+func (s ParticipantState) String() string {
+    if n, ok := participantStateNames[s]; ok {
+        return n
+    }
+    return fmt.Sprintf("ParticipantState(%d)", s)
+}
+
+// ParticipantEvent is an input of the participant state machine
+type ParticipantEvent int32
+
+const (
+    // ParticipantEventAccepted is fired when the participant accepted the call, or we accepted its request
+    ParticipantEventAccepted ParticipantEvent = iota
+    // ParticipantEventConnected is fired when the media stream of the participant has been connected
+    ParticipantEventConnected
+    // ParticipantEventDisconnected is fired when the connection with the participant has been lost
+    ParticipantEventDisconnected
+    // ParticipantEventLeft is fired when the participant discarded the call or has been removed
+    ParticipantEventLeft
+)
+
+var participantEventNames = map[ParticipantEvent]string{
+    ParticipantEventAccepted:     "Accepted",
+    ParticipantEventConnected:    "Connected",
+    ParticipantEventDisconnected: "Disconnected",
+    ParticipantEventLeft:         "Left",
+}
+
+func (e ParticipantEvent) String() string {
+    if n, ok := participantEventNames[e]; ok {
+        return n
+    }
+    return fmt.Sprintf("ParticipantEvent(%d)", e)
+}
+
+// participantTransitions declares all the valid transitions of a participant. Left is final, and any state except
+// Left could be left.
+var participantTransitions = map[ParticipantState]map[ParticipantEvent]ParticipantState{
+    ParticipantStateInvited: {
+        ParticipantEventAccepted:  ParticipantStateConnecting,
+        ParticipantEventConnected: ParticipantStateConnected,
+        ParticipantEventLeft:      ParticipantStateLeft,
+    },
+    ParticipantStateConnecting: {
+        ParticipantEventAccepted:     ParticipantStateConnecting,
+        ParticipantEventConnected:    ParticipantStateConnected,
+        ParticipantEventDisconnected: ParticipantStateReconnecting,
+        ParticipantEventLeft:         ParticipantStateLeft,
+    },
+    ParticipantStateConnected: {
+        ParticipantEventConnected:    ParticipantStateConnected,
+        ParticipantEventDisconnected: ParticipantStateReconnecting,
+        ParticipantEventLeft:         ParticipantStateLeft,
+    },
+    ParticipantStateReconnecting: {
+        ParticipantEventAccepted:     ParticipantStateReconnecting,
+        ParticipantEventConnected:    ParticipantStateConnected,
+        ParticipantEventDisconnected: ParticipantStateReconnecting,
+        ParticipantEventLeft:         ParticipantStateLeft,
+    },
+    ParticipantStateLeft: {},
+}
+
+// ParticipantMachine holds the state of a participant. It is safe for concurrent use.
+type ParticipantMachine struct {
+    mu    sync.Mutex
+    state ParticipantState
+}
+
+func NewParticipantMachine() *ParticipantMachine {
+    return &ParticipantMachine{
+        state: ParticipantStateInvited,
+    }
+}
+
+func (m *ParticipantMachine) State() ParticipantState {
+    m.mu.Lock()
+    s := m.state
+    m.mu.Unlock()
+    return s
+}
+
+// Fire applies the event and returns the previous state. If the transition has not been declared, the state does
+// not change and ErrInvalidTransition is returned.
+func (m *ParticipantMachine) Fire(e ParticipantEvent) (ParticipantState, error) {
+    m.mu.Lock()
+    defer m.mu.Unlock()
+    from := m.state
+    next, ok := participantTransitions[from][e]
+    if !ok {
+        return from, fmt.Errorf("%w: %s on %s", ErrInvalidTransition, e, from)
+    }
+    m.state = next
+    return from, nil
+}
+
+// maxEndedCallIDs is the number of the recently ended calls which are remembered after their machines are removed,
+// so the late updates of them are not taken as new calls.
+const maxEndedCallIDs = 32
+
+// fire applies the event on the machine of the call, invalid transitions are logged and returned. The machine is
+// removed once the call is ended.
+func (c *call) fire(callID int64, e CallEvent) error {
+    c.mu.Lock()
+    m, ok := c.machines[callID]
+    if !ok {
+        m = NewCallMachine()
+        if c.isEnded(callID) {
+            m.state = CallStateEnded
+        }
+        c.machines[callID] = m
+    }
+    c.mu.Unlock()
+
+    from, err := m.Fire(e)
+    if m.State() == CallStateEnded {
+        c.removeMachine(callID)
+    }
+    if err != nil {
+        c.Log().Warn("got invalid call transition",
+            zap.Int64("CallID", callID),
+            zap.String("State", from.String()),
+            zap.String("Event", e.String()),
+        )
+        return err
+    }
+    c.Log().Debug("call transition",
+        zap.Int64("CallID", callID),
+        zap.String("From", from.String()),
+        zap.String("To", m.State().String()),
+    )
+    return nil
+}
+
+// removeMachine removes the machine of the ended call and remembers the call as ended. TempCallID is reused by
+// the next dial, hence it is not remembered.
+func (c *call) removeMachine(callID int64) {
+    c.mu.Lock()
+    defer c.mu.Unlock()
+    delete(c.machines, callID)
+    if callID == TempCallID || c.isEnded(callID) {
+        return
+    }
+    c.endedCallIDs = append(c.endedCallIDs, callID)
+    if len(c.endedCallIDs) > maxEndedCallIDs {
+        c.endedCallIDs = c.endedCallIDs[len(c.endedCallIDs)-maxEndedCallIDs:]
+    }
+}
+
+// isEnded must be called while c.mu is locked
+func (c *call) isEnded(callID int64) bool {
+    for _, id := range c.endedCallIDs {
+        if id == callID {
+            return true
+        }
+    }
+    return false
+}
+
+// fireParticipant applies the event on the machine of the participant which has the connection connId
+func (c *call) fireParticipant(callID int64, connId int32, e ParticipantEvent) error {
+    info := c.getCallInfo(callID)
+    if info == nil {
+        return ErrInvalidCallID
+    }
+
+    info.mu.Lock()
+    if info.machines == nil {
+        info.machines = make(map[int32]*ParticipantMachine)
+    }
+    m, ok := info.machines[connId]
+    if !ok {
+        m = NewParticipantMachine()
+        info.machines[connId] = m
+    }
+    info.mu.Unlock()
+
+    from, err := m.Fire(e)
+    if err != nil {
+        c.Log().Warn("got invalid participant transition",
+            zap.Int64("CallID", callID),
+            zap.Int32("ConnId", connId),
+            zap.String("State", from.String()),
+            zap.String("Event", e.String()),
+        )
+        return err
+    }
+    return nil
+}
+
+// resetMachine starts a new machine for the call, it is used for TempCallID which is reused by every dial
+func (c *call) resetMachine(callID int64) {
+    c.mu.Lock()
+    c.machines[callID] = NewCallMachine()
+    c.mu.Unlock()
+}
+
+// moveMachine moves the machine of the call to its new id, i.e. when the server returns the id of a dialed call
+func (c *call) moveMachine(fromID, toID int64) {
+    c.mu.Lock()
+    if m, ok := c.machines[fromID]; ok {
+        c.machines[toID] = m
+        delete(c.machines, fromID)
+    }
+    c.mu.Unlock()
+}
+
+func (c *call) callState(callID int64) CallState {
+    c.mu.RLock()
+    m, ok := c.machines[callID]
+    ended := c.isEnded(callID)
+    c.mu.RUnlock()
+    if !ok {
+        if ended {
+            return CallStateEnded
+        }
+        return CallStateIdle
+    }
+    return m.State()
+}
+
+func (c *call) participantState(callID int64, connId int32) ParticipantState {
+    info := c.getCallInfo(callID)
+    if info == nil {
+        return ParticipantStateLeft
+    }
+    info.mu.RLock()
+    m, ok := info.machines[connId]
+    info.mu.RUnlock()
+    if !ok {
+        return ParticipantStateInvited
+    }
+    return m.State()
+}
+
+// isDialing returns true if the current user is dialing a call other than callID
+func (c *call) isDialing(callID int64) bool {
+    c.mu.RLock()
+    defer c.mu.RUnlock()
+    for id, m := range c.machines {
+        if id != callID && m.State() == CallStateDialing {
+            return true
+        }
+    }
+    return false
+}
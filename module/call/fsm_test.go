@@ -0,0 +1,282 @@
+package call
+
+import (
+    "errors"
+    "sync"
+    "testing"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    fileCtrl "github.com/ronaksoft/river-sdk/internal/ctrl_file"
+    networkCtrl "github.com/ronaksoft/river-sdk/internal/ctrl_network"
+    queueCtrl "github.com/ronaksoft/river-sdk/internal/ctrl_queue"
+    syncCtrl "github.com/ronaksoft/river-sdk/internal/ctrl_sync"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/river-sdk/internal/request"
+    "github.com/ronaksoft/river-sdk/internal/testenv"
+    "github.com/ronaksoft/river-sdk/module"
+    "github.com/ronaksoft/rony"
+    "github.com/ronaksoft/rony/tools"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func init() {
+    repo.MustInit("./_data", false)
+    testenv.Log().SetLogLevel(2)
+}
+
+// fakeSDK answers every request with Bool and records the constructors of the requests
+type fakeSDK struct {
+    mu           sync.Mutex
+    constructors []int64
+}
+
+func (f *fakeSDK) Version() string                       { return "test" }
+func (f *fakeSDK) SyncCtrl() *syncCtrl.Controller        { return nil }
+func (f *fakeSDK) NetCtrl() *networkCtrl.Controller      { return nil }
+func (f *fakeSDK) QueueCtrl() *queueCtrl.Controller      { return nil }
+func (f *fakeSDK) FileCtrl() *fileCtrl.Controller        { return nil }
+func (f *fakeSDK) GetConnInfo() domain.RiverConfigurator { return nil }
+func (f *fakeSDK) Module(name string) module.Module      { return nil }
+
+func (f *fakeSDK) Execute(cb request.Callback) error {
+    f.mu.Lock()
+    f.constructors = append(f.constructors, cb.Constructor())
+    f.mu.Unlock()
+
+    res, _ := (&msg.Bool{Result: true}).Marshal()
+    go cb.OnComplete(&rony.MessageEnvelope{
+        Constructor: msg.C_Bool,
+        RequestID:   cb.RequestID(),
+        Message:     res,
+    })
+    return nil
+}
+
+func (f *fakeSDK) Constructors() []int64 {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    return append([]int64{}, f.constructors...)
+}
+
+// fakeDelegate records the updates which are sent to the client
+type fakeDelegate struct {
+    mu      sync.Mutex
+    updates []msg.CallUpdate
+}
+
+func (f *fakeDelegate) OnUpdate(action int32, b []byte) {
+    f.mu.Lock()
+    f.updates = append(f.updates, msg.CallUpdate(action))
+    f.mu.Unlock()
+}
+
+func (f *fakeDelegate) Count(action msg.CallUpdate) int {
+    f.mu.Lock()
+    defer f.mu.Unlock()
+    n := 0
+    for _, u := range f.updates {
+        if u == action {
+            n++
+        }
+    }
+    return n
+}
+
+func newTestCall(userID int64) (*call, *fakeSDK, *fakeDelegate) {
+    sdk := &fakeSDK{}
+    delegate := &fakeDelegate{}
+    c := New(&Config{
+        UserID: userID,
+        Callback: &Callback{
+            OnUpdate: delegate.OnUpdate,
+        },
+    })
+    c.Init(sdk, testenv.Log())
+    return c, sdk, delegate
+}
+
+func requestedUpdate(callID, callerID, calleeID int64) *UpdatePhoneCall {
+    return &UpdatePhoneCall{
+        UpdatePhoneCall: &msg.UpdatePhoneCall{
+            PeerID:   callerID,
+            PeerType: int32(msg.PeerType_PeerUser),
+            CallID:   callID,
+            UserID:   callerID,
+            Action:   msg.PhoneCallAction_PhoneCallRequested,
+        },
+        Data: &msg.PhoneActionRequested{
+            Participants: []*msg.PhoneParticipant{
+                {ConnectionId: 0, Peer: &msg.InputUser{UserID: callerID}, Initiator: true},
+                {ConnectionId: 1, Peer: &msg.InputUser{UserID: calleeID}},
+            },
+        },
+    }
+}
+
+func discardedUpdate(callID, callerID int64) *UpdatePhoneCall {
+    return &UpdatePhoneCall{
+        UpdatePhoneCall: &msg.UpdatePhoneCall{
+            PeerID:   callerID,
+            PeerType: int32(msg.PeerType_PeerUser),
+            CallID:   callID,
+            UserID:   callerID,
+            Action:   msg.PhoneCallAction_PhoneCallDiscarded,
+        },
+        Data: &msg.PhoneActionDiscarded{
+            Reason: msg.DiscardReason_DiscardReasonHangup,
+        },
+    }
+}
+
+func TestCallMachine(t *testing.T) {
+    Convey("Call Machine", t, func(c C) {
+        Convey("Outgoing Call", func(c C) {
+            m := NewCallMachine()
+            for _, e := range []CallEvent{
+                CallEventDial, CallEventAccepted, CallEventConnected, CallEventDisconnected, CallEventConnected,
+            } {
+                _, err := m.Fire(e)
+                c.So(err, ShouldBeNil)
+            }
+            c.So(m.State(), ShouldEqual, CallStateConnected)
+            _, err := m.Fire(CallEventReject)
+            c.So(err, ShouldBeNil)
+            c.So(m.State(), ShouldEqual, CallStateEnded)
+            _, err = m.Fire(CallEventDestroy)
+            c.So(err, ShouldBeNil)
+            c.So(m.State(), ShouldEqual, CallStateEnded)
+        })
+        Convey("Invalid Transitions", func(c C) {
+            m := NewCallMachine()
+            _, err := m.Fire(CallEventRequested)
+            c.So(err, ShouldBeNil)
+            _, err = m.Fire(CallEventDial)
+            c.So(errors.Is(err, ErrInvalidTransition), ShouldBeTrue)
+            _, err = m.Fire(CallEventAccept)
+            c.So(err, ShouldBeNil)
+            from, err := m.Fire(CallEventAccept)
+            c.So(errors.Is(err, ErrInvalidTransition), ShouldBeTrue)
+            c.So(from, ShouldEqual, CallStateConnecting)
+            c.So(m.State(), ShouldEqual, CallStateConnecting)
+            _, err = m.Fire(CallEventDiscarded)
+            c.So(err, ShouldBeNil)
+            _, err = m.Fire(CallEventRequested)
+            c.So(errors.Is(err, ErrInvalidTransition), ShouldBeTrue)
+            c.So(m.State(), ShouldEqual, CallStateEnded)
+        })
+        Convey("Participant", func(c C) {
+            m := NewParticipantMachine()
+            for _, e := range []ParticipantEvent{
+                ParticipantEventAccepted, ParticipantEventConnected, ParticipantEventDisconnected,
+                ParticipantEventConnected, ParticipantEventLeft,
+            } {
+                _, err := m.Fire(e)
+                c.So(err, ShouldBeNil)
+            }
+            c.So(m.State(), ShouldEqual, ParticipantStateLeft)
+            _, err := m.Fire(ParticipantEventConnected)
+            c.So(errors.Is(err, ErrInvalidTransition), ShouldBeTrue)
+            c.So(m.State(), ShouldEqual, ParticipantStateLeft)
+        })
+    })
+}
+
+func TestCallUpdates(t *testing.T) {
+    Convey("Call Updates", t, func(c C) {
+        userID := tools.RandomInt64(0)
+        callerID := tools.RandomInt64(0)
+        callID := tools.RandomInt64(0)
+
+        Convey("Requested Then Discarded", func(c C) {
+            ca, _, delegate := newTestCall(userID)
+            ca.callRequested(requestedUpdate(callID, callerID, userID))
+            c.So(ca.callState(callID), ShouldEqual, CallStateRinging)
+            c.So(delegate.Count(msg.CallUpdate_CallRequested), ShouldEqual, 1)
+
+            ca.callDiscarded(discardedUpdate(callID, callerID))
+            c.So(ca.callState(callID), ShouldEqual, CallStateEnded)
+            c.So(delegate.Count(msg.CallUpdate_Destroyed), ShouldEqual, 1)
+            // the machine of the ended call is removed, only its id is remembered
+            c.So(ca.machines, ShouldNotContainKey, callID)
+
+            // A late request of an ended call must not ring again
+            ca.callRequested(requestedUpdate(callID, callerID, userID))
+            c.So(ca.callState(callID), ShouldEqual, CallStateEnded)
+            c.So(delegate.Count(msg.CallUpdate_CallRequested), ShouldEqual, 1)
+        })
+        Convey("Double Accept", func(c C) {
+            ca, sdk, _ := newTestCall(userID)
+            ca.callRequested(requestedUpdate(callID, callerID, userID))
+            c.So(ca.callState(callID), ShouldEqual, CallStateRinging)
+
+            // The first accept is in progress
+            err := ca.fire(callID, CallEventAccept)
+            c.So(err, ShouldBeNil)
+
+            err = ca.accept(callID, false)
+            c.So(errors.Is(err, ErrInvalidTransition), ShouldBeTrue)
+            c.So(ca.callState(callID), ShouldEqual, CallStateConnecting)
+            c.So(sdk.Constructors(), ShouldBeEmpty)
+        })
+        Convey("Glare", func(c C) {
+            ca, sdk, delegate := newTestCall(userID)
+            err := ca.fire(TempCallID, CallEventDial)
+            c.So(err, ShouldBeNil)
+
+            ca.callRequested(requestedUpdate(callID, callerID, userID))
+            c.So(ca.callState(callID), ShouldEqual, CallStateIdle)
+            c.So(ca.callState(TempCallID), ShouldEqual, CallStateDialing)
+            c.So(delegate.Count(msg.CallUpdate_CallRequested), ShouldEqual, 0)
+            c.So(sdk.Constructors(), ShouldResemble, []int64{msg.C_PhoneDiscardCall})
+        })
+        Convey("Ended Calls Are Bounded", func(c C) {
+            ca, _, _ := newTestCall(userID)
+            for i := int64(1); i <= maxEndedCallIDs+1; i++ {
+                c.So(ca.fire(i, CallEventRequested), ShouldBeNil)
+                c.So(ca.fire(i, CallEventDestroy), ShouldBeNil)
+            }
+            c.So(ca.machines, ShouldBeEmpty)
+            c.So(ca.endedCallIDs, ShouldHaveLength, maxEndedCallIDs)
+            c.So(ca.callState(1), ShouldEqual, CallStateIdle)
+            c.So(ca.callState(maxEndedCallIDs+1), ShouldEqual, CallStateEnded)
+
+            // a late event of an ended call does not start it again
+            err := ca.fire(maxEndedCallIDs+1, CallEventRequested)
+            c.So(errors.Is(err, ErrInvalidTransition), ShouldBeTrue)
+            c.So(ca.machines, ShouldBeEmpty)
+        })
+        Convey("Joined Call Direction", func(c C) {
+            ca, _, _ := newTestCall(userID)
+            participants := []*msg.PhoneParticipant{
+                {ConnectionId: 0, Peer: &msg.InputUser{UserID: callerID}, Initiator: true},
+                {ConnectionId: 1, Peer: &msg.InputUser{UserID: userID}},
+            }
+            c.So(ca.initiatedByOthers(participants), ShouldBeTrue)
+
+            // we rejoin the call which we have started
+            participants[0].Initiator = false
+            participants[1].Initiator = true
+            c.So(ca.initiatedByOthers(participants), ShouldBeFalse)
+        })
+        Convey("Participant Left", func(c C) {
+            ca, _, _ := newTestCall(userID)
+            ca.callRequested(requestedUpdate(callID, callerID, userID))
+            c.So(ca.participantState(callID, 0), ShouldEqual, ParticipantStateInvited)
+
+            c.So(ca.removeParticipant(callerID, &callID), ShouldBeTrue)
+            c.So(ca.participantState(callID, 0), ShouldEqual, ParticipantStateLeft)
+            err := ca.fireParticipant(callID, 0, ParticipantEventConnected)
+            c.So(errors.Is(err, ErrInvalidTransition), ShouldBeTrue)
+        })
+    })
+}
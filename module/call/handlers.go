@@ -3,8 +3,10 @@ package call
 import (
     "github.com/ronaksoft/river-msg/go/msg"
     "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/repo"
     "github.com/ronaksoft/river-sdk/internal/request"
     "github.com/ronaksoft/rony"
+    "go.uber.org/zap"
 )
 
 /*
@@ -344,3 +346,60 @@ func (c *call) groupUpdateAdminHandler(da request.Callback) {
 
     da.Response(msg.C_Bool, &msg.Bool{Result: true})
 }
+
+func (c *call) phoneGetHistoryHandler(da request.Callback) {
+    req := &msg.PhoneGetHistory{}
+    if err := da.RequestData(req); err != nil {
+        return
+    }
+
+    // The server has the calls of our other sessions too, hence the local history is only served when we are
+    // offline. The response of the server is merged into the local history by phoneCallsMany.
+    if c.SDK().NetCtrl().Connected() {
+        c.SDK().QueueCtrl().EnqueueCommand(da)
+        return
+    }
+
+    records, err := repo.Calls.List(da.TeamID(), req.After, req.Limit)
+    if err != nil {
+        da.Response(rony.C_Error, &rony.Error{Code: "E00", Items: err.Error()})
+        return
+    }
+
+    userID := c.SDK().SyncCtrl().GetUserID()
+    res := &msg.PhoneCallsMany{
+        PhoneCalls: make([]*msg.PhoneCallRecord, 0, len(records)),
+        Empty:      len(records) == 0,
+    }
+    mUsers := domain.MInt64B{}
+    mGroups := domain.MInt64B{}
+    for _, cr := range records {
+        res.PhoneCalls = append(res.PhoneCalls, cr.ToPhoneCallRecord(userID))
+        switch msg.PeerType(cr.PeerType) {
+        case msg.PeerType_PeerUser:
+            mUsers[cr.PeerID] = true
+        case msg.PeerType_PeerGroup:
+            mGroups[cr.PeerID] = true
+        }
+    }
+    res.Users, _ = repo.Users.GetMany(mUsers.ToArray())
+    res.Groups, _ = repo.Groups.GetMany(mGroups.ToArray())
+
+    c.Log().Info("returned data locally, PhoneGetHistory",
+        zap.Int("Calls", len(res.PhoneCalls)),
+    )
+    da.Response(msg.C_PhoneCallsMany, res)
+}
+
+func (c *call) phoneRateCallHandler(da request.Callback) {
+    req := &msg.PhoneRateCall{}
+    if err := da.RequestData(req); err != nil {
+        return
+    }
+
+    err := repo.Calls.SetRate(da.TeamID(), req.CallID, req.Rate)
+    c.Log().WarnOnErr("got error on saving call rate", err, zap.Int64("CallID", req.CallID))
+
+    // send the request to server
+    c.SDK().QueueCtrl().EnqueueCommand(da)
+}
@@ -0,0 +1,110 @@
+package call
+
+import (
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "go.uber.org/zap"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+// recordStarted adds the call to the call history. The team of the call is kept until the call ends, since the
+// later state transitions do not carry it.
+func (c *call) recordStarted(teamID, callID int64, peer *msg.InputPeer, participants []int64, incoming bool) {
+    if callID == 0 || callID == TempCallID || peer == nil {
+        return
+    }
+
+    c.mu.Lock()
+    c.historyTeams[callID] = teamID
+    c.mu.Unlock()
+
+    err := repo.Calls.Start(&repo.CallRecord{
+        CallID:       callID,
+        TeamID:       teamID,
+        PeerID:       peer.ID,
+        PeerType:     int32(peer.Type),
+        Participants: participants,
+        Incoming:     incoming,
+        Group:        peer.Type == msg.PeerType_PeerGroup,
+        StartedOn:    domain.Now().Unix(),
+    })
+    c.Log().WarnOnErr("got error on recording call start", err, zap.Int64("CallID", callID))
+}
+
+func (c *call) recordParticipants(callID int64, userIDs ...int64) {
+    teamID, ok := c.historyTeamID(callID)
+    if !ok {
+        return
+    }
+    err := repo.Calls.AddParticipants(teamID, callID, userIDs...)
+    c.Log().WarnOnErr("got error on recording call participants", err, zap.Int64("CallID", callID))
+}
+
+func (c *call) recordConnected(callID int64) {
+    teamID, ok := c.historyTeamID(callID)
+    if !ok {
+        return
+    }
+    err := repo.Calls.SetConnected(teamID, callID, domain.Now().Unix())
+    c.Log().WarnOnErr("got error on recording call connect", err, zap.Int64("CallID", callID))
+}
+
+// recordEnded sets the end of the call in the call history, the first reason which is recorded is kept.
+func (c *call) recordEnded(callID int64, reason msg.DiscardReason) {
+    teamID, ok := c.historyTeamID(callID)
+    if !ok {
+        return
+    }
+
+    c.mu.Lock()
+    delete(c.historyTeams, callID)
+    c.mu.Unlock()
+
+    err := repo.Calls.SetEnded(teamID, callID, domain.Now().Unix(), reason)
+    c.Log().WarnOnErr("got error on recording call end", err, zap.Int64("CallID", callID))
+}
+
+func (c *call) historyTeamID(callID int64) (int64, bool) {
+    c.mu.RLock()
+    teamID, ok := c.historyTeams[callID]
+    c.mu.RUnlock()
+    return teamID, ok
+}
+
+// initiatedByOthers returns true if the initiator of the call which is joined is not us. The direction of the
+// call is decided by its initiator, so joining a call which is started by us again is not an incoming call.
+func (c *call) initiatedByOthers(participants []*msg.PhoneParticipant) bool {
+    for _, p := range participants {
+        if p.Initiator && p.Peer != nil {
+            return p.Peer.UserID != c.userID
+        }
+    }
+    return true
+}
+
+func inputUserIDs(inputUsers []*msg.InputUser) []int64 {
+    userIDs := make([]int64, 0, len(inputUsers))
+    for _, u := range inputUsers {
+        userIDs = append(userIDs, u.UserID)
+    }
+    return userIDs
+}
+
+func phoneParticipantIDs(participants []*msg.PhoneParticipant) []int64 {
+    userIDs := make([]int64, 0, len(participants))
+    for _, p := range participants {
+        if p.Peer != nil {
+            userIDs = append(userIDs, p.Peer.UserID)
+        }
+    }
+    return userIDs
+}
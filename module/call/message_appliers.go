@@ -0,0 +1,34 @@
+package call
+
+import (
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/rony"
+    "go.uber.org/zap"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func (c *call) phoneCallsMany(e *rony.MessageEnvelope) {
+    x := new(msg.PhoneCallsMany)
+    err := x.Unmarshal(e.Message)
+    if err != nil {
+        c.Log().Error("couldn't unmarshal PhoneCallsMany", zap.Error(err))
+        return
+    }
+    c.Log().Debug("applies PhoneCallsMany",
+        zap.Int("Calls", len(x.PhoneCalls)),
+    )
+
+    _ = repo.Users.Save(x.Users...)
+    _ = repo.Groups.Save(x.Groups...)
+    err = repo.Calls.SaveHistory(x.PhoneCalls...)
+    c.Log().WarnOnErr("got error on saving call history", err)
+}
@@ -82,6 +82,9 @@ func (c *call) destroy(callID int64) {
     c.activeCallID = 0
     c.peer = nil
     c.mu.Unlock()
+
+    _ = c.fire(callID, CallEventDestroy)
+    c.recordEnded(callID, msg.DiscardReason_DiscardReasonHangup)
 }
 
 func (c *call) areAllAudio() (ok bool, err error) {
@@ -182,13 +185,17 @@ func (c *call) trackUpdate(connId int32, streamID string) (err error) {
     }
 
     c.mu.Lock()
-    if _, ok := c.callDuration[c.activeCallID]; !ok {
+    _, started := c.callDuration[c.activeCallID]
+    if !started {
         c.callDuration[c.activeCallID] = &Duration{
             Start: time.Now().Unix(),
             Stop:  0,
         }
     }
     c.mu.Unlock()
+    if !started {
+        c.recordConnected(c.activeCallID)
+    }
 
     conn, hasConn := c.peerConnections[connId]
     if !hasConn {
@@ -230,6 +237,24 @@ func (c *call) mediaSettingsChange(mediaSettings *msg.CallMediaSettings) (err er
 }
 
 func (c *call) start(peer *msg.InputPeer, participants []*msg.InputUser, video bool, callID int64) (id int64, err error) {
+    // A new call is tracked by TempCallID until the server returns its id
+    machineID := callID
+    if callID == 0 {
+        machineID = TempCallID
+    }
+    if c.callState(machineID) == CallStateEnded {
+        c.resetMachine(machineID)
+    }
+    err = c.fire(machineID, CallEventDial)
+    if err != nil {
+        return
+    }
+    defer func() {
+        if err != nil {
+            _ = c.fire(machineID, CallEventDestroy)
+        }
+    }()
+
     c.peer = peer
     initRes, err := c.apiInit(peer, callID)
     if err != nil {
@@ -249,6 +274,7 @@ func (c *call) start(peer *msg.InputPeer, participants []*msg.InputUser, video b
     }
 
     c.iceServer = initRes.IceServers
+    incoming := false
     if callID != 0 {
         c.activeCallID = callID
         var joinRes *msg.PhoneParticipants
@@ -256,6 +282,7 @@ func (c *call) start(peer *msg.InputPeer, participants []*msg.InputUser, video b
         if err != nil {
             return
         }
+        incoming = c.initiatedByOthers(joinRes.Participants)
 
         c.initParticipants(c.activeCallID, joinRes.Participants, true)
         _, err = c.initManyConnections(peer, c.activeCallID, false, video, nil)
@@ -273,8 +300,10 @@ func (c *call) start(peer *msg.InputPeer, participants []*msg.InputUser, video b
         }
 
         c.swapTempInfo(c.activeCallID)
+        c.moveMachine(TempCallID, c.activeCallID)
     }
     id = c.activeCallID
+    c.recordStarted(c.teamInput.teamID, id, peer, inputUserIDs(participants), incoming)
     return
 }
 
@@ -304,6 +333,18 @@ func (c *call) accept(callID int64, video bool) (err error) {
         return
     }
 
+    // Only a ringing call could be accepted, this prevents accepting a call twice
+    err = c.fire(callID, CallEventAccept)
+    if err != nil {
+        return
+    }
+
+    return c.acceptRequests(callID, video)
+}
+
+// acceptRequests answers all the requests of the call, it is also used to answer the requests which are received
+// after the call has been accepted.
+func (c *call) acceptRequests(callID int64, video bool) (err error) {
     initRes, err := c.apiInit(c.peer, callID)
     if err != nil {
         return
@@ -389,6 +430,7 @@ func (c *call) reject(callID int64, duration int32, reason msg.DiscardReason, ta
     }
 
     c.pushToRejectedCallIds(callID)
+    _ = c.fire(callID, CallEventReject)
 
     c.mu.Lock()
     if d, ok := c.callDuration[callID]; ok {
@@ -397,6 +439,7 @@ func (c *call) reject(callID int64, duration int32, reason msg.DiscardReason, ta
         }
     }
     c.mu.Unlock()
+    c.recordEnded(callID, reason)
 
     if force {
         _ = repo.Dialogs.UpdateCallEnded(&msg.UpdatePhoneCallEnded{
@@ -1493,6 +1536,8 @@ func (c *call) setCallInfoDialed(callID int64) {
 
 func (c *call) callBusy(in *UpdatePhoneCall) {
     inputPeer := c.getInputUserFromUpdate(in)
+    c.recordStarted(in.TeamID, in.CallID, inputPeer, []int64{in.UserID}, true)
+    c.recordEnded(in.CallID, msg.DiscardReason_DiscardReasonBusy)
 
     _, _ = c.apiReject(inputPeer, in.CallID, msg.DiscardReason_DiscardReasonBusy, 0)
 }
@@ -1609,6 +1654,7 @@ func (c *call) removeParticipant(userID int64, callID *int64) bool {
     if !valid {
         return false
     }
+    _ = c.fireParticipant(activeCallID, connId, ParticipantEventLeft)
 
     c.mu.RLock()
     _, hasConn := c.peerConnections[connId]
@@ -1710,10 +1756,20 @@ func (c *call) callRequested(in *UpdatePhoneCall) {
         return
     }
 
+    // Glare: we are calling someone while another call is requested
+    if c.isDialing(in.CallID) {
+        c.callBusy(in)
+        return
+    }
+
     if c.isCallRejected(in.CallID) {
         return
     }
 
+    if c.fire(in.CallID, CallEventRequested) != nil {
+        return
+    }
+
     // (Disabled due to client state problem)
     // Send ack update so callee ringing indicator activates
     // c.sendCallAck(in)
@@ -1724,6 +1780,8 @@ func (c *call) callRequested(in *UpdatePhoneCall) {
         c.peer = c.getInputUserFromUpdate(in)
         c.mu.Unlock()
 
+        c.recordStarted(in.TeamID, in.CallID, c.peer, phoneParticipantIDs(data.Participants), true)
+
         update := msg.CallUpdateCallRequested{
             Peer:   c.peer,
             CallID: in.CallID,
@@ -1740,7 +1798,7 @@ func (c *call) callRequested(in *UpdatePhoneCall) {
             if streamState, err := c.getMediaSettings(); err == nil {
                 video = streamState.Video
             }
-            _ = c.accept(c.activeCallID, video)
+            _ = c.acceptRequests(c.activeCallID, video)
         }
     }
 }
@@ -1772,10 +1830,15 @@ func (c *call) callAccepted(in *UpdatePhoneCall) {
         Type: data.Type,
     }
 
+    // The answer is applied only if the call accepts it, i.e. a late accept of an ended call is ignored
+    if c.fire(in.CallID, CallEventAccepted) != nil {
+        return
+    }
     sdpOK := c.CallbackSetAnswerSDP(connId, answerSdp)
     if !sdpOK {
         return
     }
+    _ = c.fireParticipant(in.CallID, connId, ParticipantEventAccepted)
 
     pc.mu.Lock()
     pc.Accepted = true
@@ -1821,6 +1884,8 @@ func (c *call) callDiscarded(in *UpdatePhoneCall) {
 
     data := in.Data.(*msg.PhoneActionDiscarded)
     if in.PeerType == int32(msg.PeerType_PeerUser) || data.Terminate {
+        _ = c.fire(in.CallID, CallEventDiscarded)
+        c.recordEnded(in.CallID, data.Reason)
         if c.activeCallID != 0 {
             update := msg.CallUpdateCallRejected{
                 CallID: in.CallID,
@@ -1834,6 +1899,8 @@ func (c *call) callDiscarded(in *UpdatePhoneCall) {
         c.destroy(in.CallID)
     } else {
         if c.removeParticipant(in.UserID, &in.CallID) {
+            _ = c.fire(in.CallID, CallEventDiscarded)
+            c.recordEnded(in.CallID, data.Reason)
             if c.activeCallID != 0 {
                 update := msg.CallUpdateCallRejected{
                     CallID: in.CallID,
@@ -2004,6 +2071,8 @@ func (c *call) participantAdded(in *UpdatePhoneCall) {
         userIDs = append(userIDs, participant.Peer.UserID)
     }
 
+    c.recordParticipants(in.CallID, userIDs...)
+
     if isNew {
         update := msg.CallUpdateParticipantJoined{
             UserIDs: userIDs,
@@ -2163,6 +2232,7 @@ func (c *call) checkCallTimeout(connId int32) {
     }
 
     if len(info.acceptedParticipants) == 0 {
+        _ = c.fire(c.activeCallID, CallEventTimeout)
         _ = c.reject(c.activeCallID, 0, msg.DiscardReason_DiscardReasonMissed, nil, false)
         update := msg.CallUpdateCallTimeout{}
         updateData, uErr := update.Marshal()
@@ -2212,6 +2282,15 @@ func (c *call) changeStatus(connId int32, status msg.CallStatus) {
     }
     conn.status = status
 
+    switch status {
+    case msg.CallStatus_Connected:
+        _ = c.fire(c.activeCallID, CallEventConnected)
+        _ = c.fireParticipant(c.activeCallID, connId, ParticipantEventConnected)
+    case msg.CallStatus_Reconnecting:
+        _ = c.fire(c.activeCallID, CallEventDisconnected)
+        _ = c.fireParticipant(c.activeCallID, connId, ParticipantEventDisconnected)
+    }
+
     update := msg.CallUpdateStatusChanged{
         CallID: c.activeCallID,
         ConnId: connId,
@@ -2317,6 +2396,7 @@ func (c *call) updatePhoneCallEnded(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelop
     _ = repo.Dialogs.UpdateCallEnded(x)
 
     if c.activeCallID != 0 && c.peer != nil && c.peer.ID == x.Peer.ID && int32(c.peer.Type) == x.Peer.Type {
+        c.recordEnded(c.activeCallID, msg.DiscardReason_DiscardReasonHangup)
         update := msg.CallUpdateCallRejected{
             CallID: c.activeCallID,
             Reason: msg.DiscardReason_DiscardReasonHangup,
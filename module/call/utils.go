@@ -33,6 +33,7 @@ var (
     ErrInvalidRequest             = errors.New("invalid request")
     ErrInvalidResponse            = errors.New("invalid response")
     ErrCallbacksAreNotInitialized = errors.New("callbacks are not initialized")
+    ErrInvalidTransition          = errors.New("invalid transition")
 )
 
 type UpdatePhoneCall struct {
@@ -77,6 +78,7 @@ type Info struct {
     requests               []*UpdatePhoneCall
     iceServer              *msg.IceServer
     requestMap             map[int64]struct{}
+    machines               map[int32]*ParticipantMachine
     mu                     *sync.RWMutex
 }
 
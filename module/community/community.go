@@ -0,0 +1,48 @@
+package community
+
+import (
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/request"
+    "github.com/ronaksoft/river-sdk/module"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+type community struct {
+    module.Base
+}
+
+func New() *community {
+    r := &community{}
+    r.RegisterHandlers(
+        map[int64]request.LocalHandler{
+            msg.C_CommunityGetUpdates:  r.communityGetUpdates,
+            msg.C_CommunitySendMessage: r.communitySendMessage,
+        },
+    )
+    r.RegisterUpdateAppliers(
+        map[int64]domain.UpdateApplier{
+            msg.C_UpdateCommunityMessage:    r.updateCommunityMessage,
+            msg.C_UpdateCommunityReadOutbox: r.updateCommunityReadOutbox,
+            msg.C_UpdateCommunityTyping:     r.updateCommunityTyping,
+        },
+    )
+    r.RegisterMessageAppliers(
+        map[int64]domain.MessageApplier{
+            msg.C_CommunityUpdateContainer: r.communityUpdateContainer,
+        },
+    )
+    return r
+}
+
+func (r *community) Name() string {
+    return module.Community
+}
@@ -0,0 +1,72 @@
+package community
+
+import (
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/river-sdk/internal/request"
+    "github.com/ronaksoft/rony"
+    "github.com/ronaksoft/rony/errors"
+    "go.uber.org/zap"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func (r *community) communitySendMessage(da request.Callback) {
+    req := &msg.CommunitySendMessage{}
+    if err := da.RequestData(req); err != nil {
+        return
+    }
+
+    // SenderMsgID is echoed back by UpdateCommunityMessage, hence we use it to track the pending message
+    if req.RandomID == 0 {
+        req.RandomID = domain.SequentialUniqueID()
+    }
+    if req.SenderMsgID == 0 {
+        req.SenderMsgID = domain.SequentialUniqueID()
+    }
+    req.SenderID = r.SDK().GetConnInfo().PickupUserID()
+
+    err := repo.Community.SavePending(da.TeamID(), req)
+    if err != nil {
+        da.Response(rony.C_Error, errors.New("00", err.Error()))
+        return
+    }
+    r.Log().Debug("saved community pending message",
+        zap.Int64("TeamID", da.TeamID()),
+        zap.Int64("SenderMsgID", req.SenderMsgID),
+    )
+
+    teamID := da.TeamID()
+    da.SetPreComplete(func(m *rony.MessageEnvelope) {
+        if m.Constructor != rony.C_Error {
+            return
+        }
+        // The server has rejected the message, it will never be echoed back
+        r.Log().Warn("got error on CommunitySendMessage", zap.Error(domain.ParseServerError(m.Message)))
+        _ = repo.Community.DeletePending(teamID, req.SenderMsgID)
+    })
+    da.Envelope().Message, _ = req.Marshal()
+    r.SDK().QueueCtrl().EnqueueCommand(da)
+}
+
+func (r *community) communityGetUpdates(da request.Callback) {
+    req := &msg.CommunityGetUpdates{}
+    if err := da.RequestData(req); err != nil {
+        return
+    }
+
+    // If OffsetID is not set, we continue from the last update we have received
+    if req.OffsetID == 0 {
+        req.OffsetID, _ = repo.Community.GetOffset(da.TeamID())
+    }
+    da.Envelope().Message, _ = req.Marshal()
+    r.SDK().QueueCtrl().EnqueueCommand(da)
+}
@@ -0,0 +1,73 @@
+package community
+
+import (
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/rony"
+    "github.com/ronaksoft/rony/registry"
+    "go.uber.org/zap"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func (r *community) communityUpdateContainer(e *rony.MessageEnvelope) {
+    u := &msg.CommunityUpdateContainer{}
+    err := u.Unmarshal(e.Message)
+    if err != nil {
+        r.Log().Error("couldn't unmarshal CommunityUpdateContainer", zap.Error(err))
+        return
+    }
+
+    r.Log().Debug("applies CommunityUpdateContainer",
+        zap.Int("Count", len(u.Updates)),
+        zap.Bool("Empty", u.Empty),
+    )
+
+    r.applyUpdates(domain.GetTeamID(e), u)
+}
+
+// applyUpdates applies the community updates and advances the offset of the team. It returns the updates which
+// must be delivered to the UI.
+func (r *community) applyUpdates(teamID int64, uc *msg.CommunityUpdateContainer) []*msg.UpdateEnvelope {
+    var (
+        offsetID int64
+        updates  []*msg.UpdateEnvelope
+    )
+    appliers := r.UpdateAppliers()
+    for _, cu := range uc.Updates {
+        ue := &msg.UpdateEnvelope{
+            Constructor: cu.Constructor,
+            Update:      cu.Update,
+        }
+        if applier, ok := appliers[cu.Constructor]; ok {
+            res, err := applier(ue)
+            if err != nil {
+                r.Log().Warn("got error on community update applier",
+                    zap.String("C", registry.ConstructorName(cu.Constructor)),
+                    zap.Int64("OffsetID", cu.OffsetID),
+                    zap.Error(err),
+                )
+            }
+            updates = append(updates, res...)
+        } else {
+            updates = append(updates, ue)
+        }
+        if cu.OffsetID > offsetID {
+            offsetID = cu.OffsetID
+        }
+    }
+
+    if offsetID != 0 {
+        err := repo.Community.SetOffset(teamID, offsetID)
+        r.Log().WarnOnErr("got error on saving community offset", err, zap.Int64("OffsetID", offsetID))
+    }
+    return updates
+}
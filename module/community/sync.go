@@ -0,0 +1,84 @@
+package community
+
+import (
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/river-sdk/internal/request"
+    "github.com/ronaksoft/river-sdk/internal/uiexec"
+    "github.com/ronaksoft/rony"
+    "github.com/ronaksoft/rony/registry"
+    "go.uber.org/zap"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+const syncSizeLimit = 100
+
+// Sync fills the gap of the community updates of the team. It calls CommunityGetUpdates from the last received
+// offset until the server has no more updates, and delivers the updates to the UI.
+func (r *community) Sync(teamID int64, teamAccess uint64) {
+    for {
+        offsetID, _ := repo.Community.GetOffset(teamID)
+        uc := r.getUpdates(teamID, teamAccess, offsetID)
+        if uc == nil || uc.Empty || len(uc.Updates) == 0 {
+            return
+        }
+
+        // The container has already been applied by the message applier, we just pass the updates to the UI
+        updates := make([]*msg.UpdateEnvelope, 0, len(uc.Updates))
+        for _, cu := range uc.Updates {
+            updates = append(updates, &msg.UpdateEnvelope{
+                Constructor: cu.Constructor,
+                Update:      cu.Update,
+            })
+        }
+        uiexec.ExecUpdate(msg.C_UpdateContainer, &msg.UpdateContainer{
+            Length:  int32(len(updates)),
+            Updates: updates,
+        })
+
+        newOffsetID, _ := repo.Community.GetOffset(teamID)
+        if newOffsetID <= offsetID {
+            return
+        }
+    }
+}
+
+func (r *community) getUpdates(teamID int64, teamAccess uint64, offsetID int64) *msg.CommunityUpdateContainer {
+    var uc *msg.CommunityUpdateContainer
+    r.SDK().NetCtrl().WebsocketCommand(
+        request.NewCallback(
+            teamID, teamAccess, domain.NextRequestID(), msg.C_CommunityGetUpdates,
+            &msg.CommunityGetUpdates{
+                OffsetID:  offsetID,
+                SizeLimit: syncSizeLimit,
+            },
+            func() {
+                r.Log().Warn("got timeout on CommunityGetUpdates", zap.Int64("TeamID", teamID))
+            },
+            func(m *rony.MessageEnvelope) {
+                switch m.Constructor {
+                case msg.C_CommunityUpdateContainer:
+                    x := &msg.CommunityUpdateContainer{}
+                    if err := x.Unmarshal(m.Message); err == nil {
+                        uc = x
+                    }
+                case rony.C_Error:
+                    r.Log().Warn("got error on CommunityGetUpdates", zap.Error(domain.ParseServerError(m.Message)))
+                default:
+                    r.Log().Warn("received unexpected response", zap.String("C", registry.ConstructorName(m.Constructor)))
+                }
+            }, nil,
+            false, request.SkipFlusher, 0,
+        ),
+    )
+    return uc
+}
@@ -0,0 +1,90 @@
+package community
+
+import (
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "go.uber.org/zap"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func (r *community) updateCommunityMessage(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
+    x := &msg.UpdateCommunityMessage{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return nil, err
+    }
+
+    r.Log().Debug("applies UpdateCommunityMessage",
+        zap.Int64("TeamID", x.TeamID),
+        zap.Uint64("GlobalMsgID", x.GlobalMsgID),
+    )
+
+    pending, err := repo.Community.SaveMessage(r.SDK().GetConnInfo().PickupUserID(), x)
+    if err != nil {
+        return nil, err
+    }
+    if pending {
+        r.Log().Debug("community pending message delivered", zap.Int64("SenderMsgID", x.SenderMsgID))
+    }
+
+    // Live updates are not wrapped by CommunityUpdateEnvelope, so the GlobalMsgID which is the offset of the
+    // message in the team is used to advance our offset, otherwise the next Sync fetches them again. We only
+    // advance if it directly follows our offset, otherwise there is a gap which must be filled by Sync.
+    _, err = repo.Community.AdvanceOffset(x.TeamID, int64(x.GlobalMsgID))
+    if err != nil {
+        return nil, err
+    }
+
+    return []*msg.UpdateEnvelope{u}, nil
+}
+
+func (r *community) updateCommunityReadOutbox(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
+    x := &msg.UpdateCommunityReadOutbox{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return nil, err
+    }
+
+    r.Log().Debug("applies UpdateCommunityReadOutbox",
+        zap.Int64("TeamID", x.TeamID),
+        zap.Int64("SenderMsgID", x.SenderMsgID),
+    )
+
+    // We only keep the read state of the messages which we have sent
+    if x.SenderID != r.SDK().GetConnInfo().PickupUserID() {
+        return []*msg.UpdateEnvelope{u}, nil
+    }
+    updated, err := repo.Community.UpdateReadOutbox(x.TeamID, x.ReceiverID, x.SenderMsgID)
+    if err != nil {
+        return nil, err
+    }
+    if !updated {
+        return nil, nil
+    }
+
+    return []*msg.UpdateEnvelope{u}, nil
+}
+
+func (r *community) updateCommunityTyping(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
+    x := &msg.UpdateCommunityTyping{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return nil, err
+    }
+
+    r.Log().Debug("applies UpdateCommunityTyping",
+        zap.Int64("TeamID", x.TeamID),
+        zap.Int64("SenderID", x.SenderID),
+    )
+
+    // Typing is not stored, we just pass it to the UI
+    return []*msg.UpdateEnvelope{u}, nil
+}
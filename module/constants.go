@@ -13,6 +13,8 @@ const (
 	Account      = "ACCOUNT"
 	Auth         = "AUTH"
 	Bot          = "BOT"
+	Calendar     = "CALENDAR"
+	Community    = "COMMUNITY"
 	Call         = "CALL"
 	Contact      = "CONTACT"
 	Gif          = "GIF"
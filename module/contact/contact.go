@@ -25,12 +25,15 @@ func New() *contact {
     r.RegisterHandlers(
         map[int64]request.LocalHandler{
             msg.C_ContactsAdd:          r.contactsAdd,
+            msg.C_ContactsBlock:        r.contactsBlock,
             msg.C_ContactsDelete:       r.contactsDelete,
             msg.C_ContactsDeleteAll:    r.contactsDeleteAll,
             msg.C_ContactsGet:          r.contactsGet,
+            msg.C_ContactsGetBlocked:   r.contactsGetBlocked,
             msg.C_ContactsGetTopPeers:  r.contactsGetTopPeers,
             msg.C_ContactsImport:       r.contactsImport,
             msg.C_ContactsResetTopPeer: r.contactsResetTopPeer,
+            msg.C_ContactsUnblock:      r.contactsUnblock,
             msg.C_ClientContactSearch:  r.clientContactSearch,
         },
     )
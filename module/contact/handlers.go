@@ -268,3 +268,93 @@ func (r *contact) clientContactSearch(da request.Callback) {
 
     da.Response(msg.C_UsersMany, users)
 }
+
+func (r *contact) contactsGetBlocked(da request.Callback) {
+    req := &msg.ContactsGetBlocked{}
+    if err := da.RequestData(req); err != nil {
+        return
+    }
+
+    // We serve the list locally once it has been synced with the server, or if we are offline
+    synced, _ := repo.System.LoadInt(domain.SkBlockedSynced)
+    if synced == 0 && r.SDK().NetCtrl().Connected() {
+        da.SetPreComplete(func(m *rony.MessageEnvelope) {
+            if m.Constructor != msg.C_BlockedContactsMany {
+                return
+            }
+            x := &msg.BlockedContactsMany{}
+            if err := x.Unmarshal(m.Message); err != nil {
+                return
+            }
+            _ = repo.Users.Save(x.Users...)
+
+            // If we got the whole list, then we replace our list and serve the next requests locally
+            complete := req.Offset == 0 && int32(len(x.Contacts)) >= x.Total
+            err := repo.Users.SaveBlocked(complete, x.Contacts...)
+            if err != nil {
+                r.Log().Warn("got error on saving blocked contacts", zap.Error(err))
+                return
+            }
+            if complete {
+                _ = repo.System.SaveInt(domain.SkBlockedSynced, 1)
+            }
+        })
+        r.SDK().QueueCtrl().EnqueueCommand(da)
+        return
+    }
+
+    res := &msg.BlockedContactsMany{}
+    res.Contacts, res.Total = repo.Users.GetBlocked(req.Offset, req.Limit)
+    userIDs := make([]int64, 0, len(res.Contacts))
+    for _, bc := range res.Contacts {
+        userIDs = append(userIDs, bc.UserID)
+    }
+    res.Users, _ = repo.Users.GetMany(userIDs)
+
+    r.Log().Info("returned data locally, ContactsGetBlocked",
+        zap.Int("Contacts", len(res.Contacts)),
+        zap.Int32("Total", res.Total),
+    )
+    da.Response(msg.C_BlockedContactsMany, res)
+}
+
+func (r *contact) contactsBlock(da request.Callback) {
+    req := &msg.ContactsBlock{}
+    if err := da.RequestData(req); err != nil {
+        return
+    }
+
+    r.toggleBlocked(da, req.User.UserID, true)
+}
+
+func (r *contact) contactsUnblock(da request.Callback) {
+    req := &msg.ContactsUnblock{}
+    if err := da.RequestData(req); err != nil {
+        return
+    }
+
+    r.toggleBlocked(da, req.User.UserID, false)
+}
+
+// toggleBlocked applies the block optimistically and rolls it back if the server rejects it. The server confirms
+// it by UpdateUserBlocked.
+func (r *contact) toggleBlocked(da request.Callback, userID int64, blocked bool) {
+    err := repo.Users.UpdateBlocked(userID, blocked)
+    if err != nil {
+        da.Response(rony.C_Error, errors.New("00", err.Error()))
+        return
+    }
+
+    da.SetPreComplete(func(m *rony.MessageEnvelope) {
+        if m.Constructor != rony.C_Error {
+            return
+        }
+        r.Log().Warn("got error on toggling user block",
+            zap.Int64("UserID", userID),
+            zap.Bool("Blocked", blocked),
+            zap.Error(domain.ParseServerError(m.Message)),
+        )
+        _ = repo.Users.UpdateBlocked(userID, !blocked)
+    })
+    r.SDK().QueueCtrl().EnqueueCommand(da)
+}
@@ -1,7 +1,10 @@
 package group
 
 import (
+    "time"
+
     "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/clientmsg"
     "github.com/ronaksoft/river-sdk/internal/domain"
     "github.com/ronaksoft/river-sdk/internal/request"
     "github.com/ronaksoft/river-sdk/module"
@@ -16,6 +19,12 @@ import (
    Copyright Ronak Software Group 2020
 */
 
+const (
+    // readHistoryStatsTTL is how long the cached read history stats could be used, the cache is kept up to date by
+    // the updates anyway, hence it only limits the effect of the missed updates.
+    readHistoryStatsTTL = 5 * time.Minute
+)
+
 type group struct {
     module.Base
 }
@@ -24,21 +33,25 @@ func New() *group {
     r := &group{}
     r.RegisterHandlers(
         map[int64]request.LocalHandler{
-            msg.C_GroupsAddUser:      r.groupAddUser,
-            msg.C_GroupsDeleteUser:   r.groupDeleteUser,
-            msg.C_GroupsEditTitle:    r.groupsEditTitle,
-            msg.C_GroupsGetFull:      r.groupsGetFull,
-            msg.C_GroupsRemovePhoto:  r.groupRemovePhoto,
-            msg.C_GroupsToggleAdmins: r.groupToggleAdmin,
-            msg.C_GroupsUpdateAdmin:  r.groupUpdateAdmin,
+            msg.C_GroupsAddUser:             r.groupAddUser,
+            msg.C_GroupsDeleteUser:          r.groupDeleteUser,
+            msg.C_GroupsEditTitle:           r.groupsEditTitle,
+            msg.C_GroupsGetFull:             r.groupsGetFull,
+            msg.C_GroupsGetReadHistoryStats: r.groupsGetReadHistoryStats,
+            msg.C_GroupsRemovePhoto:         r.groupRemovePhoto,
+            msg.C_GroupsToggleAdmins:        r.groupToggleAdmin,
+            msg.C_GroupsUpdateAdmin:         r.groupUpdateAdmin,
+            clientmsg.C_ClientGetReadBy:     r.clientGetReadBy,
         },
     )
     r.RegisterUpdateAppliers(
         map[int64]domain.UpdateApplier{
-            msg.C_UpdateGroupAdmins:           r.updateGroupAdmins,
-            msg.C_UpdateGroupAdminOnly:        r.updateGroupAdminOnly,
-            msg.C_UpdateGroupParticipantAdmin: r.updateGroupParticipantAdmin,
-            msg.C_UpdateGroupPhoto:            r.updateGroupPhoto,
+            msg.C_UpdateGroupAdmins:             r.updateGroupAdmins,
+            msg.C_UpdateGroupAdminOnly:          r.updateGroupAdminOnly,
+            msg.C_UpdateGroupParticipantAdmin:   r.updateGroupParticipantAdmin,
+            msg.C_UpdateGroupParticipantAdd:     r.updateGroupParticipantAdd,
+            msg.C_UpdateGroupParticipantDeleted: r.updateGroupParticipantDeleted,
+            msg.C_UpdateGroupPhoto:              r.updateGroupPhoto,
         },
     )
     r.RegisterMessageAppliers(
@@ -2,9 +2,12 @@ package group
 
 import (
     "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/clientmsg"
     "github.com/ronaksoft/river-sdk/internal/domain"
     "github.com/ronaksoft/river-sdk/internal/repo"
     "github.com/ronaksoft/river-sdk/internal/request"
+    "github.com/ronaksoft/rony"
+    "github.com/ronaksoft/rony/errors"
     "go.uber.org/zap"
 )
 
@@ -108,6 +111,107 @@ func (r *group) groupsGetFull(da request.Callback) {
     da.Response(msg.C_GroupFull, res)
 }
 
+// groupsGetReadHistoryStats answers from the cached stats if they are fresh, the cache is updated when
+// UpdateReadHistoryOutbox is received for the group.
+func (r *group) groupsGetReadHistoryStats(da request.Callback) {
+    req := &msg.GroupsGetReadHistoryStats{}
+    if err := da.RequestData(req); err != nil {
+        return
+    }
+
+    stats, err := repo.Groups.GetReadHistoryStats(req.GroupID, readHistoryStatsTTL)
+    if err == nil {
+        userIDs := make([]int64, 0, len(stats))
+        for _, s := range stats {
+            userIDs = append(userIDs, s.UserID)
+        }
+        users, _ := repo.Users.GetMany(userIDs)
+        if len(users) == len(userIDs) {
+            da.Response(msg.C_GroupsHistoryStats, &msg.GroupsHistoryStats{
+                Stats: stats,
+                Users: users,
+                Empty: len(stats) == 0,
+            })
+            return
+        }
+    }
+
+    da.SetPreComplete(func(m *rony.MessageEnvelope) {
+        if m.Constructor != msg.C_GroupsHistoryStats {
+            return
+        }
+        x := &msg.GroupsHistoryStats{}
+        if err := x.Unmarshal(m.Message); err != nil {
+            return
+        }
+        r.saveReadHistoryStats(req.GroupID, x)
+    })
+    r.SDK().QueueCtrl().EnqueueCommand(da)
+}
+
+func (r *group) saveReadHistoryStats(groupID int64, x *msg.GroupsHistoryStats) {
+    err := repo.Users.Save(x.Users...)
+    r.Log().WarnOnErr("got error on saving users of read history stats", err)
+    err = repo.Groups.SaveReadHistoryStats(groupID, x.Stats...)
+    r.Log().WarnOnErr("got error on saving read history stats", err, zap.Int64("GroupID", groupID))
+}
+
+// clientGetReadBy returns the members who have read the message. It answers from the cached read history stats if
+// they are fresh, otherwise the stats are received from the server first.
+func (r *group) clientGetReadBy(da request.Callback) {
+    req := &clientmsg.ClientGetReadBy{}
+    if err := da.RequestData(req); err != nil {
+        return
+    }
+
+    if r.respondReadBy(da, req) {
+        return
+    }
+
+    r.SDK().QueueCtrl().EnqueueCommand(
+        request.NewCallback(
+            da.TeamID(), da.TeamAccess(), domain.NextRequestID(), msg.C_GroupsGetReadHistoryStats,
+            &msg.GroupsGetReadHistoryStats{GroupID: req.GroupID},
+            da.OnTimeout,
+            func(m *rony.MessageEnvelope) {
+                switch m.Constructor {
+                case msg.C_GroupsHistoryStats:
+                    x := &msg.GroupsHistoryStats{}
+                    if err := x.Unmarshal(m.Message); err != nil {
+                        da.Response(rony.C_Error, errors.New("00", err.Error()))
+                        return
+                    }
+                    r.saveReadHistoryStats(req.GroupID, x)
+                    if !r.respondReadBy(da, req) {
+                        da.Response(rony.C_Error, errors.New("00", domain.ErrNotFound.Error()))
+                    }
+                default:
+                    da.OnComplete(m)
+                }
+            },
+            nil, false, da.Flags(), da.Timeout(),
+        ),
+    )
+}
+
+// respondReadBy responds the request from the cached stats, it returns false if the stats or the users are not
+// cached.
+func (r *group) respondReadBy(da request.Callback, req *clientmsg.ClientGetReadBy) bool {
+    userIDs, ok := repo.Groups.ReadBy(req.GroupID, req.MessageID, readHistoryStatsTTL)
+    if !ok {
+        return false
+    }
+    users, _ := repo.Users.GetMany(userIDs)
+    if len(users) != len(userIDs) {
+        return false
+    }
+    da.Response(msg.C_UsersMany, &msg.UsersMany{
+        Users: users,
+        Empty: len(users) == 0,
+    })
+    return true
+}
+
 func (r *group) groupUpdateAdmin(da request.Callback) {
     req := &msg.GroupsUpdateAdmin{}
     if err := da.RequestData(req); err != nil {
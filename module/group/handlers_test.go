@@ -0,0 +1,84 @@
+package group
+
+import (
+    "testing"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/clientmsg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/river-sdk/internal/request"
+    "github.com/ronaksoft/river-sdk/internal/testenv"
+    "github.com/ronaksoft/river-sdk/module"
+    "github.com/ronaksoft/rony"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func init() {
+    repo.MustInit("./_data", false)
+    testenv.Log().SetLogLevel(2)
+}
+
+// testSDK does not provide any controller, hence the requests must be answered locally
+type testSDK struct {
+    module.SDK
+}
+
+func TestReadBy(t *testing.T) {
+    r := New()
+    r.Init(&testSDK{}, testenv.Log())
+    Convey("Read By", t, func(c C) {
+        groupID := domain.RandomInt63()
+        users := []*msg.User{
+            {ID: domain.RandomInt63(), FirstName: "First"},
+            {ID: domain.RandomInt63(), FirstName: "Second"},
+        }
+        c.So(repo.Users.Save(users...), ShouldBeNil)
+        c.So(repo.Groups.SaveReadHistoryStats(groupID,
+            &msg.ReadHistoryStat{UserID: users[0].ID, MessageID: 10},
+            &msg.ReadHistoryStat{UserID: users[1].ID, MessageID: 5},
+        ), ShouldBeNil)
+
+        readBy := func(messageID int64) *msg.UsersMany {
+            var res *rony.MessageEnvelope
+            r.clientGetReadBy(
+                request.NewCallback(
+                    0, 0, domain.NextRequestID(), clientmsg.C_ClientGetReadBy,
+                    &clientmsg.ClientGetReadBy{GroupID: groupID, MessageID: messageID},
+                    nil,
+                    func(m *rony.MessageEnvelope) {
+                        res = m
+                    },
+                    nil, false, 0, 0,
+                ),
+            )
+            c.So(res, ShouldNotBeNil)
+            c.So(res.Constructor, ShouldEqual, msg.C_UsersMany)
+            x := &msg.UsersMany{}
+            c.So(x.Unmarshal(res.Message), ShouldBeNil)
+            return x
+        }
+
+        x := readBy(8)
+        c.So(x.Users, ShouldHaveLength, 1)
+        c.So(x.Users[0].ID, ShouldEqual, users[0].ID)
+
+        // the cached stats are patched by the read outbox updates
+        c.So(repo.Groups.UpdateReadHistoryStat(groupID, users[1].ID, 8), ShouldBeNil)
+        x = readBy(8)
+        c.So(x.Users, ShouldHaveLength, 2)
+
+        x = readBy(20)
+        c.So(x.Users, ShouldBeEmpty)
+        c.So(x.Empty, ShouldBeTrue)
+    })
+}
@@ -109,3 +109,96 @@ func (r *group) updateGroupAdminOnly(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelo
     res := []*msg.UpdateEnvelope{u}
     return res, nil
 }
+
+func (r *group) updateGroupParticipantAdd(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
+    x := &msg.UpdateGroupParticipantAdd{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return nil, err
+    }
+
+    r.Log().Debug("applies UpdateGroupParticipantAdd",
+        zap.Int64("GroupID", x.GroupID),
+        zap.Int64("UserID", x.UserID),
+        zap.Int64("UpdateID", x.UpdateID),
+    )
+
+    gp := &msg.GroupParticipant{
+        UserID: x.UserID,
+        Type:   msg.ParticipantType_ParticipantTypeMember,
+    }
+    if user, _ := repo.Users.Get(x.UserID); user != nil {
+        gp.FirstName = user.FirstName
+        gp.LastName = user.LastName
+        gp.Username = user.Username
+        gp.AccessHash = user.AccessHash
+        gp.Photo = user.Photo
+    }
+    // if the GroupFull is not cached, we only keep the number of the participants up to date
+    if gf, _ := repo.Groups.GetFull(x.GroupID); gf != nil {
+        err = repo.Groups.AddParticipant(x.GroupID, gp)
+    } else {
+        err = repo.Groups.UpdateParticipantsCount(x.GroupID, x.UserID, true)
+    }
+    r.Log().WarnOnErr("got error on adding group participant", err, zap.Int64("GroupID", x.GroupID))
+
+    if x.UserID == r.SDK().GetConnInfo().PickupUserID() {
+        r.setParticipation(x.GroupID, true)
+    }
+
+    res := []*msg.UpdateEnvelope{u}
+    return res, nil
+}
+
+func (r *group) updateGroupParticipantDeleted(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
+    x := &msg.UpdateGroupParticipantDeleted{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return nil, err
+    }
+
+    r.Log().Debug("applies UpdateGroupParticipantDeleted",
+        zap.Int64("GroupID", x.GroupID),
+        zap.Int64("UserID", x.UserID),
+        zap.Int64("UpdateID", x.UpdateID),
+    )
+
+    if gf, _ := repo.Groups.GetFull(x.GroupID); gf != nil {
+        err = repo.Groups.RemoveParticipant(x.GroupID, x.UserID)
+    } else {
+        err = repo.Groups.UpdateParticipantsCount(x.GroupID, x.UserID, false)
+    }
+    r.Log().WarnOnErr("got error on removing group participant", err, zap.Int64("GroupID", x.GroupID))
+
+    if x.UserID == r.SDK().GetConnInfo().PickupUserID() {
+        r.setParticipation(x.GroupID, false)
+    }
+
+    res := []*msg.UpdateEnvelope{u}
+    return res, nil
+}
+
+// setParticipation updates the group and its dialog when the current user joins or leaves the group. The dialog
+// of the group which the user is not a member of anymore is read-only.
+func (r *group) setParticipation(groupID int64, participant bool) {
+    err := repo.Groups.SetParticipation(groupID, participant)
+    if err != nil {
+        r.Log().WarnOnErr("got error on setting group participation", err, zap.Int64("GroupID", groupID))
+        return
+    }
+
+    group, _ := repo.Groups.Get(groupID)
+    if group == nil {
+        return
+    }
+    dialog, _ := repo.Dialogs.Get(group.TeamID, group.ID, int32(msg.PeerType_PeerGroup))
+    if dialog == nil {
+        return
+    }
+    if participant {
+        dialog.ReadOnly = repo.Groups.HasFlag(group.Flags, msg.GroupFlags_GroupFlagsAdminOnly) && !repo.Groups.HasFlag(group.Flags, msg.GroupFlags_GroupFlagsAdmin)
+    } else {
+        dialog.ReadOnly = true
+    }
+    _ = repo.Dialogs.Save(dialog)
+}
@@ -18,6 +18,7 @@ import (
     "github.com/dustin/go-humanize"
     "github.com/olekukonko/tablewriter"
     "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/clientmsg"
     "github.com/ronaksoft/river-sdk/internal/domain"
     "github.com/ronaksoft/river-sdk/internal/hole"
     "github.com/ronaksoft/river-sdk/internal/logs"
@@ -25,10 +26,12 @@ import (
     "github.com/ronaksoft/river-sdk/internal/repo"
     "github.com/ronaksoft/river-sdk/internal/request"
     "github.com/ronaksoft/river-sdk/internal/salt"
+    "github.com/ronaksoft/river-sdk/internal/uiexec"
     "github.com/ronaksoft/rony"
     "github.com/ronaksoft/rony/errors"
     "github.com/ronaksoft/rony/tools"
     "go.uber.org/zap"
+    "google.golang.org/protobuf/proto"
 )
 
 /*
@@ -46,8 +49,9 @@ func (r *message) messagesGetDialogs(da request.Callback) {
         return
     }
     res := &msg.MessagesDialogs{}
-    res.Dialogs, _ = repo.Dialogs.List(da.TeamID(), req.Offset, req.Limit)
-    res.Count = repo.Dialogs.CountDialogs(da.TeamID())
+    // Dialogs of the blocked users are hidden
+    res.Dialogs, _ = repo.Dialogs.ListUnblocked(da.TeamID(), req.Offset, req.Limit)
+    res.Count = repo.Dialogs.CountUnblockedDialogs(da.TeamID())
 
     // If the localDB had no data send the request to server
     if len(res.Dialogs) == 0 {
@@ -56,6 +60,12 @@ func (r *message) messagesGetDialogs(da request.Callback) {
         return
     }
 
+    r.fillDialogs(res)
+    da.Response(msg.C_MessagesDialogs, res)
+}
+
+// fillDialogs loads the top messages, the pending messages, the users and the groups of the dialogs into res
+func (r *message) fillDialogs(res *msg.MessagesDialogs) {
     pendingMessages := repo.PendingMessages.GetAndConvertAll()
     dialogPMs := make(map[string]*msg.UserMessage, len(pendingMessages))
     for _, pm := range pendingMessages {
@@ -142,8 +152,6 @@ func (r *message) messagesGetDialogs(da request.Callback) {
             }
         }
     }
-
-    da.Response(msg.C_MessagesDialogs, res)
 }
 
 func (r *message) messagesGetDialog(da request.Callback) {
@@ -496,7 +504,8 @@ func (r *message) messagesSendMedia(da request.Callback) {
         da.Response(rony.C_Error, errors.New("00", "USE_ClientSendMessageMedia"))
         return
     case msg.InputMediaType_InputMediaTypeContact, msg.InputMediaType_InputMediaTypeGeoLocation,
-        msg.InputMediaType_InputMediaTypeDocument, msg.InputMediaType_InputMediaTypeMessageDocument:
+        msg.InputMediaType_InputMediaTypeDocument, msg.InputMediaType_InputMediaTypeMessageDocument,
+        msg.InputMediaType_InputMediaTypePoll:
         // This will be used as next requestID
         // Insert into pending messages, id is negative nano timestamp and save RandomID too
         req.RandomID = domain.SequentialUniqueID()
@@ -928,6 +937,111 @@ func (r *message) messagesClearDraft(da request.Callback) {
     da.Response(msg.C_Bool, &msg.Bool{Result: true})
 }
 
+func (r *message) messagesEdit(da request.Callback) {
+    req := &msg.MessagesEdit{}
+    if err := da.RequestData(req); err != nil {
+        return
+    }
+
+    // the request of a pending message has been already queued, hence it cannot be edited
+    if req.MessageID < 0 {
+        da.Response(rony.C_Error, errors.New("00", domain.ErrMessageIsPending.Error()))
+        return
+    }
+
+    r.editMessage(da, req.MessageID, msg.C_MessagesEdit, req, func(um *msg.UserMessage) {
+        um.Body = req.Body
+        um.Entities = req.Entities
+    })
+}
+
+func (r *message) messagesEditMedia(da request.Callback) {
+    req := &msg.MessagesEditMedia{}
+    if err := da.RequestData(req); err != nil {
+        return
+    }
+
+    // the media is being uploaded, the new caption will be sent by the post upload process
+    if req.MessageID < 0 {
+        pm, err := repo.PendingMessages.UpdateCaption(req.MessageID, req.Caption, req.Entities)
+        if err != nil {
+            da.Response(rony.C_Error, errors.New("00", err.Error()))
+            return
+        }
+        r.notifyMessageEdited(repo.PendingMessages.ToUserMessage(pm))
+        da.Response(msg.C_Bool, &msg.Bool{Result: true})
+        return
+    }
+
+    r.editMessage(da, req.MessageID, msg.C_MessagesEditMedia, req, func(um *msg.UserMessage) {
+        if um.MediaType != msg.MediaType_MediaTypeDocument {
+            return
+        }
+        doc := &msg.MediaDocument{}
+        if err := doc.Unmarshal(um.Media); err != nil {
+            return
+        }
+        doc.Caption = req.Caption
+        doc.Entities = req.Entities
+        um.Media, _ = doc.Marshal()
+    })
+}
+
+// editMessage applies the edit on the local message and responds immediately, then the request is queued. The edit
+// is pending until updateMessageEdited confirms it, or it is rolled back by the queue if the server returns an error.
+func (r *message) editMessage(
+        da request.Callback, messageID int64, constructor int64, req proto.Message, fn func(um *msg.UserMessage),
+) {
+    reqID := domain.NextRequestID()
+    um, err := repo.Messages.ApplyEdit(messageID, reqID, fn)
+    if err != nil {
+        // we do not have the message, let the server decide
+        r.Log().Warn("got error on applying message edit", zap.Int64("MessageID", messageID), zap.Error(err))
+        r.SDK().QueueCtrl().EnqueueCommand(da)
+        return
+    }
+    r.notifyMessageEdited(um)
+
+    da.Discard()
+    r.SDK().QueueCtrl().EnqueueCommand(
+        request.NewCallback(
+            da.TeamID(), da.TeamAccess(), reqID, constructor, req,
+            nil,
+            func(m *rony.MessageEnvelope) {
+                if m.Constructor != rony.C_Error {
+                    return
+                }
+                r.Log().Warn("got error on editing message",
+                    zap.Int64("MessageID", messageID),
+                    zap.Error(domain.ParseServerError(m.Message)),
+                )
+                // the edit has been rolled back by the queue, we show the restored message
+                um, err := repo.Messages.Get(messageID)
+                if err != nil {
+                    return
+                }
+                r.notifyMessageEdited(um)
+            },
+            nil, false, da.Flags(), da.Timeout(),
+        ),
+    )
+    da.Response(msg.C_Bool, &msg.Bool{Result: true})
+}
+
+// notifyMessageEdited informs the ui about the local changes of the message
+func (r *message) notifyMessageEdited(um *msg.UserMessage) {
+    bytes, _ := (&msg.UpdateMessageEdited{Message: um}).Marshal()
+    uiexec.ExecUpdate(
+        msg.C_UpdateEnvelope,
+        &msg.UpdateEnvelope{
+            Constructor: msg.C_UpdateMessageEdited,
+            Update:      bytes,
+            UpdateID:    0,
+            Timestamp:   tools.TimeUnix(),
+        },
+    )
+}
+
 func (r *message) messagesTogglePin(da request.Callback) {
     req := &msg.MessagesTogglePin{}
     if err := da.RequestData(req); err != nil {
@@ -972,15 +1086,130 @@ func (r *message) messagesToggleDialogPin(da request.Callback) {
         return
     }
 
-    dialog, _ := repo.Dialogs.Get(da.TeamID(), req.Peer.ID, int32(req.Peer.Type))
-    if dialog != nil {
-        dialog.Pinned = req.Pin
-        _ = repo.Dialogs.Save(dialog)
+    err := repo.Dialogs.UpdatePinned(&msg.UpdateDialogPinned{
+        TeamID: da.TeamID(),
+        Peer:   &msg.Peer{ID: req.Peer.ID, Type: int32(req.Peer.Type)},
+        Pinned: req.Pin,
+    })
+    r.Log().WarnOnErr("got error on toggling dialog pin", err, zap.Int64("PeerID", req.Peer.ID))
+
+    // send the request to server
+    r.SDK().QueueCtrl().EnqueueCommand(da)
+
+}
+
+func (r *message) messagesReorderPinnedDialogs(da request.Callback) {
+    req := &msg.MessagesReorderPinnedDialogs{}
+    if err := da.RequestData(req); err != nil {
+        return
     }
 
+    // apply the new order optimistically, the server confirms it by UpdateDialogPinnedReorder, and we bring back
+    // the old order if the server rejects the request
+    teamID := da.TeamID()
+    oldDialogs := repo.Dialogs.GetPinnedDialogs(teamID)
+    oldPeers := make([]*msg.Peer, 0, len(oldDialogs))
+    for _, d := range oldDialogs {
+        oldPeers = append(oldPeers, &msg.Peer{ID: d.PeerID, Type: d.PeerType})
+    }
+    peers := make([]*msg.Peer, 0, len(req.Peers))
+    for _, p := range req.Peers {
+        peers = append(peers, &msg.Peer{ID: p.ID, Type: int32(p.Type)})
+    }
+    err := repo.Dialogs.ReorderPinned(teamID, peers)
+    r.Log().WarnOnErr("got error on reordering pinned dialogs", err)
+    da.SetPreComplete(func(m *rony.MessageEnvelope) {
+        if m.Constructor != rony.C_Error {
+            return
+        }
+        r.Log().Warn("got error on MessagesReorderPinnedDialogs",
+            zap.Error(domain.ParseServerError(m.Message)),
+        )
+        err := repo.Dialogs.ReplacePinned(teamID, oldPeers)
+        r.Log().WarnOnErr("got error on restoring pinned dialogs", err)
+    })
+
     // send the request to server
     r.SDK().QueueCtrl().EnqueueCommand(da)
+}
+
+// messagesGetPinnedDialogs answers from the local database, then the pinned dialogs are received from the server in
+// background and the ui is notified by DataSynced if their order has been changed. If there is no pinned dialog
+// locally, the request is sent to the server.
+func (r *message) messagesGetPinnedDialogs(da request.Callback) {
+    req := &msg.MessagesGetPinnedDialogs{}
+    if err := da.RequestData(req); err != nil {
+        return
+    }
+
+    teamID := da.TeamID()
+    res := &msg.MessagesDialogs{}
+    res.Dialogs = repo.Dialogs.GetPinnedDialogs(teamID)
+    res.Count = int32(len(res.Dialogs))
 
+    // If the localDB had no data send the request to server
+    if len(res.Dialogs) == 0 && r.SDK().NetCtrl().Connected() {
+        da.SetPreComplete(func(m *rony.MessageEnvelope) {
+            r.savePinnedDialogs(teamID, m)
+        })
+        r.SDK().QueueCtrl().EnqueueCommand(da)
+        return
+    }
+
+    res.UpdateID = r.SDK().SyncCtrl().GetUpdateID()
+    r.fillDialogs(res)
+    da.Response(msg.C_MessagesDialogs, res)
+
+    if !r.SDK().NetCtrl().Connected() {
+        return
+    }
+    localPeers := make([]*msg.Peer, 0, len(res.Dialogs))
+    for _, d := range res.Dialogs {
+        localPeers = append(localPeers, &msg.Peer{ID: d.PeerID, Type: d.PeerType})
+    }
+    r.SDK().QueueCtrl().EnqueueCommand(
+        request.NewCallback(
+            teamID, da.TeamAccess(), domain.NextRequestID(), msg.C_MessagesGetPinnedDialogs, req,
+            nil,
+            func(m *rony.MessageEnvelope) {
+                peers := r.savePinnedDialogs(teamID, m)
+                if peers != nil && !samePeers(peers, localPeers) {
+                    uiexec.ExecDataSynced(true, false, false)
+                }
+            },
+            nil, false, 0, da.Timeout(),
+        ),
+    )
+}
+
+// savePinnedDialogs saves the order of the pinned dialogs received from the server, and returns the saved order.
+func (r *message) savePinnedDialogs(teamID int64, m *rony.MessageEnvelope) []*msg.Peer {
+    if m.Constructor != msg.C_MessagesDialogs {
+        return nil
+    }
+    x := &msg.MessagesDialogs{}
+    if err := x.Unmarshal(m.Message); err != nil {
+        return nil
+    }
+    peers := make([]*msg.Peer, 0, len(x.Dialogs))
+    for _, d := range x.Dialogs {
+        peers = append(peers, &msg.Peer{ID: d.PeerID, Type: d.PeerType})
+    }
+    err := repo.Dialogs.ReplacePinned(teamID, peers)
+    r.Log().WarnOnErr("got error on saving pinned dialogs", err)
+    return peers
+}
+
+func samePeers(a, b []*msg.Peer) bool {
+    if len(a) != len(b) {
+        return false
+    }
+    for idx := range a {
+        if a[idx].ID != b[idx].ID || a[idx].Type != b[idx].Type {
+            return false
+        }
+    }
+    return true
 }
 
 func (r *message) clientGetMediaHistory(da request.Callback) {
@@ -1121,3 +1350,19 @@ func (r *message) clientGetLastBotKeyboard(da request.Callback) {
 
     da.Response(msg.C_UserMessage, lastKeyboardMsg)
 }
+
+func (r *message) clientGetTypingUsers(da request.Callback) {
+    req := &clientmsg.ClientGetTypingUsers{}
+    if err := da.RequestData(req); err != nil {
+        return
+    }
+    if req.Peer == nil {
+        da.Response(rony.C_Error, errors.New("00", "PEER_INVALID"))
+        return
+    }
+
+    res := &clientmsg.ClientTypingUsers{
+        Users: r.typing.get(da.TeamID(), req.Peer.ID, int32(req.Peer.Type)),
+    }
+    da.Response(clientmsg.C_ClientTypingUsers, res)
+}
@@ -1,7 +1,10 @@
 package message
 
 import (
+    "time"
+
     "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/clientmsg"
     "github.com/ronaksoft/river-sdk/internal/domain"
     "github.com/ronaksoft/river-sdk/internal/request"
     "github.com/ronaksoft/river-sdk/module"
@@ -18,45 +21,55 @@ import (
 
 type message struct {
     module.Base
+
+    typing *typingTracker
 }
 
 func New() *message {
     r := &message{}
+    r.typing = newTypingTracker(r.onTypingExpired)
     r.RegisterHandlers(
         map[int64]request.LocalHandler{
-            msg.C_MessagesClearDraft:         r.messagesClearDraft,
-            msg.C_MessagesClearHistory:       r.messagesClearHistory,
-            msg.C_MessagesDelete:             r.messagesDelete,
-            msg.C_MessagesDeleteReaction:     r.messagesDeleteReaction,
-            msg.C_MessagesGet:                r.messagesGet,
-            msg.C_MessagesGetDialog:          r.messagesGetDialog,
-            msg.C_MessagesGetDialogs:         r.messagesGetDialogs,
-            msg.C_MessagesGetHistory:         r.messagesGetHistory,
-            msg.C_MessagesGetMediaHistory:    r.messagesGetMediaHistory,
-            msg.C_MessagesReadContents:       r.messagesReadContents,
-            msg.C_MessagesReadHistory:        r.messagesReadHistory,
-            msg.C_MessagesSaveDraft:          r.messagesSaveDraft,
-            msg.C_MessagesSend:               r.messagesSend,
-            msg.C_MessagesSendMedia:          r.messagesSendMedia,
-            msg.C_MessagesSendReaction:       r.messagesSendReaction,
-            msg.C_MessagesToggleDialogPin:    r.messagesToggleDialogPin,
-            msg.C_MessagesTogglePin:          r.messagesTogglePin,
-            msg.C_ClientGetFrequentReactions: r.clientGetFrequentReactions,
-            msg.C_ClientGetMediaHistory:      r.clientGetMediaHistory,
-            msg.C_ClientSendMessageMedia:     r.clientSendMessageMedia,
-            msg.C_ClientClearCachedMedia:     r.clientClearCachedMedia,
-            msg.C_ClientGetCachedMedia:       r.clientGetCachedMedia,
-            msg.C_ClientGetLastBotKeyboard:   r.clientGetLastBotKeyboard,
+            msg.C_MessagesClearDraft:           r.messagesClearDraft,
+            msg.C_MessagesClearHistory:         r.messagesClearHistory,
+            msg.C_MessagesDelete:               r.messagesDelete,
+            msg.C_MessagesDeleteReaction:       r.messagesDeleteReaction,
+            msg.C_MessagesEdit:                 r.messagesEdit,
+            msg.C_MessagesEditMedia:            r.messagesEditMedia,
+            msg.C_MessagesGet:                  r.messagesGet,
+            msg.C_MessagesGetDialog:            r.messagesGetDialog,
+            msg.C_MessagesGetDialogs:           r.messagesGetDialogs,
+            msg.C_MessagesGetHistory:           r.messagesGetHistory,
+            msg.C_MessagesGetMediaHistory:      r.messagesGetMediaHistory,
+            msg.C_MessagesGetPinnedDialogs:     r.messagesGetPinnedDialogs,
+            msg.C_MessagesReadContents:         r.messagesReadContents,
+            msg.C_MessagesReadHistory:          r.messagesReadHistory,
+            msg.C_MessagesReorderPinnedDialogs: r.messagesReorderPinnedDialogs,
+            msg.C_MessagesSaveDraft:            r.messagesSaveDraft,
+            msg.C_MessagesSend:                 r.messagesSend,
+            msg.C_MessagesSendMedia:            r.messagesSendMedia,
+            msg.C_MessagesSendReaction:         r.messagesSendReaction,
+            msg.C_MessagesToggleDialogPin:      r.messagesToggleDialogPin,
+            msg.C_MessagesTogglePin:            r.messagesTogglePin,
+            msg.C_ClientGetFrequentReactions:   r.clientGetFrequentReactions,
+            msg.C_ClientGetMediaHistory:        r.clientGetMediaHistory,
+            msg.C_ClientSendMessageMedia:       r.clientSendMessageMedia,
+            msg.C_ClientClearCachedMedia:       r.clientClearCachedMedia,
+            msg.C_ClientGetCachedMedia:         r.clientGetCachedMedia,
+            msg.C_ClientGetLastBotKeyboard:     r.clientGetLastBotKeyboard,
+            clientmsg.C_ClientGetTypingUsers:   r.clientGetTypingUsers,
         },
     )
     r.RegisterUpdateAppliers(
         map[int64]domain.UpdateApplier{
             msg.C_UpdateDialogPinned:         r.updateDialogPinned,
+            msg.C_UpdateDialogPinnedReorder:  r.updateDialogPinnedReorder,
             msg.C_UpdateDraftMessage:         r.updateDraftMessage,
             msg.C_UpdateDraftMessageCleared:  r.updateDraftMessageCleared,
             msg.C_UpdateMessageEdited:        r.updateMessageEdited,
             msg.C_UpdateMessageID:            r.updateMessageID,
             msg.C_UpdateMessagePinned:        r.updateMessagePinned,
+            msg.C_UpdateMessagePoll:          r.updateMessagePoll,
             msg.C_UpdateMessagesDeleted:      r.updateMessagesDeleted,
             msg.C_UpdateNewMessage:           r.updateNewMessage,
             msg.C_UpdateNotifySettings:       r.updateNotifySettings,
@@ -64,6 +77,7 @@ func New() *message {
             msg.C_UpdateReadHistoryInbox:     r.updateReadHistoryInbox,
             msg.C_UpdateReadHistoryOutbox:    r.updateReadHistoryOutbox,
             msg.C_UpdateReadMessagesContents: r.updateReadMessagesContents,
+            msg.C_UpdateUserTyping:           r.updateUserTyping,
         },
     )
     r.RegisterMessageAppliers(
@@ -79,3 +93,9 @@ func New() *message {
 func (r *message) Name() string {
     return module.Message
 }
+
+// SetTypingTimeout sets the duration after which a typing action expires, if the user neither repeats it nor
+// cancels it. Non-positive values reset it to the default.
+func (r *message) SetTypingTimeout(d time.Duration) {
+    r.typing.setTimeout(d)
+}
@@ -78,6 +78,28 @@ func (r *message) messagesDialogs(e *rony.MessageEnvelope) {
     }()
     waitGroup.Wait()
     pools.ReleaseWaitGroup(waitGroup)
+
+    // Dialogs of the blocked users are saved, but they are hidden from the response which is passed to the UI
+    if hideBlockedDialogs(x) {
+        e.Message, _ = x.Marshal()
+    }
+}
+
+// hideBlockedDialogs removes the dialogs of the blocked users from the dialogs, it returns true if any dialog
+// is removed. Count is left as it is, since the next pages are requested from the server by its offsets.
+func hideBlockedDialogs(x *msg.MessagesDialogs) bool {
+    dialogs := make([]*msg.Dialog, 0, len(x.Dialogs))
+    for _, dialog := range x.Dialogs {
+        if dialog.PeerType == int32(msg.PeerType_PeerUser) && repo.Users.IsBlocked(dialog.PeerID) {
+            continue
+        }
+        dialogs = append(dialogs, dialog)
+    }
+    if len(dialogs) == len(x.Dialogs) {
+        return false
+    }
+    x.Dialogs = dialogs
+    return true
 }
 
 func (r *message) messagesMany(e *rony.MessageEnvelope) {
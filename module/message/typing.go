@@ -0,0 +1,192 @@
+package message
+
+import (
+    "sort"
+    "sync"
+    "time"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/rony/tools"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+const (
+    defaultTypingTimeout = 6 * time.Second
+)
+
+type typingPeer struct {
+    teamID   int64
+    peerID   int64
+    peerType int32
+}
+
+type typingKey struct {
+    typingPeer
+    userID int64
+}
+
+type typingEntry struct {
+    action    msg.TypingAction
+    expiresOn time.Time
+}
+
+// typingTracker keeps the users who are typing in each peer in memory. The server only sends the typing actions, and
+// the users which stop typing without sending a new message or a cancel action are expired after the timeout.
+type typingTracker struct {
+    mu       sync.Mutex
+    timeout  time.Duration
+    entries  map[typingKey]*typingEntry
+    timer    *time.Timer
+    onExpire func(x *msg.UpdateUserTyping)
+    now      func() time.Time
+}
+
+func newTypingTracker(onExpire func(x *msg.UpdateUserTyping)) *typingTracker {
+    return &typingTracker{
+        timeout:  defaultTypingTimeout,
+        entries:  make(map[typingKey]*typingEntry),
+        onExpire: onExpire,
+        now:      time.Now,
+    }
+}
+
+func (t *typingTracker) setTimeout(d time.Duration) {
+    if d <= 0 {
+        d = defaultTypingTimeout
+    }
+    t.mu.Lock()
+    t.timeout = d
+    t.mu.Unlock()
+}
+
+// apply records the action of the user and returns true if the UI must be notified. Repeated actions only extend
+// the expiry of the entry, and cancelling an action which is not tracked is a no-op.
+func (t *typingTracker) apply(x *msg.UpdateUserTyping) bool {
+    k := typingKey{
+        typingPeer: typingPeer{teamID: x.TeamID, peerID: x.PeerID, peerType: x.PeerType},
+        userID:     x.UserID,
+    }
+
+    t.mu.Lock()
+    defer t.mu.Unlock()
+    e, ok := t.entries[k]
+    if x.Action == msg.TypingAction_TypingActionCancel {
+        delete(t.entries, k)
+        return ok
+    }
+    expiresOn := t.now().Add(t.timeout)
+    if ok {
+        changed := e.action != x.Action
+        e.action = x.Action
+        e.expiresOn = expiresOn
+        return changed
+    }
+    t.entries[k] = &typingEntry{
+        action:    x.Action,
+        expiresOn: expiresOn,
+    }
+    t.schedule()
+    return true
+}
+
+// stop removes the user from the typers of the peer and returns true if the user was typing
+func (t *typingTracker) stop(teamID, peerID int64, peerType int32, userID int64) bool {
+    return t.apply(&msg.UpdateUserTyping{
+        TeamID:   teamID,
+        UserID:   userID,
+        Action:   msg.TypingAction_TypingActionCancel,
+        PeerID:   peerID,
+        PeerType: peerType,
+    })
+}
+
+// get returns the users who are typing in the peer ordered by their user id
+func (t *typingTracker) get(teamID, peerID int64, peerType int32) []*msg.UpdateUserTyping {
+    p := typingPeer{teamID: teamID, peerID: peerID, peerType: peerType}
+    now := t.now()
+
+    t.mu.Lock()
+    res := make([]*msg.UpdateUserTyping, 0, 4)
+    for k, e := range t.entries {
+        if k.typingPeer != p || !e.expiresOn.After(now) {
+            continue
+        }
+        res = append(res, &msg.UpdateUserTyping{
+            TeamID:   k.teamID,
+            UserID:   k.userID,
+            Action:   e.action,
+            PeerID:   k.peerID,
+            PeerType: k.peerType,
+        })
+    }
+    t.mu.Unlock()
+
+    sort.Slice(res, func(i, j int) bool {
+        return res[i].UserID < res[j].UserID
+    })
+    return res
+}
+
+// expire removes the expired entries and reports each of them as a cancel action
+func (t *typingTracker) expire() {
+    now := t.now()
+
+    t.mu.Lock()
+    var expired []*msg.UpdateUserTyping
+    for k, e := range t.entries {
+        if e.expiresOn.After(now) {
+            continue
+        }
+        delete(t.entries, k)
+        expired = append(expired, &msg.UpdateUserTyping{
+            TeamID:   k.teamID,
+            UserID:   k.userID,
+            Action:   msg.TypingAction_TypingActionCancel,
+            PeerID:   k.peerID,
+            PeerType: k.peerType,
+        })
+    }
+    t.timer = nil
+    t.schedule()
+    t.mu.Unlock()
+
+    if t.onExpire == nil {
+        return
+    }
+    for _, x := range expired {
+        t.onExpire(x)
+    }
+}
+
+// schedule arms the timer for the earliest expiry, if it is not armed yet. Entries which are extended after the
+// timer is armed are checked again when it fires. The caller must hold the lock.
+func (t *typingTracker) schedule() {
+    if t.timer != nil || len(t.entries) == 0 {
+        return
+    }
+    var next time.Time
+    for _, e := range t.entries {
+        if next.IsZero() || e.expiresOn.Before(next) {
+            next = e.expiresOn
+        }
+    }
+    t.timer = time.AfterFunc(next.Sub(t.now()), t.expire)
+}
+
+func typingEnvelope(x *msg.UpdateUserTyping) *msg.UpdateEnvelope {
+    xb, _ := x.Marshal()
+    return &msg.UpdateEnvelope{
+        Constructor: msg.C_UpdateUserTyping,
+        Update:      xb,
+        UpdateID:    0,
+        Timestamp:   tools.TimeUnix(),
+    }
+}
@@ -0,0 +1,112 @@
+package message
+
+import (
+    "testing"
+    "time"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/clientmsg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/request"
+    "github.com/ronaksoft/rony"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func typing(userID int64, action msg.TypingAction) *msg.UpdateUserTyping {
+    return &msg.UpdateUserTyping{
+        TeamID:   1,
+        UserID:   userID,
+        Action:   action,
+        PeerID:   100,
+        PeerType: int32(msg.PeerType_PeerGroup),
+    }
+}
+
+func TestTypingTracker(t *testing.T) {
+    Convey("TypingTracker", t, func(c C) {
+        var expired []*msg.UpdateUserTyping
+        now := time.Now()
+        tr := newTypingTracker(func(x *msg.UpdateUserTyping) {
+            expired = append(expired, x)
+        })
+        tr.now = func() time.Time { return now }
+        tr.setTimeout(5 * time.Second)
+
+        Convey("Coalesce Actions", func(c C) {
+            c.So(tr.apply(typing(11, msg.TypingAction_TypingActionTyping)), ShouldBeTrue)
+            c.So(tr.apply(typing(11, msg.TypingAction_TypingActionTyping)), ShouldBeFalse)
+            c.So(tr.apply(typing(11, msg.TypingAction_TypingActionUploading)), ShouldBeTrue)
+            c.So(tr.apply(typing(12, msg.TypingAction_TypingActionCancel)), ShouldBeFalse)
+            c.So(tr.apply(typing(12, msg.TypingAction_TypingActionRecordingVoice)), ShouldBeTrue)
+
+            users := tr.get(1, 100, int32(msg.PeerType_PeerGroup))
+            c.So(users, ShouldHaveLength, 2)
+            c.So(users[0].UserID, ShouldEqual, 11)
+            c.So(users[0].Action, ShouldEqual, msg.TypingAction_TypingActionUploading)
+            c.So(users[1].UserID, ShouldEqual, 12)
+            c.So(tr.get(2, 100, int32(msg.PeerType_PeerGroup)), ShouldBeEmpty)
+            c.So(tr.get(1, 100, int32(msg.PeerType_PeerUser)), ShouldBeEmpty)
+
+            c.So(tr.stop(1, 100, int32(msg.PeerType_PeerGroup), 11), ShouldBeTrue)
+            c.So(tr.stop(1, 100, int32(msg.PeerType_PeerGroup), 11), ShouldBeFalse)
+            c.So(tr.get(1, 100, int32(msg.PeerType_PeerGroup)), ShouldHaveLength, 1)
+        })
+        Convey("Expire Actions", func(c C) {
+            tr.apply(typing(11, msg.TypingAction_TypingActionTyping))
+            now = now.Add(3 * time.Second)
+            tr.apply(typing(12, msg.TypingAction_TypingActionTyping))
+            // repeating the action extends the expiry
+            tr.apply(typing(11, msg.TypingAction_TypingActionTyping))
+
+            now = now.Add(3 * time.Second)
+            tr.expire()
+            c.So(expired, ShouldBeEmpty)
+            c.So(tr.get(1, 100, int32(msg.PeerType_PeerGroup)), ShouldHaveLength, 2)
+
+            now = now.Add(2 * time.Second)
+            c.So(tr.get(1, 100, int32(msg.PeerType_PeerGroup)), ShouldBeEmpty)
+            tr.expire()
+            c.So(expired, ShouldHaveLength, 2)
+            for _, x := range expired {
+                c.So(x.Action, ShouldEqual, msg.TypingAction_TypingActionCancel)
+                c.So(x.PeerID, ShouldEqual, 100)
+            }
+            c.So(tr.apply(typing(11, msg.TypingAction_TypingActionTyping)), ShouldBeTrue)
+        })
+        Convey("Get Typing Users", func(c C) {
+            r := newTestMessageModule()
+            r.typing = tr
+            tr.apply(typing(11, msg.TypingAction_TypingActionTyping))
+            tr.apply(typing(12, msg.TypingAction_TypingActionRecordingVideo))
+
+            var res *rony.MessageEnvelope
+            r.clientGetTypingUsers(
+                request.NewCallback(
+                    1, 0, domain.NextRequestID(), clientmsg.C_ClientGetTypingUsers,
+                    &clientmsg.ClientGetTypingUsers{Peer: &msg.InputPeer{ID: 100, Type: msg.PeerType_PeerGroup}},
+                    nil,
+                    func(m *rony.MessageEnvelope) {
+                        res = m
+                    },
+                    nil, false, 0, 0,
+                ),
+            )
+            c.So(res, ShouldNotBeNil)
+            c.So(res.Constructor, ShouldEqual, clientmsg.C_ClientTypingUsers)
+            x := &clientmsg.ClientTypingUsers{}
+            c.So(x.Unmarshal(res.Message), ShouldBeNil)
+            c.So(x.Users, ShouldHaveLength, 2)
+            c.So(x.Users[1].UserID, ShouldEqual, 12)
+            c.So(x.Users[1].Action, ShouldEqual, msg.TypingAction_TypingActionRecordingVideo)
+        })
+    })
+}
@@ -26,6 +26,13 @@ import (
    Copyright Ronak Software Group 2020
 */
 
+func (r *message) isFromBlocked(m *msg.UserMessage) bool {
+    if m.PeerType != int32(msg.PeerType_PeerUser) || m.SenderID == r.SDK().SyncCtrl().GetUserID() {
+        return false
+    }
+    return repo.Users.IsBlocked(m.SenderID)
+}
+
 func (r *message) updateNewMessage(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
     x := &msg.UpdateNewMessage{}
     err := x.Unmarshal(u.Update)
@@ -89,10 +96,30 @@ func (r *message) updateNewMessage(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope
     waitGroup.Wait()
     pools.ReleaseWaitGroup(waitGroup)
 
+    // Messages of the blocked users are saved, but they are not passed to the UI to not notify the user
+    if r.isFromBlocked(x.Message) {
+        r.Log().Debug("suppressed new message of blocked user",
+            zap.Int64("MessageID", x.Message.ID),
+            zap.Int64("SenderID", x.Message.SenderID),
+        )
+        return []*msg.UpdateEnvelope{}, nil
+    }
+
     // handle Message's Action
     res := []*msg.UpdateEnvelope{u}
     res = append(res, r.handleMessageAction(x, u)...)
 
+    // The sender is not typing anymore once the message arrives
+    if r.typing.stop(x.Message.TeamID, x.Message.PeerID, x.Message.PeerType, x.Message.SenderID) {
+        res = append(res, typingEnvelope(&msg.UpdateUserTyping{
+            TeamID:   x.Message.TeamID,
+            UserID:   x.Message.SenderID,
+            Action:   msg.TypingAction_TypingActionCancel,
+            PeerID:   x.Message.PeerID,
+            PeerType: x.Message.PeerType,
+        }))
+    }
+
     // If sender is me, check for pending
     if x.Message.SenderID == r.SDK().SyncCtrl().GetUserID() {
         pm := repo.PendingMessages.GetByRealID(x.Message.ID)
@@ -247,6 +274,9 @@ func (r *message) handlePendingMessage(x *msg.UpdateNewMessage) {
             if unmarshalErr == nil { // TODO!!! fix this with some flag in pending message
                 clientFile, err := repo.Files.GetMediaDocument(x.Message)
                 r.Log().WarnOnErr("Error On GetMediaDocument", err)
+                if clientFile != nil {
+                    _ = repo.Files.SaveSha256(clientFile.ClusterID, clientFile.FileID, clientFile.AccessHash, pmsg.Sha256)
+                }
 
                 err = os.Rename(clientSendMedia.FilePath, repo.Files.GetFilePath(clientFile))
                 if err != nil {
@@ -314,6 +344,15 @@ func (r *message) updateReadHistoryOutbox(u *msg.UpdateEnvelope) ([]*msg.UpdateE
     )
 
     _ = repo.Dialogs.UpdateReadOutboxMaxID(x.TeamID, x.Peer.ID, x.Peer.Type, x.MaxID)
+    if x.Peer.Type == int32(msg.PeerType_PeerGroup) {
+        if x.UserID != 0 {
+            err = repo.Groups.UpdateReadHistoryStat(x.Peer.ID, x.UserID, x.MaxID)
+        } else {
+            // we do not know who has read the messages
+            err = repo.Groups.InvalidateReadHistoryStats(x.Peer.ID)
+        }
+        r.Log().WarnOnErr("got error on updating read history stats", err, zap.Int64("GroupID", x.Peer.ID))
+    }
     res := []*msg.UpdateEnvelope{u}
     return res, nil
 }
@@ -330,6 +369,13 @@ func (r *message) updateMessageEdited(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvel
         zap.Int64("UpdateID", x.UpdateID),
     )
 
+    // if our newer edits are still pending, the local message must be kept until they are confirmed
+    done, err := repo.Messages.ConfirmEdit(x.Message)
+    r.Log().WarnOnErr("got error on confirming message edit", err, zap.Int64("MessageID", x.Message.ID))
+    if !done {
+        return []*msg.UpdateEnvelope{}, nil
+    }
+
     repo.Messages.Save(x.Message)
 
     res := []*msg.UpdateEnvelope{u}
@@ -337,7 +383,8 @@ func (r *message) updateMessageEdited(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvel
 }
 
 func (r *message) updateMessageID(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
-    res := make([]*msg.UpdateEnvelope, 0)
+    // the UI maps its pending message to the real message by UpdateMessageID
+    res := []*msg.UpdateEnvelope{u}
     x := new(msg.UpdateMessageID)
     err := x.Unmarshal(u.Update)
     if err != nil {
@@ -381,7 +428,7 @@ func (r *message) updateMessageID(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope,
     }
 
     if pm == nil {
-        return nil, nil
+        return res, nil
     }
     r.Log().Info("received UpdateMessageID before UpdateNewMessage",
         zap.Int64("RandomID", x.RandomID),
@@ -473,6 +520,27 @@ func (r *message) updateReaction(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope,
     return []*msg.UpdateEnvelope{u}, nil
 }
 
+func (r *message) updateMessagePoll(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
+    x := &msg.UpdateMessagePoll{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return nil, err
+    }
+
+    r.Log().Debug("applies UpdateMessagePoll",
+        zap.Int64("UpdateID", x.UpdateID),
+        zap.Int64("PollID", x.PollID),
+    )
+
+    // If the message is not received yet, the poll state is merged into it when it is saved
+    _, err = repo.Polls.Apply(x)
+    if err != nil {
+        return nil, err
+    }
+
+    return []*msg.UpdateEnvelope{u}, nil
+}
+
 func (r *message) updateMessagePinned(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
     x := &msg.UpdateMessagePinned{}
     err := x.Unmarshal(u.Update)
@@ -572,3 +640,54 @@ func (r *message) updateDialogPinned(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelo
     res := []*msg.UpdateEnvelope{u}
     return res, nil
 }
+
+func (r *message) updateDialogPinnedReorder(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
+    x := new(msg.UpdateDialogPinnedReorder)
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return nil, err
+    }
+
+    r.Log().Debug("applies UpdateDialogPinnedReorder",
+        zap.Int64("UpdateID", x.UpdateID),
+        zap.Int("Count", len(x.Peer)),
+    )
+
+    err = repo.Dialogs.ApplyPinnedReorder(domain.GetCurrTeamID(), x.Peer)
+    if err != nil {
+        return nil, err
+    }
+    res := []*msg.UpdateEnvelope{u}
+    return res, nil
+}
+
+func (r *message) updateUserTyping(u *msg.UpdateEnvelope) ([]*msg.UpdateEnvelope, error) {
+    x := &msg.UpdateUserTyping{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return nil, err
+    }
+
+    r.Log().Debug("applies UpdateUserTyping",
+        zap.Int64("UserID", x.UserID),
+        zap.Int64("PeerID", x.PeerID),
+        zap.String("Action", x.Action.String()),
+    )
+
+    // Repeated actions only extend the expiry, hence we do not bother the UI with them
+    if !r.typing.apply(x) {
+        return []*msg.UpdateEnvelope{}, nil
+    }
+
+    res := []*msg.UpdateEnvelope{u}
+    return res, nil
+}
+
+// onTypingExpired notifies the UI about the users whose typing action has expired without a cancel from the server
+func (r *message) onTypingExpired(x *msg.UpdateUserTyping) {
+    r.Log().Debug("expires typing action",
+        zap.Int64("UserID", x.UserID),
+        zap.Int64("PeerID", x.PeerID),
+    )
+    uiexec.ExecUpdate(msg.C_UpdateEnvelope, typingEnvelope(x))
+}
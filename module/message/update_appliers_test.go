@@ -0,0 +1,158 @@
+package message
+
+import (
+    "testing"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    syncCtrl "github.com/ronaksoft/river-sdk/internal/ctrl_sync"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/river-sdk/internal/testenv"
+    "github.com/ronaksoft/river-sdk/module"
+    "github.com/ronaksoft/rony"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+const testUserID = 1000
+
+func init() {
+    repo.MustInit("./_data", false)
+    testenv.Log().SetLogLevel(2)
+}
+
+// testSDK provides only the controllers which are used by the appliers under test
+type testSDK struct {
+    module.SDK
+    syncCtrl *syncCtrl.Controller
+}
+
+func (s *testSDK) SyncCtrl() *syncCtrl.Controller {
+    return s.syncCtrl
+}
+
+func newTestMessageModule() *message {
+    sdk := &testSDK{
+        syncCtrl: syncCtrl.NewSyncController(syncCtrl.Config{}),
+    }
+    sdk.syncCtrl.SetUserID(testUserID)
+    r := New()
+    r.Init(sdk, testenv.Log())
+    return r
+}
+
+func newMessageEnvelope(senderID, msgID int64) *msg.UpdateEnvelope {
+    x := &msg.UpdateNewMessage{
+        Message: &msg.UserMessage{
+            ID:        msgID,
+            PeerID:    senderID,
+            PeerType:  int32(msg.PeerType_PeerUser),
+            SenderID:  senderID,
+            CreatedOn: domain.Now().Unix(),
+            Body:      "Hello",
+        },
+        Sender: &msg.User{ID: senderID, FirstName: "Sender"},
+    }
+    b, _ := x.Marshal()
+    return &msg.UpdateEnvelope{
+        Constructor: msg.C_UpdateNewMessage,
+        Update:      b,
+        UpdateID:    msgID,
+    }
+}
+
+func TestBlockedUsers(t *testing.T) {
+    r := newTestMessageModule()
+    Convey("Blocked Users", t, func(c C) {
+        blockedID := domain.RandomInt63()
+        c.So(repo.Users.UpdateBlocked(blockedID, true), ShouldBeNil)
+
+        Convey("New Message Is Suppressed", func(c C) {
+            msgID := domain.RandomInt63()
+            res, err := r.updateNewMessage(newMessageEnvelope(blockedID, msgID))
+            c.So(err, ShouldBeNil)
+            c.So(res, ShouldBeEmpty)
+            // the message is saved, it is only hidden from the UI
+            m, err := repo.Messages.Get(msgID)
+            c.So(err, ShouldBeNil)
+            c.So(m.SenderID, ShouldEqual, blockedID)
+
+            senderID := domain.RandomInt63()
+            u := newMessageEnvelope(senderID, domain.RandomInt63())
+            res, err = r.updateNewMessage(u)
+            c.So(err, ShouldBeNil)
+            c.So(res, ShouldNotBeEmpty)
+            c.So(res[0], ShouldEqual, u)
+        })
+        Convey("Dialogs From Server Are Filtered", func(c C) {
+            teamID := domain.RandomInt63()
+            peerID := domain.RandomInt63()
+            x := &msg.MessagesDialogs{
+                Dialogs: []*msg.Dialog{
+                    {TeamID: teamID, PeerID: blockedID, PeerType: int32(msg.PeerType_PeerUser), TopMessageID: 2},
+                    {TeamID: teamID, PeerID: peerID, PeerType: int32(msg.PeerType_PeerUser), TopMessageID: 1},
+                },
+                Count: 10,
+            }
+            b, _ := x.Marshal()
+            e := &rony.MessageEnvelope{Constructor: msg.C_MessagesDialogs, Message: b}
+            r.messagesDialogs(e)
+
+            res := &msg.MessagesDialogs{}
+            c.So(res.Unmarshal(e.Message), ShouldBeNil)
+            c.So(res.Dialogs, ShouldHaveLength, 1)
+            c.So(res.Dialogs[0].PeerID, ShouldEqual, peerID)
+            c.So(res.Count, ShouldEqual, 10)
+
+            // the dialog is saved, so it is shown once the user is unblocked
+            d, err := repo.Dialogs.Get(teamID, blockedID, int32(msg.PeerType_PeerUser))
+            c.So(err, ShouldBeNil)
+            c.So(d.TopMessageID, ShouldEqual, 2)
+        })
+    })
+}
+
+func TestMessageEdited(t *testing.T) {
+    r := newTestMessageModule()
+    Convey("Message Edited", t, func(c C) {
+        m := &msg.UserMessage{
+            ID:       domain.RandomInt63(),
+            PeerID:   domain.RandomInt63(),
+            PeerType: int32(msg.PeerType_PeerUser),
+            SenderID: testUserID,
+            Body:     "original",
+        }
+        c.So(repo.Messages.Save(m), ShouldBeNil)
+        _, err := repo.Messages.ApplyEdit(m.ID, domain.NextRequestID(), func(um *msg.UserMessage) {
+            um.Body = "edited"
+        })
+        c.So(err, ShouldBeNil)
+        edited := func(body string) *msg.UpdateEnvelope {
+            b, _ := (&msg.UpdateMessageEdited{
+                Message: &msg.UserMessage{ID: m.ID, PeerID: m.PeerID, PeerType: m.PeerType, Body: body},
+            }).Marshal()
+            return &msg.UpdateEnvelope{Constructor: msg.C_UpdateMessageEdited, Update: b}
+        }
+
+        // the edit of the other device does not override our pending edit
+        res, err := r.updateMessageEdited(edited("other device"))
+        c.So(err, ShouldBeNil)
+        c.So(res, ShouldBeEmpty)
+        um, _ := repo.Messages.Get(m.ID)
+        c.So(um.Body, ShouldEqual, "edited")
+
+        u := edited("edited")
+        res, err = r.updateMessageEdited(u)
+        c.So(err, ShouldBeNil)
+        c.So(res, ShouldHaveLength, 1)
+        c.So(repo.Messages.IsEditPending(m.ID), ShouldBeFalse)
+    })
+}
@@ -0,0 +1,362 @@
+package bot
+
+import (
+    "context"
+    "errors"
+    "sort"
+    "sync"
+    "time"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/logs"
+    "github.com/ronaksoft/river-sdk/internal/uiexec"
+    "github.com/ronaksoft/river-sdk/sdk/client"
+    "go.uber.org/zap"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+var (
+    logger *logs.Logger
+)
+
+func init() {
+    logger = logs.With("Bot")
+}
+
+const (
+    defaultWorkers    = 16
+    defaultBufferSize = 1024
+    workerQueueSize   = 64
+    differenceLimit   = 250
+)
+
+var (
+    ErrAlreadyStarted = errors.New("bot has already been started")
+    ErrNoCallback     = errors.New("update is not a callback query")
+    ErrNoInlineQuery  = errors.New("update is not an inline query")
+)
+
+// SDK is implemented by the River (prime) SDK
+type SDK interface {
+    client.Executor
+    Subscribe(ctx context.Context, f uiexec.Filter, bufferSize int, policy uiexec.OverflowPolicy) <-chan *msg.UpdateEnvelope
+}
+
+type Option func(b *Bot)
+
+// WithUser sets the user id and the username of the bot. The messages sent by the bot itself are ignored, and the
+// commands which are addressed to other bots (i.e. /start@other_bot) are not routed.
+func WithUser(userID int64, username string) Option {
+    return func(b *Bot) {
+        b.userID = userID
+        b.username = username
+    }
+}
+
+// WithWorkers sets the number of the handlers which could run concurrently. The updates of a chat are always
+// handled one by one and in order.
+func WithWorkers(n int) Option {
+    return func(b *Bot) {
+        if n > 0 {
+            b.workers = n
+        }
+    }
+}
+
+// WithBufferSize sets the number of the updates which could be waiting to be routed
+func WithBufferSize(n int) Option {
+    return func(b *Bot) {
+        if n > 0 {
+            b.bufferSize = n
+        }
+    }
+}
+
+// WithChatRateLimit limits the replies which are sent to each chat to n per period. Zero disables the limit.
+func WithChatRateLimit(n int, period time.Duration) Option {
+    return func(b *Bot) {
+        b.chatLimiter = newLimiter(n, period)
+    }
+}
+
+// WithGlobalRateLimit limits all the replies of the bot to n per period. Zero disables the limit.
+func WithGlobalRateLimit(n int, period time.Duration) Option {
+    return func(b *Bot) {
+        b.globalLimiter = newLimiter(n, period)
+    }
+}
+
+// Bot routes the updates of a bot account to the registered handlers. It runs on top of the River SDK, hence
+// the updates are synced and applied by the SDK and the replies go through the SDK's queue.
+type Bot struct {
+    Router
+
+    sdk           SDK
+    client        *client.Client
+    userID        int64
+    username      string
+    workers       int
+    bufferSize    int
+    chatLimiter   *limiter
+    globalLimiter *limiter
+
+    // handlersCtx is canceled if the handlers do not return before the deadline of Shutdown
+    handlersCtx    context.Context
+    cancelHandlers context.CancelFunc
+
+    // lastUpdateID is the id of the last received update, it is used by Run only
+    lastUpdateID int64
+
+    mu   sync.Mutex
+    stop context.CancelFunc
+    done chan struct{}
+}
+
+func New(sdk SDK, opts ...Option) *Bot {
+    b := &Bot{
+        sdk:        sdk,
+        client:     client.New(sdk),
+        workers:    defaultWorkers,
+        bufferSize: defaultBufferSize,
+    }
+    b.handlersCtx, b.cancelHandlers = context.WithCancel(context.Background())
+    for _, opt := range opts {
+        opt(b)
+    }
+    return b
+}
+
+// Client returns the typed client which is used by the bot
+func (b *Bot) Client() *client.Client {
+    return b.client
+}
+
+// Run receives the updates and routes them to the handlers, until ctx is done or Shutdown is called. Before
+// returning, it waits for the handlers of the received updates to return. If the handlers are too slow and some
+// updates are dropped, they are fetched from the server by their update ids. A bot could be run only once.
+func (b *Bot) Run(ctx context.Context) error {
+    b.mu.Lock()
+    if b.done != nil {
+        b.mu.Unlock()
+        return ErrAlreadyStarted
+    }
+    ctx, b.stop = context.WithCancel(ctx)
+    b.done = make(chan struct{})
+    done := b.done
+    b.mu.Unlock()
+    defer close(done)
+
+    wg := sync.WaitGroup{}
+    queues := make([]chan *Context, b.workers)
+    for i := range queues {
+        queues[i] = make(chan *Context, workerQueueSize)
+        wg.Add(1)
+        go b.worker(queues[i], &wg)
+    }
+    defer func() {
+        for _, q := range queues {
+            close(q)
+        }
+        wg.Wait()
+    }()
+
+    f := uiexec.Filter{
+        Constructors: []int64{msg.C_UpdateNewMessage, msg.C_UpdateBotCallbackQuery, msg.C_UpdateBotInlineQuery},
+    }
+    updates := b.sdk.Subscribe(ctx, f, b.bufferSize, uiexec.OverflowClose)
+    for {
+        select {
+        case <-ctx.Done():
+            return nil
+        case ue, ok := <-updates:
+            if !ok {
+                if ctx.Err() != nil {
+                    return nil
+                }
+                logger.Warn("we missed some updates, handlers were too slow", zap.Int64("LastUpdateID", b.lastUpdateID))
+                // We subscribe before getting the difference, hence the updates which are received in the
+                // meantime are not lost. The duplicates are skipped by their update id.
+                updates = b.sdk.Subscribe(ctx, f, b.bufferSize, uiexec.OverflowClose)
+                if !b.resume(ctx, queues) {
+                    return nil
+                }
+                continue
+            }
+            if !b.dispatch(ctx, queues, ue) {
+                return nil
+            }
+        }
+    }
+}
+
+// dispatch routes the update to its chat's queue, it returns false if ctx is done
+func (b *Bot) dispatch(ctx context.Context, queues []chan *Context, ue *msg.UpdateEnvelope) bool {
+    if ue.UpdateID != 0 {
+        if ue.UpdateID <= b.lastUpdateID {
+            return true
+        }
+        b.lastUpdateID = ue.UpdateID
+    }
+    c, err := b.newContext(ue)
+    if err != nil {
+        logger.Warn("got error on decoding update", zap.Error(err))
+        return true
+    }
+    if c == nil {
+        return true
+    }
+    select {
+    case queues[c.chatKey()%uint64(len(queues))] <- c:
+        return true
+    case <-ctx.Done():
+        return false
+    }
+}
+
+// resume gets the updates which are missed after the last received update from the server and dispatches them.
+// It returns false if ctx is done.
+func (b *Bot) resume(ctx context.Context, queues []chan *Context) bool {
+    if b.lastUpdateID == 0 {
+        logger.Warn("we could not get the missed updates, no update id has been received")
+        return true
+    }
+    for {
+        res, err := b.client.GetDifference(ctx, &msg.UpdateGetDifference{
+            From:  b.lastUpdateID + 1,
+            Limit: differenceLimit,
+        })
+        if err != nil {
+            if ctx.Err() != nil {
+                return false
+            }
+            logger.Warn("got error on getting the missed updates",
+                zap.Int64("LastUpdateID", b.lastUpdateID),
+                zap.Error(err),
+            )
+            return true
+        }
+        sort.Slice(res.Updates, func(i, j int) bool {
+            return res.Updates[i].UpdateID < res.Updates[j].UpdateID
+        })
+        for _, ue := range res.Updates {
+            if !b.dispatch(ctx, queues, ue) {
+                return false
+            }
+        }
+        if !res.More || len(res.Updates) == 0 {
+            return true
+        }
+    }
+}
+
+// Shutdown stops receiving the updates and waits for the running handlers. If ctx is done before the handlers
+// return, their contexts are canceled and ctx.Err() is returned.
+func (b *Bot) Shutdown(ctx context.Context) error {
+    b.mu.Lock()
+    stop, done := b.stop, b.done
+    b.mu.Unlock()
+    if done == nil {
+        return nil
+    }
+
+    stop()
+    select {
+    case <-done:
+        return nil
+    case <-ctx.Done():
+        b.cancelHandlers()
+        return ctx.Err()
+    }
+}
+
+func (b *Bot) worker(q chan *Context, wg *sync.WaitGroup) {
+    defer wg.Done()
+    for c := range q {
+        b.handle(c)
+    }
+}
+
+func (b *Bot) handle(c *Context) {
+    defer func() {
+        if r := recover(); r != nil {
+            logger.Error("handler panicked", zap.Any("Recovered", r))
+        }
+    }()
+    h := b.route(c)
+    if h == nil {
+        return
+    }
+    if err := h(c); err != nil {
+        logger.Warn("got error from handler",
+            zap.Int64("PeerID", c.Peer.GetID()),
+            zap.Int64("UserID", c.UserID),
+            zap.Error(err),
+        )
+    }
+}
+
+// newContext decodes the update, it returns nil if the update must not be routed
+func (b *Bot) newContext(ue *msg.UpdateEnvelope) (*Context, error) {
+    c := &Context{
+        Context: b.handlersCtx,
+        bot:     b,
+    }
+    switch ue.Constructor {
+    case msg.C_UpdateNewMessage:
+        x := &msg.UpdateNewMessage{}
+        if err := x.Unmarshal(ue.Update); err != nil {
+            return nil, err
+        }
+        if x.Message == nil || x.Message.SenderID == b.userID {
+            return nil, nil
+        }
+        c.TeamID = x.Message.TeamID
+        c.UserID = x.Message.SenderID
+        c.Peer = &msg.InputPeer{
+            ID:         x.Message.PeerID,
+            Type:       msg.PeerType(x.Message.PeerType),
+            AccessHash: x.AccessHash,
+        }
+        c.Message = x.Message
+    case msg.C_UpdateBotCallbackQuery:
+        x := &msg.UpdateBotCallbackQuery{}
+        if err := x.Unmarshal(ue.Update); err != nil {
+            return nil, err
+        }
+        c.TeamID = x.TeamID
+        c.UserID = x.UserID
+        c.Peer = inputPeer(x.Peer)
+        c.Callback = x
+    case msg.C_UpdateBotInlineQuery:
+        x := &msg.UpdateBotInlineQuery{}
+        if err := x.Unmarshal(ue.Update); err != nil {
+            return nil, err
+        }
+        c.TeamID = x.TeamID
+        c.UserID = x.UserID
+        c.Peer = inputPeer(x.Peer)
+        c.Inline = x
+    default:
+        return nil, nil
+    }
+    return c, nil
+}
+
+func inputPeer(p *msg.Peer) *msg.InputPeer {
+    if p == nil {
+        return nil
+    }
+    return &msg.InputPeer{
+        ID:         p.ID,
+        Type:       msg.PeerType(p.Type),
+        AccessHash: p.AccessHash,
+    }
+}
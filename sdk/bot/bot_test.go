@@ -0,0 +1,287 @@
+package bot_test
+
+import (
+    "context"
+    "regexp"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    networkCtrl "github.com/ronaksoft/river-sdk/internal/ctrl_network"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/river-sdk/internal/request"
+    "github.com/ronaksoft/river-sdk/internal/testenv"
+    "github.com/ronaksoft/river-sdk/internal/testenv/fakeserver"
+    "github.com/ronaksoft/river-sdk/internal/uiexec"
+    "github.com/ronaksoft/river-sdk/sdk/bot"
+    "github.com/ronaksoft/rony"
+    "github.com/ronaksoft/rony/tools"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func init() {
+    repo.MustInit("./_data", false)
+    testenv.Log().SetLogLevel(2)
+}
+
+// fakeSDK sends the requests directly to the fake server and passes the pushed updates to the subscriber
+type fakeSDK struct {
+    ctrl    *networkCtrl.Controller
+    updates chan *msg.UpdateContainer
+    // overflow closes the subscription as if its buffer is overflowed
+    overflow chan struct{}
+}
+
+func newFakeSDK(s *fakeserver.Server) *fakeSDK {
+    messageChan := make(chan []*rony.MessageEnvelope, 100)
+    updateChan := make(chan *msg.UpdateContainer, 100)
+    ctrl := networkCtrl.New(networkCtrl.Config{
+        SeedHosts: []string{s.Addr()},
+    })
+    ctrl.OnWebsocketConnect = func() error { return nil }
+    ctrl.OnNetworkStatusChange = func(newStatus domain.NetworkStatus) {}
+    ctrl.MessageChan = messageChan
+    ctrl.UpdateChan = updateChan
+    go func() {
+        for msgs := range messageChan {
+            for _, m := range msgs {
+                reqCB := request.GetCallback(m.RequestID)
+                if reqCB == nil {
+                    continue
+                }
+                select {
+                case reqCB.ResponseChan() <- m:
+                default:
+                }
+            }
+        }
+    }()
+    ctrl.Start()
+    ctrl.Connect()
+    ctrl.SetAuthorization(s.CreateAuthKey())
+    return &fakeSDK{
+        ctrl:     ctrl,
+        updates:  updateChan,
+        overflow: make(chan struct{}),
+    }
+}
+
+func (f *fakeSDK) Execute(cb request.Callback) error {
+    go f.ctrl.WebsocketCommand(cb)
+    return nil
+}
+
+func (f *fakeSDK) CancelRequest(requestID int64) {}
+
+func (f *fakeSDK) Subscribe(ctx context.Context, filter uiexec.Filter, bufferSize int, policy uiexec.OverflowPolicy) <-chan *msg.UpdateEnvelope {
+    ch := make(chan *msg.UpdateEnvelope, bufferSize)
+    go func() {
+        defer close(ch)
+        for {
+            select {
+            case <-ctx.Done():
+                return
+            case <-f.overflow:
+                return
+            case uc := <-f.updates:
+                for _, u := range uc.Updates {
+                    for _, c := range filter.Constructors {
+                        if c == u.Constructor {
+                            ch <- u
+                        }
+                    }
+                }
+            }
+        }
+    }()
+    return ch
+}
+
+// recorder keeps the requests which are received by the server
+type recorder struct {
+    mu       sync.Mutex
+    messages chan *msg.BotSendMessage
+    answers  chan *msg.BotSetCallbackAnswer
+}
+
+func newRecorder(s *fakeserver.Server) *recorder {
+    r := &recorder{
+        messages: make(chan *msg.BotSendMessage, 100),
+        answers:  make(chan *msg.BotSetCallbackAnswer, 100),
+    }
+    s.Handle(msg.C_BotSendMessage, func(ctx *fakeserver.Context) {
+        req := &msg.BotSendMessage{}
+        _ = req.Unmarshal(ctx.Request().Message)
+        r.messages <- req
+        ctx.Reply(msg.C_MessagesSent, &msg.MessagesSent{MessageID: tools.RandomInt64(0), RandomID: req.RandomID})
+    })
+    s.Handle(msg.C_BotSetCallbackAnswer, func(ctx *fakeserver.Context) {
+        req := &msg.BotSetCallbackAnswer{}
+        _ = req.Unmarshal(ctx.Request().Message)
+        r.answers <- req
+        ctx.Reply(msg.C_Bool, &msg.Bool{Result: true})
+    })
+    return r
+}
+
+func (r *recorder) NextMessage() string {
+    select {
+    case m := <-r.messages:
+        return m.Body
+    case <-time.After(5 * time.Second):
+        return "<timeout>"
+    }
+}
+
+func newMessage(userID int64, senderID int64, body string) *msg.UpdateNewMessage {
+    return &msg.UpdateNewMessage{
+        Message: &msg.UserMessage{
+            ID:       tools.RandomInt64(0),
+            PeerID:   userID,
+            PeerType: int32(msg.PeerType_PeerUser),
+            SenderID: senderID,
+            Body:     body,
+        },
+        AccessHash: 1000,
+    }
+}
+
+func TestBot(t *testing.T) {
+    Convey("Bot", t, func(c C) {
+        s, err := fakeserver.New()
+        c.So(err, ShouldBeNil)
+        defer s.Close()
+        sdk := newFakeSDK(s)
+        defer sdk.ctrl.Stop()
+        rec := newRecorder(s)
+
+        // We need one request, so the server knows the authID of the connection
+        err = bot.New(sdk).Client().Invoke(context.Background(),
+            msg.C_UpdateGetState, &msg.UpdateGetState{}, msg.C_UpdateState, &msg.UpdateState{},
+        )
+        c.So(err, ShouldBeNil)
+
+        botID := tools.RandomInt64(0)
+        userID := tools.RandomInt64(0)
+        b := bot.New(sdk, bot.WithUser(botID, "test_bot"), bot.WithWorkers(4))
+        b.Command("start", func(ctx *bot.Context) error {
+            _, err := ctx.Reply("hello " + ctx.Args)
+            return err
+        })
+        b.Regex(regexp.MustCompile(`^my name is (\w+)$`), func(ctx *bot.Context) error {
+            bs, err := ctx.State()
+            if err != nil {
+                return err
+            }
+            bs.Values["name"] = ctx.Matches[1]
+            return ctx.SaveState()
+        })
+        b.Command("name", func(ctx *bot.Context) error {
+            bs, err := ctx.State()
+            if err != nil {
+                return err
+            }
+            _, err = ctx.Reply(bs.Values["name"])
+            return err
+        })
+        b.Callback("vote:", func(ctx *bot.Context) error {
+            return ctx.AnswerCallback("voted "+ctx.Args, 0)
+        })
+        started := make(chan struct{})
+        b.Command("slow", func(ctx *bot.Context) error {
+            close(started)
+            time.Sleep(200 * time.Millisecond)
+            _, err := ctx.Reply("done")
+            return err
+        })
+        b.Fallback(func(ctx *bot.Context) error {
+            _, err := ctx.Reply("unknown")
+            return err
+        })
+
+        runErr := make(chan error, 1)
+        go func() {
+            runErr <- b.Run(context.Background())
+        }()
+
+        Convey("Route", func(c C) {
+            s.PushUpdate(msg.C_UpdateNewMessage, newMessage(userID, botID, "/start from myself"))
+            s.PushUpdate(msg.C_UpdateNewMessage, newMessage(userID, userID, "/start@other_bot"))
+            s.PushUpdate(msg.C_UpdateNewMessage, newMessage(userID, userID, "/start@test_bot now"))
+            c.So(rec.NextMessage(), ShouldEqual, "hello now")
+
+            s.PushUpdate(msg.C_UpdateNewMessage, newMessage(userID, userID, "my name is ehsan"))
+            s.PushUpdate(msg.C_UpdateNewMessage, newMessage(userID, userID, "/name"))
+            c.So(rec.NextMessage(), ShouldEqual, "ehsan")
+            bs, err := repo.BotStates.Get(0, userID, int32(msg.PeerType_PeerUser))
+            c.So(err, ShouldBeNil)
+            c.So(bs.Values["name"], ShouldEqual, "ehsan")
+
+            s.PushUpdate(msg.C_UpdateNewMessage, newMessage(userID, userID, "what?"))
+            c.So(rec.NextMessage(), ShouldEqual, "unknown")
+
+            s.PushUpdate(msg.C_UpdateBotCallbackQuery, &msg.UpdateBotCallbackQuery{
+                QueryID:   10,
+                UserID:    userID,
+                Peer:      &msg.Peer{ID: userID, Type: int32(msg.PeerType_PeerUser)},
+                MessageID: 1,
+                Data:      []byte("vote:up"),
+            })
+            select {
+            case ans := <-rec.answers:
+                c.So(ans.QueryID, ShouldEqual, 10)
+                c.So(ans.Message, ShouldEqual, "voted up")
+            case <-time.After(5 * time.Second):
+                c.So("answer not received", ShouldBeEmpty)
+            }
+        })
+        Convey("Missed Updates Are Resumed", func(c C) {
+            s.PushUpdate(msg.C_UpdateNewMessage, newMessage(userID, userID, "/start 1"))
+            c.So(rec.NextMessage(), ShouldEqual, "hello 1")
+
+            // these updates are dropped by the subscription
+            s.NewUpdate(msg.C_UpdateNewMessage, newMessage(userID, userID, "/start 2"))
+            s.NewUpdate(msg.C_UpdateNewMessage, newMessage(userID, userID, "/start 3"))
+            sdk.overflow <- struct{}{}
+            s.PushUpdate(msg.C_UpdateNewMessage, newMessage(userID, userID, "/start 4"))
+            c.So(rec.NextMessage(), ShouldEqual, "hello 2")
+            c.So(rec.NextMessage(), ShouldEqual, "hello 3")
+            c.So(rec.NextMessage(), ShouldEqual, "hello 4")
+
+            // the update which is received by both the subscription and the difference is handled once
+            select {
+            case m := <-rec.messages:
+                c.So(m.Body, ShouldBeEmpty)
+            case <-time.After(300 * time.Millisecond):
+            }
+        })
+        Convey("Graceful Shutdown", func(c C) {
+            s.PushUpdate(msg.C_UpdateNewMessage, newMessage(userID, userID, "/slow"))
+            <-started
+            ctx, cf := context.WithTimeout(context.Background(), 5*time.Second)
+            defer cf()
+            c.So(b.Shutdown(ctx), ShouldBeNil)
+            c.So(<-runErr, ShouldBeNil)
+            c.So(b.Run(context.Background()), ShouldEqual, bot.ErrAlreadyStarted)
+            select {
+            case m := <-rec.messages:
+                c.So(m.Body, ShouldEqual, "done")
+            default:
+                c.So("reply not sent before shutdown", ShouldBeEmpty)
+            }
+        })
+
+        _ = b.Shutdown(context.Background())
+    })
+}
@@ -0,0 +1,158 @@
+package bot
+
+import (
+    "context"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/repo"
+    "github.com/ronaksoft/river-sdk/sdk/client"
+    "github.com/ronaksoft/rony/tools"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+const (
+    // globalKey is the key of the global rate limiter
+    globalKey = 0
+)
+
+// Context is passed to the handlers. Exactly one of Message, Callback and Inline is set.
+type Context struct {
+    context.Context
+    bot *Bot
+
+    TeamID int64
+    // UserID is the id of the user who has sent the message, pressed the button or typed the inline query
+    UserID int64
+    // Peer is the chat of the update, it could be nil for the inline queries
+    Peer     *msg.InputPeer
+    Message  *msg.UserMessage
+    Callback *msg.UpdateBotCallbackQuery
+    Inline   *msg.UpdateBotInlineQuery
+    // Args is the rest of the command, callback data or inline query after the routed prefix
+    Args string
+    // Matches are the sub-matches of the routed regex
+    Matches []string
+
+    state *repo.BotState
+}
+
+func peerKey(p *msg.InputPeer) uint64 {
+    return uint64(p.GetID())<<2 ^ uint64(p.GetType())
+}
+
+func (c *Context) chatKey() uint64 {
+    if c.Peer == nil {
+        return peerKey(&msg.InputPeer{ID: c.UserID, Type: msg.PeerType_PeerUser})
+    }
+    return peerKey(c.Peer)
+}
+
+// Client returns the client which sends the requests in the team of the update
+func (c *Context) Client() *client.Client {
+    if c.TeamID == 0 {
+        return c.bot.client
+    }
+    var accessHash uint64
+    if t, err := repo.Teams.Get(c.TeamID); err == nil {
+        accessHash = t.AccessHash
+    }
+    return c.bot.client.With(client.WithTeam(c.TeamID, accessHash))
+}
+
+// Reply sends the text to the chat of the update
+func (c *Context) Reply(text string) (*msg.MessagesSent, error) {
+    return c.Send(&msg.BotSendMessage{
+        Peer: c.Peer,
+        Body: text,
+    })
+}
+
+// Send sends the message, if req.Peer is nil the message is sent to the chat of the update. It waits if the bot has
+// reached the rate limits.
+func (c *Context) Send(req *msg.BotSendMessage) (*msg.MessagesSent, error) {
+    if req.Peer == nil {
+        req.Peer = c.Peer
+    }
+    if req.RandomID == 0 {
+        req.RandomID = tools.RandomInt64(0)
+    }
+    if err := c.bot.globalLimiter.wait(c, globalKey); err != nil {
+        return nil, err
+    }
+    if err := c.bot.chatLimiter.wait(c, peerKey(req.Peer)); err != nil {
+        return nil, err
+    }
+    return c.Client().SendBotMessage(c, req)
+}
+
+// AnswerCallback answers the pressed button, message is shown to the user and the answer is cached by the clients
+// for cacheTime seconds.
+func (c *Context) AnswerCallback(message string, cacheTime int32) error {
+    if c.Callback == nil {
+        return ErrNoCallback
+    }
+    if err := c.bot.globalLimiter.wait(c, globalKey); err != nil {
+        return err
+    }
+    _, err := c.Client().SetCallbackAnswer(c, &msg.BotSetCallbackAnswer{
+        QueryID:   c.Callback.QueryID,
+        Message:   message,
+        CacheTime: cacheTime,
+    })
+    return err
+}
+
+// AnswerInline sends the results of the inline query
+func (c *Context) AnswerInline(res *msg.BotSetInlineResults) error {
+    if c.Inline == nil {
+        return ErrNoInlineQuery
+    }
+    if err := c.bot.globalLimiter.wait(c, globalKey); err != nil {
+        return err
+    }
+    res.QueryID = c.Inline.QueryID
+    _, err := c.Client().SetInlineResults(c, res)
+    return err
+}
+
+// State returns the state of the conversation in the chat, the changes are persisted by SaveState
+func (c *Context) State() (*repo.BotState, error) {
+    if c.state != nil {
+        return c.state, nil
+    }
+    peerID, peerType := c.UserID, int32(msg.PeerType_PeerUser)
+    if c.Peer != nil {
+        peerID, peerType = c.Peer.ID, int32(c.Peer.Type)
+    }
+    bs, err := repo.BotStates.Get(c.TeamID, peerID, peerType)
+    if err != nil {
+        return nil, err
+    }
+    c.state = bs
+    return bs, nil
+}
+
+func (c *Context) SaveState() error {
+    if c.state == nil {
+        return nil
+    }
+    return repo.BotStates.Save(c.state)
+}
+
+// ResetState removes the state of the conversation in the chat
+func (c *Context) ResetState() error {
+    bs, err := c.State()
+    if err != nil {
+        return err
+    }
+    c.state = nil
+    return repo.BotStates.Delete(bs.TeamID, bs.PeerID, bs.PeerType)
+}
@@ -0,0 +1,97 @@
+package bot
+
+import (
+    "context"
+    "sync"
+    "time"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+const (
+    // limiterSweepSize is the number of the buckets which causes the full buckets to be removed
+    limiterSweepSize = 1024
+)
+
+type bucket struct {
+    tokens float64
+    last   time.Time
+}
+
+// limiter is a token bucket per key, the burst is equal to the number of the allowed events in the period
+type limiter struct {
+    mu      sync.Mutex
+    rate    float64 // tokens per second
+    burst   float64
+    buckets map[uint64]*bucket
+    now     func() time.Time
+}
+
+func newLimiter(n int, period time.Duration) *limiter {
+    if n <= 0 || period <= 0 {
+        return nil
+    }
+    return &limiter{
+        rate:    float64(n) / period.Seconds(),
+        burst:   float64(n),
+        buckets: make(map[uint64]*bucket),
+        now:     time.Now,
+    }
+}
+
+// reserve takes a token from the bucket of the key and returns how long the caller must wait before using it
+func (l *limiter) reserve(key uint64) time.Duration {
+    l.mu.Lock()
+    defer l.mu.Unlock()
+
+    now := l.now()
+    if len(l.buckets) >= limiterSweepSize {
+        for k, b := range l.buckets {
+            if b.tokens+now.Sub(b.last).Seconds()*l.rate >= l.burst {
+                delete(l.buckets, k)
+            }
+        }
+    }
+
+    b, ok := l.buckets[key]
+    if !ok {
+        b = &bucket{tokens: l.burst, last: now}
+        l.buckets[key] = b
+    }
+    b.tokens += now.Sub(b.last).Seconds() * l.rate
+    if b.tokens > l.burst {
+        b.tokens = l.burst
+    }
+    b.last = now
+    b.tokens--
+    if b.tokens >= 0 {
+        return 0
+    }
+    return time.Duration(-b.tokens / l.rate * float64(time.Second))
+}
+
+// wait blocks until the key is allowed, a nil limiter allows everything
+func (l *limiter) wait(ctx context.Context, key uint64) error {
+    if l == nil {
+        return nil
+    }
+    d := l.reserve(key)
+    if d <= 0 {
+        return nil
+    }
+    t := time.NewTimer(d)
+    defer t.Stop()
+    select {
+    case <-t.C:
+        return nil
+    case <-ctx.Done():
+        return ctx.Err()
+    }
+}
@@ -0,0 +1,64 @@
+package bot
+
+import (
+    "context"
+    "testing"
+    "time"
+
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+func TestLimiter(t *testing.T) {
+    Convey("Limiter", t, func(c C) {
+        now := time.Now()
+        l := newLimiter(2, time.Second)
+        l.now = func() time.Time { return now }
+
+        c.So(l.reserve(1), ShouldEqual, 0)
+        c.So(l.reserve(1), ShouldEqual, 0)
+        c.So(l.reserve(1), ShouldEqual, 500*time.Millisecond)
+        c.So(l.reserve(1), ShouldEqual, time.Second)
+        // other keys have their own buckets
+        c.So(l.reserve(2), ShouldEqual, 0)
+
+        // the bucket never holds more than the burst
+        now = now.Add(5 * time.Second)
+        c.So(l.reserve(1), ShouldEqual, 0)
+        c.So(l.reserve(1), ShouldEqual, 0)
+        c.So(l.reserve(1), ShouldEqual, 500*time.Millisecond)
+
+        c.So(newLimiter(0, time.Second), ShouldBeNil)
+        c.So(newLimiter(0, time.Second).wait(context.Background(), 1), ShouldBeNil)
+    })
+}
+
+func TestParseCommand(t *testing.T) {
+    Convey("Parse Command", t, func(c C) {
+        for _, tc := range []struct {
+            body string
+            cmd  string
+            args string
+            to   string
+        }{
+            {"/start", "start", "", ""},
+            {"/start  a b ", "start", "a b", ""},
+            {"/start@test_bot a", "start", "a", "test_bot"},
+            {"start", "", "", ""},
+            {"/", "", "", ""},
+        } {
+            cmd, args, to := parseCommand(tc.body)
+            c.So(cmd, ShouldEqual, tc.cmd)
+            c.So(args, ShouldEqual, tc.args)
+            c.So(to, ShouldEqual, tc.to)
+        }
+    })
+}
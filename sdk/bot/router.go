@@ -0,0 +1,137 @@
+package bot
+
+import (
+    "regexp"
+    "strings"
+    "sync"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+// HandlerFunc handles the routed update, the returned error is only logged
+type HandlerFunc func(c *Context) error
+
+type route struct {
+    prefix string
+    re     *regexp.Regexp
+    h      HandlerFunc
+}
+
+// Router keeps the handlers of the bot. The commands are checked before the regular expressions, and in each kind
+// the routes are checked in the order they have been registered.
+type Router struct {
+    mu        sync.RWMutex
+    commands  []route
+    regexps   []route
+    callbacks []route
+    inlines   []route
+    fallback  HandlerFunc
+}
+
+// Command routes the messages which start with /cmd (or /cmd@username) to h. The rest of the message is passed
+// as Context.Args.
+func (r *Router) Command(cmd string, h HandlerFunc) {
+    r.mu.Lock()
+    r.commands = append(r.commands, route{prefix: strings.TrimPrefix(cmd, "/"), h: h})
+    r.mu.Unlock()
+}
+
+// Regex routes the messages which match re to h. The sub-matches are passed as Context.Matches.
+func (r *Router) Regex(re *regexp.Regexp, h HandlerFunc) {
+    r.mu.Lock()
+    r.regexps = append(r.regexps, route{re: re, h: h})
+    r.mu.Unlock()
+}
+
+// Callback routes the button presses which their data starts with prefix to h. The rest of the data is passed
+// as Context.Args.
+func (r *Router) Callback(prefix string, h HandlerFunc) {
+    r.mu.Lock()
+    r.callbacks = append(r.callbacks, route{prefix: prefix, h: h})
+    r.mu.Unlock()
+}
+
+// Inline routes the inline queries which start with prefix to h, an empty prefix matches all the queries. The rest
+// of the query is passed as Context.Args.
+func (r *Router) Inline(prefix string, h HandlerFunc) {
+    r.mu.Lock()
+    r.inlines = append(r.inlines, route{prefix: prefix, h: h})
+    r.mu.Unlock()
+}
+
+// Fallback handles the messages which have not been matched by any command or regex
+func (r *Router) Fallback(h HandlerFunc) {
+    r.mu.Lock()
+    r.fallback = h
+    r.mu.Unlock()
+}
+
+// route finds the handler of the update and fills the arguments of the context
+func (b *Bot) route(c *Context) HandlerFunc {
+    r := &b.Router
+    r.mu.RLock()
+    defer r.mu.RUnlock()
+
+    switch {
+    case c.Message != nil:
+        body := c.Message.Body
+        if cmd, args, to := parseCommand(body); cmd != "" {
+            // the commands of the other bots are ignored
+            if to != "" && !strings.EqualFold(to, b.username) {
+                return nil
+            }
+            for _, rt := range r.commands {
+                if rt.prefix == cmd {
+                    c.Args = args
+                    return rt.h
+                }
+            }
+        }
+        for _, rt := range r.regexps {
+            if m := rt.re.FindStringSubmatch(body); m != nil {
+                c.Matches = m
+                return rt.h
+            }
+        }
+        return r.fallback
+    case c.Callback != nil:
+        data := string(c.Callback.Data)
+        for _, rt := range r.callbacks {
+            if strings.HasPrefix(data, rt.prefix) {
+                c.Args = data[len(rt.prefix):]
+                return rt.h
+            }
+        }
+    case c.Inline != nil:
+        for _, rt := range r.inlines {
+            if strings.HasPrefix(c.Inline.Query, rt.prefix) {
+                c.Args = strings.TrimSpace(c.Inline.Query[len(rt.prefix):])
+                return rt.h
+            }
+        }
+    }
+    return nil
+}
+
+// parseCommand extracts the command, its arguments and the username of the bot which the command is addressed to
+// (i.e. /start@username). If the body is not a command, cmd is empty.
+func parseCommand(body string) (cmd, args, to string) {
+    if !strings.HasPrefix(body, "/") {
+        return "", "", ""
+    }
+    cmd = body[1:]
+    if idx := strings.IndexAny(cmd, " \t\n"); idx >= 0 {
+        cmd, args = cmd[:idx], strings.TrimSpace(cmd[idx+1:])
+    }
+    if idx := strings.IndexByte(cmd, '@'); idx >= 0 {
+        cmd, to = cmd[:idx], cmd[idx+1:]
+    }
+    return cmd, args, to
+}
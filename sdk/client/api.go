@@ -0,0 +1,227 @@
+package client
+
+import (
+    "context"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+// SendMessage returns the pending message instead of the server's response. The local handler saves the
+// message as pending and answers right away, then the message is sent in background and its final state is
+// received by UpdateMessageID and UpdateNewMessage. Execute could be used if the response envelope is needed
+// as is.
+func (c *Client) SendMessage(ctx context.Context, req *msg.MessagesSend) (*msg.ClientPendingMessage, error) {
+    res := &msg.ClientPendingMessage{}
+    if err := c.Invoke(ctx, msg.C_MessagesSend, req, msg.C_ClientPendingMessage, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (c *Client) SendMedia(ctx context.Context, req *msg.MessagesSendMedia) (*msg.ClientPendingMessage, error) {
+    res := &msg.ClientPendingMessage{}
+    if err := c.Invoke(ctx, msg.C_MessagesSendMedia, req, msg.C_ClientPendingMessage, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+// EditMessage applies the edit locally and returns, the edit is sent in background and it is confirmed by
+// UpdateMessageEdited.
+func (c *Client) EditMessage(ctx context.Context, req *msg.MessagesEdit) (*msg.Bool, error) {
+    res := &msg.Bool{}
+    if err := c.Invoke(ctx, msg.C_MessagesEdit, req, msg.C_Bool, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+// EditMessageMedia edits the caption of a media message. It works like EditMessage, and if the media is still
+// being uploaded, the new caption is sent with the media.
+func (c *Client) EditMessageMedia(ctx context.Context, req *msg.MessagesEditMedia) (*msg.Bool, error) {
+    res := &msg.Bool{}
+    if err := c.Invoke(ctx, msg.C_MessagesEditMedia, req, msg.C_Bool, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (c *Client) DeleteMessages(ctx context.Context, req *msg.MessagesDelete) (*msg.Bool, error) {
+    res := &msg.Bool{}
+    if err := c.Invoke(ctx, msg.C_MessagesDelete, req, msg.C_Bool, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (c *Client) GetMessages(ctx context.Context, req *msg.MessagesGet) (*msg.MessagesMany, error) {
+    res := &msg.MessagesMany{}
+    if err := c.Invoke(ctx, msg.C_MessagesGet, req, msg.C_MessagesMany, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (c *Client) GetHistory(ctx context.Context, req *msg.MessagesGetHistory) (*msg.MessagesMany, error) {
+    res := &msg.MessagesMany{}
+    if err := c.Invoke(ctx, msg.C_MessagesGetHistory, req, msg.C_MessagesMany, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (c *Client) ReadHistory(ctx context.Context, req *msg.MessagesReadHistory) (*msg.Bool, error) {
+    res := &msg.Bool{}
+    if err := c.Invoke(ctx, msg.C_MessagesReadHistory, req, msg.C_Bool, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (c *Client) SetTyping(ctx context.Context, req *msg.MessagesSetTyping) (*msg.Bool, error) {
+    res := &msg.Bool{}
+    if err := c.Invoke(ctx, msg.C_MessagesSetTyping, req, msg.C_Bool, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (c *Client) GetDialogs(ctx context.Context, req *msg.MessagesGetDialogs) (*msg.MessagesDialogs, error) {
+    res := &msg.MessagesDialogs{}
+    if err := c.Invoke(ctx, msg.C_MessagesGetDialogs, req, msg.C_MessagesDialogs, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (c *Client) GetDialog(ctx context.Context, req *msg.MessagesGetDialog) (*msg.Dialog, error) {
+    res := &msg.Dialog{}
+    if err := c.Invoke(ctx, msg.C_MessagesGetDialog, req, msg.C_Dialog, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (c *Client) GetContacts(ctx context.Context, req *msg.ContactsGet) (*msg.ContactsMany, error) {
+    res := &msg.ContactsMany{}
+    if err := c.Invoke(ctx, msg.C_ContactsGet, req, msg.C_ContactsMany, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (c *Client) ImportContacts(ctx context.Context, req *msg.ContactsImport) (*msg.ContactsImported, error) {
+    res := &msg.ContactsImported{}
+    if err := c.Invoke(ctx, msg.C_ContactsImport, req, msg.C_ContactsImported, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (c *Client) GetUsers(ctx context.Context, req *msg.UsersGet) (*msg.UsersMany, error) {
+    res := &msg.UsersMany{}
+    if err := c.Invoke(ctx, msg.C_UsersGet, req, msg.C_UsersMany, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (c *Client) GetFullUsers(ctx context.Context, req *msg.UsersGetFull) (*msg.UsersMany, error) {
+    res := &msg.UsersMany{}
+    if err := c.Invoke(ctx, msg.C_UsersGetFull, req, msg.C_UsersMany, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (c *Client) CreateGroup(ctx context.Context, req *msg.GroupsCreate) (*msg.Group, error) {
+    res := &msg.Group{}
+    if err := c.Invoke(ctx, msg.C_GroupsCreate, req, msg.C_Group, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (c *Client) GetGroupFull(ctx context.Context, req *msg.GroupsGetFull) (*msg.GroupFull, error) {
+    res := &msg.GroupFull{}
+    if err := c.Invoke(ctx, msg.C_GroupsGetFull, req, msg.C_GroupFull, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (c *Client) GetTeams(ctx context.Context, req *msg.AccountGetTeams) (*msg.TeamsMany, error) {
+    res := &msg.TeamsMany{}
+    if err := c.Invoke(ctx, msg.C_AccountGetTeams, req, msg.C_TeamsMany, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (c *Client) GetSystemConfig(ctx context.Context, req *msg.SystemGetConfig) (*msg.SystemConfig, error) {
+    res := &msg.SystemConfig{}
+    if err := c.Invoke(ctx, msg.C_SystemGetConfig, req, msg.C_SystemConfig, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+// GetCallbackAnswer presses the button of the bot's message. Pressing the same button while the answer is on the
+// way does not send a new request.
+func (c *Client) GetCallbackAnswer(ctx context.Context, req *msg.BotGetCallbackAnswer) (*msg.BotCallbackAnswer, error) {
+    res := &msg.BotCallbackAnswer{}
+    if err := c.Invoke(ctx, msg.C_BotGetCallbackAnswer, req, msg.C_BotCallbackAnswer, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+// GetInlineResults returns the results of the inline query, the results are cached for a short while.
+func (c *Client) GetInlineResults(ctx context.Context, req *msg.BotGetInlineResults) (*msg.BotResults, error) {
+    res := &msg.BotResults{}
+    if err := c.Invoke(ctx, msg.C_BotGetInlineResults, req, msg.C_BotResults, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (c *Client) SendBotMessage(ctx context.Context, req *msg.BotSendMessage) (*msg.MessagesSent, error) {
+    res := &msg.MessagesSent{}
+    if err := c.Invoke(ctx, msg.C_BotSendMessage, req, msg.C_MessagesSent, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (c *Client) SetCallbackAnswer(ctx context.Context, req *msg.BotSetCallbackAnswer) (*msg.Bool, error) {
+    res := &msg.Bool{}
+    if err := c.Invoke(ctx, msg.C_BotSetCallbackAnswer, req, msg.C_Bool, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (c *Client) SetInlineResults(ctx context.Context, req *msg.BotSetInlineResults) (*msg.Bool, error) {
+    res := &msg.Bool{}
+    if err := c.Invoke(ctx, msg.C_BotSetInlineResults, req, msg.C_Bool, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
+
+func (c *Client) GetDifference(ctx context.Context, req *msg.UpdateGetDifference) (*msg.UpdateDifference, error) {
+    res := &msg.UpdateDifference{}
+    if err := c.Invoke(ctx, msg.C_UpdateGetDifference, req, msg.C_UpdateDifference, res); err != nil {
+        return nil, err
+    }
+    return res, nil
+}
@@ -0,0 +1,179 @@
+package client
+
+import (
+    "context"
+    "time"
+
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/request"
+    "github.com/ronaksoft/rony"
+    "google.golang.org/protobuf/proto"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+// Callback, DelegateFlag and the types used by their methods are re-exported, since the internal packages could
+// not be imported by the Go consumers outside this module.
+type (
+    Callback        = request.Callback
+    DelegateFlag    = request.DelegateFlag
+    Unmarshaller    = request.Unmarshaller
+    MessageHandler  = domain.MessageHandler
+    TimeoutCallback = domain.TimeoutCallback
+)
+
+// Request Flags
+const (
+    ServerForced       = request.ServerForced
+    Blocking           = request.Blocking
+    SkipWaitForNetwork = request.SkipWaitForNetwork
+    SkipFlusher        = request.SkipFlusher
+    Realtime           = request.Realtime
+    Batch              = request.Batch
+    RetryUntilCanceled = request.RetryUntilCanceled
+)
+
+// Executor is implemented by the River (prime) SDK. The requests go through the same path as ExecuteCommand,
+// hence local handlers answer from the local database if they can, and the others are sent to the server.
+type Executor interface {
+    Execute(cb Callback) error
+    CancelRequest(requestID int64)
+}
+
+// Client is a typed and context aware API over the SDK for the Go consumers (i.e. bots, desktop and server-side
+// tools). Unlike ExecuteCommand it does not need the delegates and returns the typed responses.
+type Client struct {
+    e          Executor
+    teamID     int64
+    teamAccess uint64
+    timeout    time.Duration
+    flags      DelegateFlag
+}
+
+type Option func(c *Client)
+
+// WithTeam sets the target team of the requests, by default the current team of the SDK is used.
+func WithTeam(teamID int64, accessHash uint64) Option {
+    return func(c *Client) {
+        c.teamID = teamID
+        c.teamAccess = accessHash
+    }
+}
+
+// WithTimeout sets the timeout of the requests if the context has no deadline.
+func WithTimeout(timeout time.Duration) Option {
+    return func(c *Client) {
+        c.timeout = timeout
+    }
+}
+
+// WithFlags sets the flags of the requests, i.e. ServerForced to skip the local handlers.
+func WithFlags(flags DelegateFlag) Option {
+    return func(c *Client) {
+        c.flags = flags
+    }
+}
+
+func New(e Executor, opts ...Option) *Client {
+    c := &Client{
+        e:       e,
+        timeout: domain.WebsocketRequestTimeout,
+    }
+    for _, opt := range opts {
+        opt(c)
+    }
+    return c
+}
+
+// With returns a copy of the client with the options applied
+func (c *Client) With(opts ...Option) *Client {
+    nc := *c
+    for _, opt := range opts {
+        opt(&nc)
+    }
+    return &nc
+}
+
+type result struct {
+    env *rony.MessageEnvelope
+    err error
+}
+
+// Invoke executes the request and unmarshals the response into res. If the response constructor is rony.C_Error
+// an *Error is returned, and if it is neither rony.C_Error nor resConstructor, ErrUnexpectedResponse is returned.
+// If ctx is done before the response, the request is canceled and ctx.Err() is returned.
+func (c *Client) Invoke(ctx context.Context, constructor int64, req proto.Message, resConstructor int64, res proto.Message) error {
+    env, err := c.Execute(ctx, constructor, req)
+    if err != nil {
+        return err
+    }
+    switch env.Constructor {
+    case resConstructor:
+        return proto.Unmarshal(env.Message, res)
+    case rony.C_Error:
+        return newError(env.Message)
+    default:
+        return ErrUnexpectedResponse
+    }
+}
+
+// Execute executes the request and returns the response envelope as is, rony.C_Error responses are not turned
+// into errors. It is used when the response constructor is not known up front.
+func (c *Client) Execute(ctx context.Context, constructor int64, req proto.Message) (*rony.MessageEnvelope, error) {
+    if ctx == nil {
+        ctx = context.Background()
+    }
+    if err := ctx.Err(); err != nil {
+        return nil, err
+    }
+
+    timeout := c.timeout
+    if deadline, ok := ctx.Deadline(); ok {
+        timeout = time.Until(deadline)
+    }
+
+    teamID, teamAccess := c.teamID, c.teamAccess
+    if teamID == 0 {
+        teamID, teamAccess = domain.GetCurrTeamID(), domain.GetCurrTeamAccess()
+    }
+
+    resChan := make(chan result, 1)
+    reqID := domain.NextRequestID()
+    reqCB := request.NewCallback(
+        teamID, teamAccess, reqID, constructor, req,
+        func() {
+            select {
+            case resChan <- result{err: ErrTimeout}:
+            default:
+            }
+        },
+        func(m *rony.MessageEnvelope) {
+            select {
+            case resChan <- result{env: m.Clone()}:
+            default:
+            }
+        },
+        nil, false, c.flags, timeout,
+    )
+    err := c.e.Execute(reqCB)
+    if err != nil {
+        reqCB.Discard()
+        return nil, err
+    }
+
+    select {
+    case r := <-resChan:
+        return r.env, r.err
+    case <-ctx.Done():
+        reqCB.Discard()
+        c.e.CancelRequest(int64(reqID))
+        return nil, ctx.Err()
+    }
+}
@@ -0,0 +1,108 @@
+package client_test
+
+import (
+    "context"
+    "errors"
+    "sync"
+    "testing"
+    "time"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/sdk/client"
+    "github.com/ronaksoft/rony"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+type fakeExecutor struct {
+    mtx      sync.Mutex
+    handler  func(cb client.Callback)
+    canceled []int64
+}
+
+func (e *fakeExecutor) Execute(cb client.Callback) error {
+    go e.handler(cb)
+    return nil
+}
+
+func (e *fakeExecutor) CancelRequest(requestID int64) {
+    e.mtx.Lock()
+    e.canceled = append(e.canceled, requestID)
+    e.mtx.Unlock()
+}
+
+func TestClient(t *testing.T) {
+    Convey("Client", t, func(c C) {
+        e := &fakeExecutor{}
+        cl := client.New(e, client.WithTeam(10, 20))
+
+        Convey("Typed Response", func(c C) {
+            e.handler = func(cb client.Callback) {
+                c.So(cb.TeamID(), ShouldEqual, 10)
+                c.So(cb.TeamAccess(), ShouldEqual, 20)
+                req := &msg.ContactsGet{}
+                c.So(cb.RequestData(req), ShouldBeNil)
+                c.So(req.Crc32Hash, ShouldEqual, 1234)
+                cb.Response(msg.C_ContactsMany, &msg.ContactsMany{
+                    Users: []*msg.User{{ID: 1}, {ID: 2}},
+                })
+            }
+            res, err := cl.GetContacts(context.Background(), &msg.ContactsGet{Crc32Hash: 1234})
+            c.So(err, ShouldBeNil)
+            c.So(res.Users, ShouldHaveLength, 2)
+        })
+        Convey("Error Response", func(c C) {
+            e.handler = func(cb client.Callback) {
+                cb.Response(rony.C_Error, &rony.Error{Code: msg.ErrCodeUnavailable, Items: msg.ErrItemUserID})
+            }
+            res, err := cl.GetUsers(context.Background(), &msg.UsersGet{})
+            c.So(res, ShouldBeNil)
+            c.So(client.IsError(err, msg.ErrCodeUnavailable, msg.ErrItemUserID), ShouldBeTrue)
+            c.So(errors.Is(err, &client.Error{Code: msg.ErrCodeUnavailable}), ShouldBeTrue)
+            c.So(client.IsError(err, msg.ErrCodeInvalid, ""), ShouldBeFalse)
+        })
+        Convey("Unexpected Response", func(c C) {
+            e.handler = func(cb client.Callback) {
+                cb.Response(msg.C_Bool, &msg.Bool{Result: true})
+            }
+            _, err := cl.GetDialogs(context.Background(), &msg.MessagesGetDialogs{})
+            c.So(err, ShouldEqual, client.ErrUnexpectedResponse)
+        })
+        Convey("Raw Response With Flags", func(c C) {
+            e.handler = func(cb client.Callback) {
+                c.So(cb.Flags(), ShouldEqual, client.ServerForced)
+                cb.Response(rony.C_Error, &rony.Error{Code: msg.ErrCodeInvalid})
+            }
+            env, err := cl.With(client.WithFlags(client.ServerForced)).Execute(
+                context.Background(), msg.C_MessagesSend, &msg.MessagesSend{},
+            )
+            c.So(err, ShouldBeNil)
+            c.So(env.Constructor, ShouldEqual, rony.C_Error)
+        })
+        Convey("Timeout", func(c C) {
+            e.handler = func(cb client.Callback) {
+                cb.OnTimeout()
+            }
+            _, err := cl.GetDialogs(context.Background(), &msg.MessagesGetDialogs{})
+            c.So(err, ShouldEqual, client.ErrTimeout)
+        })
+        Convey("Context Canceled", func(c C) {
+            e.handler = func(cb client.Callback) {}
+            ctx, cf := context.WithTimeout(context.Background(), 100*time.Millisecond)
+            defer cf()
+            _, err := cl.With(client.WithTeam(0, 0)).GetDialogs(ctx, &msg.MessagesGetDialogs{})
+            c.So(err, ShouldEqual, context.DeadlineExceeded)
+            e.mtx.Lock()
+            c.So(e.canceled, ShouldHaveLength, 1)
+            e.mtx.Unlock()
+        })
+    })
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+    "errors"
+    "fmt"
+
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/rony"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+var (
+    ErrTimeout            = domain.ErrRequestTimeout
+    ErrUnexpectedResponse = errors.New("unexpected response")
+)
+
+// Error is returned when the server (or a local handler) responds with rony.Error. Callers could check the
+// code and items by errors.Is:
+//      errors.Is(err, &client.Error{Code: msg.ErrCodeUnavailable, Items: msg.ErrItemUserID})
+// Empty Code or Items in the target matches any value.
+type Error struct {
+    Code        string
+    Items       string
+    Description string
+}
+
+func newError(b []byte) *Error {
+    x := &rony.Error{}
+    _ = x.Unmarshal(b)
+    return &Error{
+        Code:        x.Code,
+        Items:       x.Items,
+        Description: x.Description,
+    }
+}
+
+func (e *Error) Error() string {
+    if e.Description != "" {
+        return fmt.Sprintf("%s:%s (%s)", e.Code, e.Items, e.Description)
+    }
+    return fmt.Sprintf("%s:%s", e.Code, e.Items)
+}
+
+func (e *Error) Is(target error) bool {
+    t, ok := target.(*Error)
+    if !ok {
+        return false
+    }
+    return (t.Code == "" || t.Code == e.Code) && (t.Items == "" || t.Items == e.Items)
+}
+
+// IsError returns true if err is an *Error with the code and the item
+func IsError(err error, code, item string) bool {
+    return errors.Is(err, &Error{Code: code, Items: item})
+}
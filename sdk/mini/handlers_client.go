@@ -287,21 +287,16 @@ func (r *River) clientGlobalSearch(da request.Callback) {
         zap.Int64("TeamID", da.TeamID()),
         zap.Any("Text", req.Text),
     )
+    // Results are ranked by minirepo, hence we keep their order
     res := &msg.ClientSearchResult{}
-    uniqueUsers := domain.MInt64B{}
-    uniqueGroups := domain.MInt64B{}
-    cUsers := minirepo.Users.SearchContacts(da.TeamID(), strings.ToLower(req.Text), int(req.Limit))
+    cUsers := minirepo.Users.SearchContacts(da.TeamID(), req.Text, int(req.Limit))
+    userIDs := make([]int64, 0, len(cUsers))
     for _, cu := range cUsers {
-        uniqueUsers[cu.ID] = true
+        userIDs = append(userIDs, cu.ID)
     }
 
-    cGroups := minirepo.Groups.Search(da.TeamID(), strings.ToLower(req.Text), int(req.Limit))
-    for _, g := range cGroups {
-        uniqueGroups[g.ID] = true
-    }
-
-    res.Users, _ = minirepo.Users.ReadMany(uniqueUsers.ToArray()...)
-    res.Groups, _ = minirepo.Groups.ReadMany(da.TeamID(), uniqueGroups.ToArray()...)
+    res.Users, _ = minirepo.Users.ReadMany(userIDs...)
+    res.Groups = minirepo.Groups.Search(da.TeamID(), req.Text, int(req.Limit))
     res.MatchedUsers = append(res.MatchedUsers, res.Users...)
     res.MatchedGroups = append(res.MatchedGroups, res.Groups...)
 
@@ -86,6 +86,10 @@ type River struct {
     messageChan   chan []*rony.MessageEnvelope
     updateChan    chan *msg.UpdateContainer
 
+    // updateAppliers keep minirepo up to date between the full syncs
+    updateAppliers map[int64]updateApplier
+    resyncing      int32
+
     // Internal Controllers
     network *networkCtrl.Controller
 
@@ -118,6 +122,7 @@ func (r *River) SetConfig(conf *RiverConfig) {
     r.dbPath = fmt.Sprintf("%s/%s.db", conf.DbPath, conf.DbID)
 
     r.registerCommandHandlers()
+    r.registerUpdateAppliers()
     r.mainDelegate = conf.MainDelegate
 
     // set log level
@@ -174,8 +179,8 @@ func (r *River) messageReceiver() {
     }
 }
 func (r *River) updateReceiver() {
-    // NOP Loop to just clear the received updates
-    for range r.updateChan {
+    for updateContainer := range r.updateChan {
+        r.applyUpdates(updateContainer)
     }
 }
 
@@ -0,0 +1,406 @@
+package mini
+
+import (
+    "sync/atomic"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/minirepo"
+    "github.com/ronaksoft/rony/registry"
+    "go.uber.org/zap"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+// changes collects what is modified by the appliers of one UpdateContainer, then we notify the delegate once
+type changes struct {
+    teams    map[int64]struct{}
+    dialogs  bool
+    contacts bool
+}
+
+func (c *changes) dialog(teamID int64) {
+    c.dialogs = true
+    c.teams[teamID] = struct{}{}
+}
+
+func (c *changes) contact(teamID int64) {
+    c.contacts = true
+    c.teams[teamID] = struct{}{}
+}
+
+type updateApplier func(c *changes, u *msg.UpdateEnvelope) error
+
+func (r *River) registerUpdateAppliers() {
+    r.updateAppliers = map[int64]updateApplier{
+        msg.C_UpdateNewMessage:        r.updateNewMessage,
+        msg.C_UpdateReadHistoryInbox:  r.updateReadHistoryInbox,
+        msg.C_UpdateReadHistoryOutbox: r.updateReadHistoryOutbox,
+        msg.C_UpdateMessagesDeleted:   r.updateMessagesDeleted,
+        msg.C_UpdateDialogPinned:      r.updateDialogPinned,
+        msg.C_UpdateUsername:          r.updateUsername,
+        msg.C_UpdateUserPhoto:         r.updateUserPhoto,
+        msg.C_UpdateTeamCreated:       r.updateTeamCreated,
+        msg.C_UpdateTeam:              r.updateTeam,
+        msg.C_UpdateTeamPhoto:         r.updateTeamPhoto,
+        msg.C_UpdateTeamMemberAdded:   r.updateTeamMemberAdded,
+        msg.C_UpdateTeamMemberRemoved: r.updateTeamMemberRemoved,
+    }
+}
+
+// applyUpdates applies the updates to minirepo and advances the lastUpdateID. Updates are received in order
+// of their ids, if there is a gap between our lastUpdateID and the container we fall back to the full sync.
+func (r *River) applyUpdates(uc *msg.UpdateContainer) {
+    lastUpdateID := r.getLastUpdateID(0)
+    if lastUpdateID == 0 {
+        // The initial sync has not been finished yet, it fetches everything anyway
+        return
+    }
+    if uc.MinUpdateID != 0 && uc.MinUpdateID > lastUpdateID+1 {
+        logger.Info("MiniRiver is out of sync",
+            zap.Int64("ContainerMinID", uc.MinUpdateID),
+            zap.Int64("ClientUpdateID", lastUpdateID),
+        )
+        go r.resync()
+        return
+    }
+
+    _ = minirepo.Users.SaveUser(uc.Users...)
+    _ = minirepo.Groups.Save(uc.Groups...)
+
+    c := &changes{
+        teams: map[int64]struct{}{},
+    }
+    for _, u := range uc.Updates {
+        if u.UpdateID != 0 && u.UpdateID <= lastUpdateID {
+            // already applied
+            continue
+        }
+        if u.Constructor == msg.C_UpdateTooLong {
+            go r.resync()
+            return
+        }
+        if applier, ok := r.updateAppliers[u.Constructor]; ok {
+            err := applier(c, u)
+            if err != nil {
+                logger.Warn("MiniRiver got error on update applier",
+                    zap.String("C", registry.ConstructorName(u.Constructor)),
+                    zap.Int64("UpdateID", u.UpdateID),
+                    zap.Error(err),
+                )
+            }
+        }
+        if u.UpdateID > lastUpdateID {
+            lastUpdateID = u.UpdateID
+        }
+    }
+    if uc.MaxUpdateID > lastUpdateID {
+        lastUpdateID = uc.MaxUpdateID
+    }
+
+    err := r.setLastUpdateID(0, lastUpdateID)
+    if err != nil {
+        logger.Warn("MiniRiver couldn't save LastUpdateID to the database", zap.Error(err))
+    }
+    for teamID := range c.teams {
+        // Teams which have never been synced, must be synced fully when they are selected
+        if teamID != 0 && r.getLastUpdateID(teamID) != 0 {
+            _ = r.setLastUpdateID(teamID, lastUpdateID)
+        }
+    }
+
+    if r.mainDelegate != nil && (c.dialogs || c.contacts) {
+        r.mainDelegate.DataSynced(c.dialogs, c.contacts, false)
+    }
+}
+
+// resync runs the full sync of the main team and the current team, it is called when we missed some updates
+func (r *River) resync() {
+    if !atomic.CompareAndSwapInt32(&r.resyncing, 0, 1) {
+        return
+    }
+    defer atomic.StoreInt32(&r.resyncing, 0)
+
+    r.syncTeams()
+    r.syncContacts(0, 0)
+    r.syncDialogs(0, 0)
+    if teamID := domain.GetCurrTeamID(); teamID != 0 {
+        r.syncContacts(teamID, domain.GetCurrTeamAccess())
+        r.syncDialogs(teamID, domain.GetCurrTeamAccess())
+    }
+}
+
+func (r *River) updateNewMessage(c *changes, u *msg.UpdateEnvelope) error {
+    x := &msg.UpdateNewMessage{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return err
+    }
+    if x.Message == nil {
+        return nil
+    }
+    if x.Sender != nil {
+        _ = minirepo.Users.SaveUser(x.Sender)
+    }
+
+    m := x.Message
+    dialog, err := minirepo.Dialogs.Read(m.TeamID, m.PeerID, m.PeerType)
+    if err != nil {
+        dialog = &msg.Dialog{
+            TeamID:     m.TeamID,
+            PeerID:     m.PeerID,
+            PeerType:   m.PeerType,
+            AccessHash: x.AccessHash,
+        }
+    }
+    if m.ID > dialog.TopMessageID {
+        dialog.TopMessageID = m.ID
+    }
+    switch {
+    case m.SenderID == r.ConnInfo.UserID:
+        // we have read all the messages if we have sent a message
+        if m.ID > dialog.ReadInboxMaxID {
+            dialog.ReadInboxMaxID = m.ID
+        }
+        dialog.UnreadCount = 0
+        dialog.MentionedCount = 0
+    case m.ID > dialog.ReadInboxMaxID:
+        dialog.UnreadCount++
+    }
+
+    c.dialog(m.TeamID)
+    return minirepo.Dialogs.Save(dialog)
+}
+
+func (r *River) updateReadHistoryInbox(c *changes, u *msg.UpdateEnvelope) error {
+    x := &msg.UpdateReadHistoryInbox{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return err
+    }
+    if x.Peer == nil {
+        return nil
+    }
+
+    dialog, err := minirepo.Dialogs.Read(x.TeamID, x.Peer.ID, x.Peer.Type)
+    if err != nil {
+        return nil
+    }
+    if x.MaxID <= dialog.ReadInboxMaxID {
+        return nil
+    }
+    dialog.ReadInboxMaxID = x.MaxID
+    if x.MaxID >= dialog.TopMessageID {
+        dialog.UnreadCount = 0
+        dialog.MentionedCount = 0
+    }
+
+    c.dialog(x.TeamID)
+    return minirepo.Dialogs.Save(dialog)
+}
+
+func (r *River) updateReadHistoryOutbox(c *changes, u *msg.UpdateEnvelope) error {
+    x := &msg.UpdateReadHistoryOutbox{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return err
+    }
+    if x.Peer == nil {
+        return nil
+    }
+
+    dialog, err := minirepo.Dialogs.Read(x.TeamID, x.Peer.ID, x.Peer.Type)
+    if err != nil {
+        return nil
+    }
+    if x.MaxID <= dialog.ReadOutboxMaxID {
+        return nil
+    }
+    dialog.ReadOutboxMaxID = x.MaxID
+
+    c.dialog(x.TeamID)
+    return minirepo.Dialogs.Save(dialog)
+}
+
+func (r *River) updateMessagesDeleted(c *changes, u *msg.UpdateEnvelope) error {
+    x := &msg.UpdateMessagesDeleted{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return err
+    }
+    if x.Peer == nil {
+        return nil
+    }
+
+    dialog, err := minirepo.Dialogs.Read(x.TeamID, x.Peer.ID, x.Peer.Type)
+    if err != nil {
+        return nil
+    }
+    for _, msgID := range x.MessageIDs {
+        if msgID > dialog.ReadInboxMaxID && msgID <= dialog.TopMessageID && dialog.UnreadCount > 0 {
+            dialog.UnreadCount--
+        }
+    }
+    // MiniRepo does not keep the messages, so if the top message is deleted we keep its id until the
+    // next sync of the dialogs, only the unread count is fixed here.
+
+    c.dialog(x.TeamID)
+    return minirepo.Dialogs.Save(dialog)
+}
+
+func (r *River) updateDialogPinned(c *changes, u *msg.UpdateEnvelope) error {
+    x := &msg.UpdateDialogPinned{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return err
+    }
+    if x.Peer == nil {
+        return nil
+    }
+
+    dialog, err := minirepo.Dialogs.Read(x.TeamID, x.Peer.ID, x.Peer.Type)
+    if err != nil {
+        return nil
+    }
+    dialog.Pinned = x.Pinned
+
+    c.dialog(x.TeamID)
+    return minirepo.Dialogs.Save(dialog)
+}
+
+func (r *River) updateUsername(c *changes, u *msg.UpdateEnvelope) error {
+    x := &msg.UpdateUsername{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return err
+    }
+
+    user, err := minirepo.Users.ReadUser(x.UserID)
+    if err != nil {
+        return nil
+    }
+    user.Username = x.Username
+    user.FirstName = x.FirstName
+    user.LastName = x.LastName
+    user.Bio = x.Bio
+    user.Phone = x.Phone
+
+    c.contact(0)
+    return minirepo.Users.SaveUser(user)
+}
+
+func (r *River) updateUserPhoto(c *changes, u *msg.UpdateEnvelope) error {
+    x := &msg.UpdateUserPhoto{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return err
+    }
+
+    user, err := minirepo.Users.ReadUser(x.UserID)
+    if err != nil {
+        return nil
+    }
+    if x.Photo != nil {
+        user.Photo = x.Photo
+    }
+    for _, photoID := range x.DeletedPhotoIDs {
+        if user.Photo != nil && user.Photo.PhotoID == photoID {
+            user.Photo = nil
+        }
+    }
+
+    c.contact(0)
+    return minirepo.Users.SaveUser(user)
+}
+
+func (r *River) updateTeamCreated(c *changes, u *msg.UpdateEnvelope) error {
+    x := &msg.UpdateTeamCreated{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return err
+    }
+    if x.Team == nil {
+        return nil
+    }
+    return minirepo.Teams.Save(x.Team)
+}
+
+func (r *River) updateTeam(c *changes, u *msg.UpdateEnvelope) error {
+    x := &msg.UpdateTeam{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return err
+    }
+
+    team, err := minirepo.Teams.Read(x.TeamID)
+    if err != nil {
+        return nil
+    }
+    team.Name = x.Name
+    return minirepo.Teams.Save(team)
+}
+
+func (r *River) updateTeamPhoto(c *changes, u *msg.UpdateEnvelope) error {
+    x := &msg.UpdateTeamPhoto{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return err
+    }
+
+    team, err := minirepo.Teams.Read(x.TeamID)
+    if err != nil {
+        return nil
+    }
+    team.Photo = x.Photo
+    return minirepo.Teams.Save(team)
+}
+
+func (r *River) updateTeamMemberAdded(c *changes, u *msg.UpdateEnvelope) error {
+    x := &msg.UpdateTeamMemberAdded{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return err
+    }
+    if x.User == nil || x.Contact == nil {
+        return nil
+    }
+
+    err = minirepo.Users.SaveUser(x.User)
+    if err != nil {
+        return err
+    }
+    err = minirepo.Users.SaveContact(x.TeamID, x.Contact, x.User.LastSeen)
+    if err != nil {
+        return err
+    }
+
+    c.contact(x.TeamID)
+    return r.setContactsHash(x.TeamID, x.Hash)
+}
+
+func (r *River) updateTeamMemberRemoved(c *changes, u *msg.UpdateEnvelope) error {
+    x := &msg.UpdateTeamMemberRemoved{}
+    err := x.Unmarshal(u.Update)
+    if err != nil {
+        return err
+    }
+
+    if x.UserID == r.ConnInfo.UserID {
+        // we have been removed from the team, if we join again it must be synced fully
+        c.contacts = true
+        minirepo.Users.DeleteAllContacts(x.TeamID)
+        _ = r.setLastUpdateID(x.TeamID, 0)
+        return minirepo.Teams.Delete(x.TeamID)
+    }
+
+    c.contact(x.TeamID)
+    minirepo.Users.DeleteContact(x.TeamID, x.UserID)
+    return r.setContactsHash(x.TeamID, x.Hash)
+}
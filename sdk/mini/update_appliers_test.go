@@ -0,0 +1,155 @@
+package mini
+
+import (
+    "testing"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/minirepo"
+    . "github.com/smartystreets/goconvey/convey"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+const testUserID = 100
+
+func init() {
+    minirepo.MustInit("./_hdd")
+}
+
+func newTestRiver() *River {
+    r := &River{
+        ConnInfo: &RiverConnection{UserID: testUserID},
+    }
+    r.registerUpdateAppliers()
+    return r
+}
+
+type testUpdate interface {
+    Marshal() ([]byte, error)
+}
+
+func testContainer(minID int64, constructors []int64, updates ...testUpdate) *msg.UpdateContainer {
+    uc := &msg.UpdateContainer{
+        MinUpdateID: minID,
+        MaxUpdateID: minID + int64(len(updates)) - 1,
+    }
+    for idx, u := range updates {
+        b, _ := u.Marshal()
+        uc.Updates = append(uc.Updates, &msg.UpdateEnvelope{
+            Constructor: constructors[idx],
+            Update:      b,
+            UpdateID:    minID + int64(idx),
+        })
+    }
+    return uc
+}
+
+func newTestMessage(peerID, msgID, senderID int64) *msg.UpdateNewMessage {
+    return &msg.UpdateNewMessage{
+        AccessHash: 1,
+        Message: &msg.UserMessage{
+            ID:       msgID,
+            PeerID:   peerID,
+            PeerType: int32(msg.PeerType_PeerUser),
+            SenderID: senderID,
+            Body:     "Hello",
+        },
+    }
+}
+
+func TestUpdateAppliers(t *testing.T) {
+    Convey("MiniRiver Update Appliers", t, func(c C) {
+        r := newTestRiver()
+        c.So(r.setLastUpdateID(0, 10), ShouldBeNil)
+        peerID := domain.RandomInt63()
+        peer := &msg.Peer{ID: peerID, Type: int32(msg.PeerType_PeerUser)}
+
+        Convey("New Message", func(c C) {
+            r.applyUpdates(testContainer(11,
+                []int64{msg.C_UpdateNewMessage, msg.C_UpdateNewMessage},
+                newTestMessage(peerID, 1, peerID),
+                newTestMessage(peerID, 2, peerID),
+            ))
+            dialog, err := minirepo.Dialogs.Read(0, peerID, int32(msg.PeerType_PeerUser))
+            c.So(err, ShouldBeNil)
+            c.So(dialog.TopMessageID, ShouldEqual, 2)
+            c.So(dialog.UnreadCount, ShouldEqual, 2)
+            c.So(dialog.AccessHash, ShouldEqual, 1)
+            c.So(r.getLastUpdateID(0), ShouldEqual, 12)
+
+            // replayed updates are not applied twice
+            r.applyUpdates(testContainer(12, []int64{msg.C_UpdateNewMessage}, newTestMessage(peerID, 2, peerID)))
+            dialog, _ = minirepo.Dialogs.Read(0, peerID, int32(msg.PeerType_PeerUser))
+            c.So(dialog.UnreadCount, ShouldEqual, 2)
+
+            // we have read the dialog if we send a message
+            r.applyUpdates(testContainer(13, []int64{msg.C_UpdateNewMessage}, newTestMessage(peerID, 3, testUserID)))
+            dialog, _ = minirepo.Dialogs.Read(0, peerID, int32(msg.PeerType_PeerUser))
+            c.So(dialog.TopMessageID, ShouldEqual, 3)
+            c.So(dialog.ReadInboxMaxID, ShouldEqual, 3)
+            c.So(dialog.UnreadCount, ShouldEqual, 0)
+        })
+        Convey("Read History", func(c C) {
+            r.applyUpdates(testContainer(11,
+                []int64{msg.C_UpdateNewMessage, msg.C_UpdateNewMessage, msg.C_UpdateNewMessage},
+                newTestMessage(peerID, 1, peerID),
+                newTestMessage(peerID, 2, peerID),
+                newTestMessage(peerID, 3, peerID),
+            ))
+            r.applyUpdates(testContainer(14,
+                []int64{msg.C_UpdateReadHistoryInbox, msg.C_UpdateReadHistoryOutbox},
+                &msg.UpdateReadHistoryInbox{Peer: peer, MaxID: 3},
+                &msg.UpdateReadHistoryOutbox{Peer: peer, MaxID: 2},
+            ))
+            dialog, err := minirepo.Dialogs.Read(0, peerID, int32(msg.PeerType_PeerUser))
+            c.So(err, ShouldBeNil)
+            c.So(dialog.ReadInboxMaxID, ShouldEqual, 3)
+            c.So(dialog.ReadOutboxMaxID, ShouldEqual, 2)
+            c.So(dialog.UnreadCount, ShouldEqual, 0)
+
+            // older read histories are ignored
+            r.applyUpdates(testContainer(16,
+                []int64{msg.C_UpdateReadHistoryInbox, msg.C_UpdateReadHistoryOutbox},
+                &msg.UpdateReadHistoryInbox{Peer: peer, MaxID: 1},
+                &msg.UpdateReadHistoryOutbox{Peer: peer, MaxID: 1},
+            ))
+            dialog, _ = minirepo.Dialogs.Read(0, peerID, int32(msg.PeerType_PeerUser))
+            c.So(dialog.ReadInboxMaxID, ShouldEqual, 3)
+            c.So(dialog.ReadOutboxMaxID, ShouldEqual, 2)
+        })
+        Convey("Messages Deleted", func(c C) {
+            r.applyUpdates(testContainer(11,
+                []int64{msg.C_UpdateNewMessage, msg.C_UpdateNewMessage, msg.C_UpdateNewMessage, msg.C_UpdateNewMessage},
+                newTestMessage(peerID, 1, peerID),
+                newTestMessage(peerID, 2, testUserID),
+                newTestMessage(peerID, 3, peerID),
+                newTestMessage(peerID, 4, peerID),
+            ))
+            r.applyUpdates(testContainer(15,
+                []int64{msg.C_UpdateMessagesDeleted},
+                &msg.UpdateMessagesDeleted{Peer: peer, MessageIDs: []int64{1, 3}},
+            ))
+            dialog, err := minirepo.Dialogs.Read(0, peerID, int32(msg.PeerType_PeerUser))
+            c.So(err, ShouldBeNil)
+            // only the deleted unread message is subtracted
+            c.So(dialog.UnreadCount, ShouldEqual, 1)
+            c.So(dialog.TopMessageID, ShouldEqual, 4)
+            c.So(r.getLastUpdateID(0), ShouldEqual, 15)
+        })
+        Convey("Out Of Sync", func(c C) {
+            r.resyncing = 1
+            r.applyUpdates(testContainer(20, []int64{msg.C_UpdateNewMessage}, newTestMessage(peerID, 1, peerID)))
+            _, err := minirepo.Dialogs.Read(0, peerID, int32(msg.PeerType_PeerUser))
+            c.So(err, ShouldNotBeNil)
+            c.So(r.getLastUpdateID(0), ShouldEqual, 10)
+        })
+    })
+}
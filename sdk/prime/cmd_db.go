@@ -1,6 +1,7 @@
 package riversdk
 
 import (
+    "github.com/ronaksoft/river-msg/go/msg"
     "github.com/ronaksoft/river-sdk/internal/domain"
     "github.com/ronaksoft/river-sdk/internal/repo"
     "go.uber.org/zap"
@@ -25,7 +26,7 @@ func (r *River) GetRealTopMessageID(peerID int64, peerType int32) int64 {
 }
 
 func (r *River) GetPinnedDialogsCount() int32 {
-    dialogs := repo.Dialogs.GetPinnedDialogs()
+    dialogs := repo.Dialogs.GetPinnedDialogs(domain.GetCurrTeamID())
     return int32(len(dialogs))
 }
 
@@ -33,3 +34,38 @@ func (r *River) ResetCalculatedImportHash() {
     err := repo.System.SaveInt(domain.SkContactsImportHash, 0)
     logger.ErrorOnErr("ResetCalculatedImportHash", err)
 }
+
+// GetPoll returns the serialized UpdateMessagePoll which holds the latest known state of the poll and its results
+func (r *River) GetPoll(pollID int64) []byte {
+    p, err := repo.Polls.Get(pollID)
+    if err != nil {
+        logger.Warn("GetPoll got error on Polls.Get()", zap.Int64("PollID", pollID), zap.Error(err))
+        return nil
+    }
+    b, _ := p.Marshal()
+    return b
+}
+
+// GetCommunityMessages returns the serialized UpdateContainer of the UpdateCommunityMessage of the conversation
+// with the peer, which their GlobalMsgID is less than maxID, newest first.
+func (r *River) GetCommunityMessages(teamID, peerID, maxID int64, limit int32) []byte {
+    messages, err := repo.Community.GetMessages(teamID, peerID, uint64(maxID), int(limit))
+    if err != nil {
+        logger.Warn("GetCommunityMessages got error on Community.GetMessages()", zap.Error(err))
+        return nil
+    }
+    uc := &msg.UpdateContainer{
+        Updates: make([]*msg.UpdateEnvelope, 0, len(messages)),
+    }
+    for _, m := range messages {
+        mBytes, _ := m.Marshal()
+        uc.Updates = append(uc.Updates, &msg.UpdateEnvelope{
+            Constructor: msg.C_UpdateCommunityMessage,
+            Update:      mBytes,
+            Timestamp:   m.CreatedOn,
+        })
+    }
+    uc.Length = int32(len(uc.Updates))
+    b, _ := uc.Marshal()
+    return b
+}
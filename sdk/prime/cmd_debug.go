@@ -3,6 +3,7 @@ package riversdk
 import (
     "github.com/ronaksoft/river-msg/go/msg"
     "github.com/ronaksoft/river-sdk/internal/domain"
+    "github.com/ronaksoft/river-sdk/internal/hole"
     "github.com/ronaksoft/river-sdk/internal/repo"
     "github.com/ronaksoft/river-sdk/internal/request"
     "github.com/ronaksoft/river-sdk/module"
@@ -40,7 +41,7 @@ func (r *River) HandleDebugActions(txt string) {
 }
 
 func (r *River) GetHole(peerID int64, peerType int32) []byte {
-    return repo.MessagesExtra.GetHoles(domain.GetCurrTeamID(), peerID, peerType, 0)
+    return hole.Dump(domain.GetCurrTeamID(), peerID, peerType, 0)
 }
 
 func (r *River) CancelFileRequest(reqID string) {
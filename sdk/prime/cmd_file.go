@@ -5,6 +5,8 @@ import (
 
     "github.com/dgraph-io/badger/v2"
     "github.com/ronaksoft/river-msg/go/msg"
+    fileCtrl "github.com/ronaksoft/river-sdk/internal/ctrl_file"
+    "github.com/ronaksoft/river-sdk/internal/domain"
     "github.com/ronaksoft/river-sdk/internal/repo"
     "github.com/ronaksoft/river-sdk/internal/request"
     "go.uber.org/zap"
@@ -21,6 +23,9 @@ func (r *River) GetFileStatus(clusterID int32, fileID int64, accessHash int64) [
                 fileStatus.Progress = int64(float64(len(uploadRequest.FinishedParts)) / float64(uploadRequest.TotalParts) * 100)
             }
             fileStatus.Status = int32(request.StatusInProgress)
+            if r.fileCtrl.IsPaused(r.fileCtrl.GetUploadRequestID(fileID)) {
+                fileStatus.Status = int32(request.StatusPaused)
+            }
         } else {
             fileStatus.Status = int32(request.StatusNone)
             fileStatus.Progress = 0
@@ -30,6 +35,9 @@ func (r *River) GetFileStatus(clusterID int32, fileID int64, accessHash int64) [
         if downloadRequest != nil {
             fileStatus.FilePath = downloadRequest.FilePath
             fileStatus.Status = int32(request.StatusInProgress)
+            if r.fileCtrl.IsPaused(r.fileCtrl.GetDownloadRequestID(clusterID, fileID, uint64(accessHash))) {
+                fileStatus.Status = int32(request.StatusPaused)
+            }
             if downloadRequest.TotalParts > 0 {
                 fileStatus.Progress = int64(float64(len(downloadRequest.FinishedParts)) / float64(downloadRequest.TotalParts) * 100)
             }
@@ -39,6 +47,10 @@ func (r *River) GetFileStatus(clusterID int32, fileID int64, accessHash int64) [
                 filePath := repo.Files.GetFilePath(clientFile)
                 if _, err = os.Stat(filePath); os.IsNotExist(err) {
                     fileStatus.FilePath = ""
+                    integrity := repo.Files.GetIntegrity(clusterID, fileID, uint64(accessHash))
+                    if integrity.Status == repo.FileIntegrityCorrupted {
+                        fileStatus.Status = int32(request.StatusCorrupted)
+                    }
                 } else {
                     fileStatus.FilePath = filePath
                     fileStatus.Progress = 100
@@ -104,6 +116,72 @@ func (r *River) FileDownloadSync(clusterID int32, fileID int64, accessHash int64
     return err
 }
 
+// FileAutoDownload must be called for the downloads which are not explicitly requested by the user (e.g. media
+// of the messages which are shown in the chat). The file is downloaded only if the auto download rules of the
+// current network profile allow it, otherwise an empty reqID is returned.
+func (r *River) FileAutoDownload(clusterID int32, fileID int64, accessHash int64) (reqID string) {
+    reqID, err := r.fileCtrl.AutoDownloadAsync(clusterID, fileID, uint64(accessHash), false)
+    switch err {
+    case nil, domain.ErrAutoDownloadDisabled:
+    default:
+        logger.Warn("Error On AutoDownload",
+            zap.Int32("ClusterID", clusterID),
+            zap.Int64("FileID", fileID),
+            zap.Int64("AccessHash", accessHash),
+            zap.Error(err),
+        )
+    }
+    return
+}
+
+// SetNetworkType must be called whenever the device connects to another network.
+// networkType: 0 (Wifi), 1 (Cellular), 2 (Roaming)
+func (r *River) SetNetworkType(networkType int32) error {
+    err := r.fileCtrl.SetNetworkType(fileCtrl.NetworkType(networkType))
+    logger.WarnOnErr("got error on SetNetworkType", err)
+    return err
+}
+
+// SetNetworkBandwidth sets the upload and download rates (bytes per second) of the network type profile.
+// Zero rate means unlimited. If the device is on this network type, the rates are applied immediately.
+func (r *River) SetNetworkBandwidth(networkType int32, uploadRate, downloadRate int64) error {
+    p := r.fileCtrl.GetNetworkProfile(fileCtrl.NetworkType(networkType))
+    p.UploadRate = uploadRate
+    p.DownloadRate = downloadRate
+    err := r.fileCtrl.SetNetworkProfile(fileCtrl.NetworkType(networkType), p)
+    logger.WarnOnErr("got error on SetNetworkBandwidth", err)
+    return err
+}
+
+// SetAutoDownloadRule sets the auto download rule of the media type (msg.ClientMediaType) in the network type
+// profile. maxSize is in bytes and zero means there is no limit on the file size.
+func (r *River) SetAutoDownloadRule(networkType int32, mediaType int32, enabled bool, maxSize int64) error {
+    p := r.fileCtrl.GetNetworkProfile(fileCtrl.NetworkType(networkType))
+    p.AutoDownload[msg.ClientMediaType(mediaType)] = fileCtrl.AutoDownloadRule{
+        Enabled: enabled,
+        MaxSize: maxSize,
+    }
+    err := r.fileCtrl.SetNetworkProfile(fileCtrl.NetworkType(networkType), p)
+    logger.WarnOnErr("got error on SetAutoDownloadRule", err)
+    return err
+}
+
+// FileStreamURL returns a local http url which serves the file while it is being downloaded. The url supports
+// Range requests, so it could be passed to media players. The requested ranges are downloaded first.
+func (r *River) FileStreamURL(clusterID int32, fileID int64, accessHash int64) string {
+    url, err := r.fileCtrl.StreamURL(clusterID, fileID, uint64(accessHash))
+    if err != nil {
+        logger.Warn("Error On FileStreamURL",
+            zap.Int32("ClusterID", clusterID),
+            zap.Int64("FileID", fileID),
+            zap.Int64("AccessHash", accessHash),
+            zap.Error(err),
+        )
+        return ""
+    }
+    return url
+}
+
 // CancelDownload cancel download
 func (r *River) CancelDownload(clusterID int32, fileID int64, accessHash int64) {
     clientFile, err := repo.Files.Get(clusterID, fileID, uint64(accessHash))
@@ -116,6 +194,35 @@ func (r *River) CancelDownload(clusterID int32, fileID int64, accessHash int64)
     }
 }
 
+// PauseFileRequest pauses the upload/download request identified by reqID. The progress is kept and
+// the request could be continued later by calling ResumeFileRequest, even after the app restarts.
+func (r *River) PauseFileRequest(reqID string) error {
+    err := r.fileCtrl.PauseRequest(reqID)
+    logger.WarnOnErr("got error on PauseFileRequest", err, zap.String("ReqID", reqID))
+    return err
+}
+
+// ResumeFileRequest continues the paused upload/download request identified by reqID.
+func (r *River) ResumeFileRequest(reqID string) error {
+    err := r.fileCtrl.ResumeRequest(reqID)
+    logger.WarnOnErr("got error on ResumeFileRequest", err, zap.String("ReqID", reqID))
+    return err
+}
+
+// SetFileRequestPriority changes the priority of the upload/download request identified by reqID. Priority
+// must be between 0 (lowest) and 255 (highest), the default priority of the requests is 128.
+func (r *River) SetFileRequestPriority(reqID string, priority int32) error {
+    switch {
+    case priority < 0:
+        priority = 0
+    case priority > 255:
+        priority = 255
+    }
+    err := r.fileCtrl.SetRequestPriority(reqID, uint8(priority))
+    logger.WarnOnErr("got error on SetFileRequestPriority", err, zap.String("ReqID", reqID))
+    return err
+}
+
 // ResumeUpload must be called if for any reason the upload of a ClientSendMediaMessage failed,
 // then client should call this function by providing the pending message id, or if delete the pending
 // message.
@@ -13,7 +13,6 @@ import (
     "github.com/monnand/dhkx"
     "github.com/ronaksoft/river-msg/go/msg"
     "github.com/ronaksoft/river-sdk/internal/domain"
-    "github.com/ronaksoft/river-sdk/internal/hole"
     "github.com/ronaksoft/river-sdk/internal/logs"
     mon "github.com/ronaksoft/river-sdk/internal/monitoring"
     "github.com/ronaksoft/river-sdk/internal/repo"
@@ -571,9 +570,6 @@ func (r *River) AppStart() error {
     logs.SetSentry(r.ConnInfo.AuthID, r.ConnInfo.UserID, r.sentryDSN)
     logger.Info("Starting")
 
-    // Initialize MessageHole
-    hole.Init()
-
     // Initialize DB replaced with ORM
     err := repo.Init(r.dbPath, r.optimizeForLowMemory)
     if err != nil {
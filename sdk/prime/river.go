@@ -24,7 +24,9 @@ import (
     "github.com/ronaksoft/river-sdk/module/account"
     "github.com/ronaksoft/river-sdk/module/auth"
     "github.com/ronaksoft/river-sdk/module/bot"
+    "github.com/ronaksoft/river-sdk/module/calendar"
     "github.com/ronaksoft/river-sdk/module/call"
+    "github.com/ronaksoft/river-sdk/module/community"
     "github.com/ronaksoft/river-sdk/module/contact"
     "github.com/ronaksoft/river-sdk/module/gif"
     "github.com/ronaksoft/river-sdk/module/group"
@@ -93,6 +95,15 @@ type RiverConfig struct {
     OptimizeForLowMemory bool
     MaxInFlightDownloads int32
     MaxInFlightUploads   int32
+    // UploadWindow is the number of parts of each upload which are sent in parallel
+    UploadWindow int32
+    // TypingTimeoutSec is the number of seconds after which a typing action of a user expires, if the user neither
+    // repeats it nor cancels it. If it is not set the default is used.
+    TypingTimeoutSec int32
+    // InlineResultsCacheSec is the number of seconds which the results of the inline queries of bots are cached.
+    // The server does not tell how long the results are valid, hence it is decided by the client. If it is not set
+    // the default is used.
+    InlineResultsCacheSec int32
 
     // Misc
     ResetQueueOnStartup bool
@@ -162,6 +173,15 @@ func (r *River) Module(name string) module.Module {
     return r.modules[name]
 }
 
+// SetBotDelegate sets the delegate which receives the callback and inline queries, when the account is a bot
+func (r *River) SetBotDelegate(d bot.Delegate) {
+    m, ok := r.Module(module.Bot).(interface{ SetDelegate(d bot.Delegate) })
+    if !ok {
+        return
+    }
+    m.SetDelegate(d)
+}
+
 // SetConfig must be called before any other function, otherwise it panics
 func (r *River) SetConfig(conf *RiverConfig) {
     domain.ClientPlatform = conf.ClientPlatform
@@ -203,6 +223,7 @@ func (r *River) SetConfig(conf *RiverConfig) {
     r.localCommands = map[int64]request.LocalHandler{}
     r.realTimeCommands = map[int64]bool{
         msg.C_MessagesSetTyping:   true,
+        msg.C_CommunitySetTyping:  true,
         msg.C_InitConnect:         true,
         msg.C_InitConnectTest:     true,
         msg.C_InitAuthCompleted:   true,
@@ -248,6 +269,7 @@ func (r *River) SetConfig(conf *RiverConfig) {
         DbPath:               r.dbPath,
         MaxInflightDownloads: conf.MaxInFlightDownloads,
         MaxInflightUploads:   conf.MaxInFlightUploads,
+        UploadWindow:         conf.UploadWindow,
         CompletedCB:          r.fileDelegate.OnCompleted,
         ProgressChangedCB:    r.fileDelegate.OnProgressChanged,
         CancelCB:             r.fileDelegate.OnCancel,
@@ -305,11 +327,17 @@ func (r *River) SetConfig(conf *RiverConfig) {
     })
 
     r.registerModule(
-        account.New(), auth.New(), bot.New(), contact.New(),
+        account.New(), auth.New(), bot.New(), calendar.New(), community.New(), contact.New(),
         gif.New(), group.New(), label.New(), message.New(),
         search.New(), system.New(), team.New(), user.New(), wallpaper.New(),
         callModule, notification.New(),
     )
+    if m, ok := r.Module(module.Message).(interface{ SetTypingTimeout(d time.Duration) }); ok {
+        m.SetTypingTimeout(time.Duration(conf.TypingTimeoutSec) * time.Second)
+    }
+    if m, ok := r.Module(module.Bot).(interface{ SetInlineResultsTTL(d time.Duration) }); ok {
+        m.SetInlineResultsTTL(time.Duration(conf.InlineResultsCacheSec) * time.Second)
+    }
 
     // Initialize River Connection
     logger.Info("SetConfig done!")
@@ -394,6 +422,14 @@ func (r *River) onNetworkConnect() (err error) {
         }
         atomic.CompareAndSwapInt32(&domain.ContactsSynced, 0, 1)
 
+        // Fill the gaps of the community teams
+        if cm, ok := r.Module(module.Community).(interface{ Sync(int64, uint64) }); ok {
+            for _, team := range repo.Teams.List() {
+                if team.Community {
+                    cm.Sync(team.ID, team.AccessHash)
+                }
+            }
+        }
     }()
     return nil
 }
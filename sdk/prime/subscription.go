@@ -0,0 +1,35 @@
+package riversdk
+
+import (
+    "context"
+
+    "github.com/ronaksoft/river-msg/go/msg"
+    "github.com/ronaksoft/river-sdk/internal/uiexec"
+)
+
+/*
+   Creation Time: 2026 - Oct - 19
+   Created by:  (agent)
+   Maintainers:
+      1.  agent
+   Auditor: agent
+   Copyright Ronak Software Group 2026
+*/
+
+type UpdateFilter = uiexec.Filter
+type OverflowPolicy = uiexec.OverflowPolicy
+
+// Overflow Policies
+// These are exact copies of uiexec overflow policies
+const (
+    OverflowDropNewest = uiexec.OverflowDropNewest
+    OverflowDropOldest = uiexec.OverflowDropOldest
+    OverflowClose      = uiexec.OverflowClose
+)
+
+// Subscribe is the Go-native alternative of MainDelegate.OnUpdates. The returned channel receives the decoded
+// updates which match the filter, the same updates which are passed to OnUpdates. The channel is closed when ctx
+// is done. If the subscriber is slower than the updates, the policy decides which updates are dropped.
+func (r *River) Subscribe(ctx context.Context, f UpdateFilter, bufferSize int, policy OverflowPolicy) <-chan *msg.UpdateEnvelope {
+    return uiexec.Subscribe(ctx, f, bufferSize, policy)
+}